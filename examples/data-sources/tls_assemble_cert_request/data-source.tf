@@ -0,0 +1,20 @@
+resource "tls_cert_request" "example" {
+  subject_public_key_pem = file("public_key.pem")
+
+  subject {
+    common_name = "example.com"
+  }
+}
+
+# The content of `tbs.sig` is produced by signing
+# `tls_cert_request.example.cert_request_tbs_der_base64`
+# with the private key, outside of Terraform
+data "tls_assemble_cert_request" "example" {
+  cert_request_tbs_der_base64 = tls_cert_request.example.cert_request_tbs_der_base64
+  signature_base64            = filebase64("tbs.sig")
+  signature_algorithm         = "ECDSA-SHA256"
+}
+
+output "cert_request" {
+  value = data.tls_assemble_cert_request.example.cert_request_pem
+}
@@ -0,0 +1,10 @@
+data "tls_assemble_certificate" "example" {
+  tbs_certificate_base64 = filebase64("tbs.der")
+  signature_base64       = filebase64("tbs.sig")
+  signature_algorithm    = "ECDSA-SHA256"
+  ca_cert_pem            = file("ca.pem")
+}
+
+output "certificate" {
+  value = data.tls_assemble_certificate.example.cert_pem
+}
@@ -0,0 +1,13 @@
+data "tls_ca_bundle" "example" {
+  certificate_pem = [
+    tls_self_signed_cert.root_ca.cert_pem,
+    file("intermediate_ca.pem"),
+  ]
+
+  deduplicate = true
+}
+
+resource "local_file" "ca_bundle" {
+  content  = data.tls_ca_bundle.example.bundle_pem
+  filename = "ca_bundle.pem"
+}
@@ -0,0 +1,8 @@
+data "tls_compare_certificates" "example" {
+  first_certificate_pem  = file("old.pem")
+  second_certificate_pem = file("new.pem")
+}
+
+output "certificate_differences" {
+  value = data.tls_compare_certificates.example.differences
+}
@@ -0,0 +1,9 @@
+data "tls_convert_key" "example" {
+  input_key     = file("id_rsa")
+  target_format = "PEM"
+}
+
+resource "local_sensitive_file" "private_key_pem" {
+  content  = data.tls_convert_key.example.private_key
+  filename = "private_key.pem"
+}
@@ -0,0 +1,8 @@
+data "tls_crl" "example" {
+  url     = "http://crl.example.com/root.crl"
+  timeout = 10
+}
+
+output "revoked_serial_numbers" {
+  value = data.tls_crl.example.revoked_certificates[*].serial_number
+}
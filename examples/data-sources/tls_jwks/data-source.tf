@@ -0,0 +1,22 @@
+resource "tls_private_key" "current" {
+  algorithm   = "ECDSA"
+  ecdsa_curve = "P256"
+}
+
+resource "tls_private_key" "next" {
+  algorithm   = "ECDSA"
+  ecdsa_curve = "P256"
+}
+
+data "tls_jwks" "example" {
+  public_keys = [
+    tls_private_key.current.public_key_pem,
+    tls_private_key.next.public_key_pem,
+  ]
+  use = "sig"
+}
+
+resource "local_file" "jwks_json" {
+  content  = data.tls_jwks.example.jwks
+  filename = "jwks.json"
+}
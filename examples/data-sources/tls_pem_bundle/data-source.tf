@@ -0,0 +1,10 @@
+data "tls_pem_bundle" "example" {
+  private_key_pem = tls_private_key.example.private_key_pem
+  cert_pem        = tls_locally_signed_cert.example.cert_pem
+  chain_pem       = file("intermediate_ca.pem")
+}
+
+resource "local_sensitive_file" "haproxy_pem" {
+  content  = data.tls_pem_bundle.example.bundle_pem
+  filename = "haproxy.pem"
+}
@@ -0,0 +1,11 @@
+data "tls_pkcs7_sign" "example" {
+  content_base64  = filebase64("firmware.bin")
+  certificate_pem = file("signer.pem")
+  private_key_pem = file("signer-key.pem")
+  detached        = true
+}
+
+resource "local_file" "signature" {
+  content_base64 = data.tls_pkcs7_sign.example.pkcs7_base64
+  filename       = "firmware.bin.p7s"
+}
@@ -0,0 +1,10 @@
+data "tls_ssh_key_fingerprint" "example" {
+  public_key_openssh = file("~/.ssh/id_ed25519.pub")
+}
+
+output "fingerprints" {
+  value = {
+    sha256 = data.tls_ssh_key_fingerprint.example.fingerprint_sha256
+    md5    = data.tls_ssh_key_fingerprint.example.fingerprint_md5
+  }
+}
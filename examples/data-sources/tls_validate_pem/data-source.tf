@@ -0,0 +1,8 @@
+data "tls_validate_pem" "example" {
+  content = file("certificate.pem")
+  type    = "certificate"
+}
+
+output "certificate_diagnostics" {
+  value = data.tls_validate_pem.example.diagnostics
+}
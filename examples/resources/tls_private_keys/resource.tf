@@ -0,0 +1,12 @@
+# 3 ECDSA keys with P256 elliptic curve, to rotate among
+resource "tls_private_keys" "ecdsa-p256-example" {
+  key_count   = 3
+  algorithm   = "ECDSA"
+  ecdsa_curve = "P256"
+}
+
+# 2 ED25519 keys
+resource "tls_private_keys" "ed25519-example" {
+  key_count = 2
+  algorithm = "ED25519"
+}
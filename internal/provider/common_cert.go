@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/ecdsa"
@@ -9,15 +10,28 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
+	"net"
+	"net/url"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -53,13 +67,95 @@ var extendedKeyUsages = map[string]x509.ExtKeyUsage{
 	"microsoft_kernel_code_signing":     x509.ExtKeyUsageMicrosoftKernelCodeSigning,
 }
 
+// certificateExtensions maps the names accepted by `extension_order`
+// to the OIDs of the extensions that can be emitted in a certificate.
+var certificateExtensions = map[string]asn1.ObjectIdentifier{
+	"admission":               oidExtensionAdmission,
+	"authority_info_access":   {1, 3, 6, 1, 5, 5, 7, 1, 1},
+	"authority_key_id":        oidExtensionAuthorityKeyID,
+	"basic_constraints":       oidExtensionBasicConstraints,
+	"certificate_policies":    oidExtensionCertificatePolicies,
+	"crl_distribution_points": {2, 5, 29, 31},
+	"extended_key_usage":      {2, 5, 29, 37},
+	"freshest_crl":            oidExtensionFreshestCRL,
+	"key_usage":               {2, 5, 29, 15},
+	"logotype":                oidExtensionLogotype,
+	"name_constraints":        {2, 5, 29, 30},
+	"sct_list":                oidExtensionSCTList,
+	"smime_capabilities":      oidExtensionSMIMECapabilities,
+	"subject_alt_name":        oidExtensionSubjectAltName,
+	"subject_info_access":     oidExtensionSubjectInfoAccess,
+	"subject_key_id":          {2, 5, 29, 14},
+}
+
+// subjectAttributeTypes maps the names accepted by `subject_rdn_order`
+// to the OIDs of the attribute types of the subject distinguished name.
+var subjectAttributeTypes = map[string]asn1.ObjectIdentifier{
+	"common_name":         {2, 5, 4, 3},
+	"country":             {2, 5, 4, 6},
+	"locality":            {2, 5, 4, 7},
+	"organization":        {2, 5, 4, 10},
+	"organizational_unit": {2, 5, 4, 11},
+	"postal_code":         {2, 5, 4, 17},
+	"province":            {2, 5, 4, 8},
+	"serial_number":       {2, 5, 4, 5},
+	"street_address":      {2, 5, 4, 9},
+}
+
+var signatureAlgorithms = map[string]x509.SignatureAlgorithm{
+	"SHA256-RSA":    x509.SHA256WithRSA,
+	"SHA384-RSA":    x509.SHA384WithRSA,
+	"SHA512-RSA":    x509.SHA512WithRSA,
+	"SHA256-RSAPSS": x509.SHA256WithRSAPSS,
+	"SHA384-RSAPSS": x509.SHA384WithRSAPSS,
+	"SHA512-RSAPSS": x509.SHA512WithRSAPSS,
+	"ECDSA-SHA256":  x509.ECDSAWithSHA256,
+	"ECDSA-SHA384":  x509.ECDSAWithSHA384,
+	"ECDSA-SHA512":  x509.ECDSAWithSHA512,
+	"Ed25519":       x509.PureEd25519,
+}
+
+// signatureAlgorithmKeyAlgorithms maps each of the signatureAlgorithms to the Algorithm of the keys it can be used with.
+//
+// NOTE: the hash is independent of the size of the key: for example, an `ECDSA` key with curve `P256`
+// can sign with `ECDSA-SHA384`, even if `crypto/x509` defaults to `ECDSA-SHA256` for it.
+var signatureAlgorithmKeyAlgorithms = map[x509.SignatureAlgorithm]Algorithm{
+	x509.SHA256WithRSA:    RSA,
+	x509.SHA384WithRSA:    RSA,
+	x509.SHA512WithRSA:    RSA,
+	x509.SHA256WithRSAPSS: RSA,
+	x509.SHA384WithRSAPSS: RSA,
+	x509.SHA512WithRSAPSS: RSA,
+	x509.ECDSAWithSHA256:  ECDSA,
+	x509.ECDSAWithSHA384:  ECDSA,
+	x509.ECDSAWithSHA512:  ECDSA,
+	x509.PureEd25519:      ED25519,
+}
+
 // supportedKeyUsages returns a slice with all the keys in keyUsages and extendedKeyUsages.
 func supportedKeyUsages() []string {
-	res := make([]string, 0, len(keyUsages)+len(extendedKeyUsages))
+	res := append(supportedBasicKeyUsages(), supportedExtendedKeyUsages()...)
+	sort.Strings(res)
+
+	return res
+}
+
+// supportedBasicKeyUsages returns a slice with all the keys in keyUsages.
+func supportedBasicKeyUsages() []string {
+	res := make([]string, 0, len(keyUsages))
 
 	for k := range keyUsages {
 		res = append(res, k)
 	}
+	sort.Strings(res)
+
+	return res
+}
+
+// supportedExtendedKeyUsages returns a slice with all the keys in extendedKeyUsages.
+func supportedExtendedKeyUsages() []string {
+	res := make([]string, 0, len(extendedKeyUsages))
+
 	for k := range extendedKeyUsages {
 		res = append(res, k)
 	}
@@ -68,6 +164,93 @@ func supportedKeyUsages() []string {
 	return res
 }
 
+// certificateUsages returns the sorted names, as in keyUsages and extendedKeyUsages,
+// of the usages set on the given certificate.
+func certificateUsages(cert *x509.Certificate) []string {
+	var res []string
+
+	for name, usage := range keyUsages {
+		if cert.KeyUsage&usage != 0 {
+			res = append(res, name)
+		}
+	}
+	for name, usage := range extendedKeyUsages {
+		for _, extUsage := range cert.ExtKeyUsage {
+			if extUsage == usage {
+				res = append(res, name)
+				break
+			}
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// supportedSignatureAlgorithms returns a slice with all the keys in signatureAlgorithms.
+func supportedSignatureAlgorithms() []string {
+	res := make([]string, 0, len(signatureAlgorithms))
+
+	for k := range signatureAlgorithms {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// checkSignatureAlgorithmForKey returns an error if the given `signature_algorithm`
+// cannot be used to sign with the given crypto.PrivateKey.
+func checkSignatureAlgorithmForKey(sigAlg string, prvKey crypto.PrivateKey) error {
+	keyAlgorithm, err := privateKeyToAlgorithm(prvKey)
+	if err != nil {
+		return err
+	}
+
+	if signatureAlgorithmKeyAlgorithms[signatureAlgorithms[sigAlg]] == keyAlgorithm {
+		return nil
+	}
+
+	var accepted []string
+	for _, alg := range supportedSignatureAlgorithms() {
+		if signatureAlgorithmKeyAlgorithms[signatureAlgorithms[alg]] == keyAlgorithm {
+			accepted = append(accepted, alg)
+		}
+	}
+	return fmt.Errorf("signature_algorithm %s cannot be used with %s keys: accepted values are %s", sigAlg, keyAlgorithm, strings.Join(accepted, ", "))
+}
+
+// supportedCertificateExtensions returns a slice with all the keys in certificateExtensions.
+func supportedCertificateExtensions() []string {
+	res := make([]string, 0, len(certificateExtensions))
+
+	for k := range certificateExtensions {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// signatureAlgorithmSchema returns the schema.Schema for the `signature_algorithm` attribute,
+// with the given description of what is being signed.
+func signatureAlgorithmSchema(signed string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedSignatureAlgorithms(), false)),
+		Description: fmt.Sprintf("Algorithm used to sign the %s. ", signed) +
+			"Accepted values are `SHA256-RSA`, `SHA384-RSA`, `SHA512-RSA`, " +
+			"`SHA256-RSAPSS`, `SHA384-RSAPSS`, `SHA512-RSAPSS` (RSA-PSS), " +
+			"`ECDSA-SHA256`, `ECDSA-SHA384`, `ECDSA-SHA512` and `Ed25519`, " +
+			"and must be compatible with the algorithm of the signing key. " +
+			"The hash is independent of the size of the key: for example, an `ECDSA` key with curve `P256` " +
+			"can sign with `ECDSA-SHA384`. " +
+			"If not set, a default appropriate for the signing key is used.",
+	}
+}
+
 // generateSubjectKeyID generates a SHA-1 hash of the subject public key.
 func generateSubjectKeyID(pubKey crypto.PublicKey) ([]byte, error) {
 	var pubKeyBytes []byte
@@ -104,274 +287,2099 @@ func generateSubjectKeyID(pubKey crypto.PublicKey) ([]byte, error) {
 	return pubKeyHash[:], nil
 }
 
-// setCertificateSubjectSchema sets on the given reference to map of schema.Schema
-// all the keys required by a resource representing a certificate's subject.
-func setCertificateSubjectSchema(s map[string]*schema.Schema) {
-	s["dns_names"] = &schema.Schema{
-		Type:     schema.TypeList,
-		Optional: true,
-		ForceNew: true,
-		Elem: &schema.Schema{
-			Type: schema.TypeString,
-		},
-		Description: "List of DNS names for which a certificate is being requested (i.e. certificate subjects).",
+// parseCertificateSerialHex parses a certificate serial number from its hexadecimal representation.
+//
+// The serial number must be a positive integer, and its DER encoding must not exceed 20 octets
+// (see https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.2).
+func parseCertificateSerialHex(serialHex string) (*big.Int, error) {
+	serial, ok := new(big.Int).SetString(serialHex, 16)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid hexadecimal number", serialHex)
 	}
 
-	s["ip_addresses"] = &schema.Schema{
-		Type:     schema.TypeList,
-		Optional: true,
-		ForceNew: true,
-		Elem: &schema.Schema{
-			Type: schema.TypeString,
-		},
-		Description: "List of IP addresses for which a certificate is being requested (i.e. certificate subjects).",
+	if serial.Sign() <= 0 {
+		return nil, fmt.Errorf("serial number must be positive, got %q", serialHex)
 	}
 
-	s["uris"] = &schema.Schema{
-		Type:     schema.TypeList,
-		Optional: true,
-		ForceNew: true,
-		Elem: &schema.Schema{
-			Type: schema.TypeString,
-		},
-		Description: "List of URIs for which a certificate is being requested (i.e. certificate subjects).",
+	// NOTE: DER encodes integers as two's complement, so a leading byte with the high bit set
+	// requires an extra 0x00 padding byte: this counts towards the 20 octets limit.
+	if serialBytes := serial.Bytes(); len(serialBytes) > 20 || (len(serialBytes) == 20 && serialBytes[0]&0x80 != 0) {
+		return nil, fmt.Errorf("serial number must not exceed 20 octets, got %q", serialHex)
 	}
 
-	s["key_algorithm"] = &schema.Schema{
-		Type:       schema.TypeString,
-		Optional:   true,
-		Computed:   true,
-		ForceNew:   true,
-		Deprecated: "This is now ignored, as the key algorithm is inferred from the `private_key_pem`.",
-		Description: "Name of the algorithm used when generating the private key provided in `private_key_pem`. " +
-			"**NOTE**: this is deprecated and ignored, as the key algorithm is now inferred from the key. ",
+	return serial, nil
+}
+
+// deterministicSerialNumber derives a 128 bit serial number from the SHA256 hash of the TBSCertificate
+// that the given template produces with a placeholder serial number, so that certificates with identical
+// content have identical serial numbers, while certificates with different content do not.
+func deterministicSerialNumber(template, parent *x509.Certificate, pub crypto.PublicKey, prv interface{}) (*big.Int, error) {
+	placeholder := *template
+	placeholder.SerialNumber = big.NewInt(1)
+
+	certBytes, err := x509.CreateCertificate(nil, &placeholder, parent, pub, prv)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, err
 	}
 
-	s["private_key_pem"] = &schema.Schema{
-		Type:      schema.TypeString,
-		Required:  true,
-		ForceNew:  true,
-		Sensitive: true,
-		StateFunc: func(v interface{}) string {
-			return hashForState(v.(string))
-		},
-		Description: "Private key in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
-			"that the certificate will belong to. " +
-			"This can be read from a separate file using the [`file`](https://www.terraform.io/language/functions/file) " +
-			"interpolation function. " +
-			"Only an irreversible secure hash of the private key will be stored in the Terraform state.",
+	hash := sha256.Sum256(cert.RawTBSCertificate)
+	return new(big.Int).SetBytes(hash[:16]), nil
+}
+
+// serialNumberFileMutex serializes the access to serial number files within this process,
+// as multiple certificates can be created concurrently from the same CA.
+var serialNumberFileMutex sync.Mutex
+
+// nextSerialNumberFromFile returns the serial number following the one stored in the given file,
+// and stores it back in the file, in hexadecimal (like the `-CAserial` file of `openssl x509`).
+//
+// If the file doesn't exist, it's created and the first serial number returned is 1.
+// The file is replaced atomically, so that a crash while writing it can't leave it empty or truncated.
+//
+// The whole read-increment-write is done holding an exclusive lock on the file `<path>.lock`,
+// so that concurrent `terraform apply` sharing the same file never hand out the same serial number.
+func nextSerialNumberFromFile(path string) (*big.Int, error) {
+	serialNumberFileMutex.Lock()
+	defer serialNumberFileMutex.Unlock()
+
+	// GOTCHA: The lock can't be taken on the serial number file itself,
+	// as replacing it atomically gives it a new inode every time.
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open serial number lock file: %w", err)
 	}
+	defer lock.Close()
 
-	s["subject"] = &schema.Schema{
-		Type:     schema.TypeList,
-		Required: true,
-		ForceNew: true,
-		Elem: &schema.Resource{
-			Schema: map[string]*schema.Schema{
-				"organization": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `O`",
-				},
-				"common_name": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `CN`",
-				},
-				"organizational_unit": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `OU`",
-				},
-				"street_address": {
-					Type:     schema.TypeList,
-					Optional: true,
-					Elem: &schema.Schema{
-						Type: schema.TypeString,
-					},
-					ForceNew:    true,
-					Description: "Distinguished name: `STREET`",
-				},
-				"locality": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `L`",
-				},
-				"province": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `ST`",
-				},
-				"country": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `C`",
-				},
-				"postal_code": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `PC`",
-				},
-				"serial_number": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					ForceNew:    true,
-					Description: "Distinguished name: `SERIALNUMBER`",
-				},
-			},
-		},
-		Description: "The subject for which a certificate is being requested. " +
-			"The acceptable arguments are all optional and their naming is based upon " +
-			"[Issuer Distinguished Names (RFC5280)](https://tools.ietf.org/html/rfc5280#section-4.1.2.4) section.",
+	if err := lockFile(lock); err != nil {
+		return nil, fmt.Errorf("failed to lock serial number file: %w", err)
 	}
-}
+	defer func() { _ = unlockFile(lock) }()
 
-// setCertificateCommonSchema sets on the given reference to map of schema.Schema
-// all the keys required by a resource representing a certificate.
-func setCertificateCommonSchema(s map[string]*schema.Schema) {
-	s["validity_period_hours"] = &schema.Schema{
-		Type:             schema.TypeInt,
-		Required:         true,
-		ForceNew:         true,
-		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
-		Description:      "Number of hours, after initial issuing, that the certificate will remain valid for.",
+	serial := big.NewInt(0)
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read serial number file: %w", err)
+	}
+	if err == nil {
+		serial, err = parseCertificateSerialHex(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid serial number file %q: %w", path, err)
+		}
 	}
 
-	s["early_renewal_hours"] = &schema.Schema{
-		Type:             schema.TypeInt,
-		Optional:         true,
-		Default:          0,
-		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
-		Description: "The resource will consider the certificate to have expired the given number of hours " +
-			"before its actual expiry time. This can be useful to deploy an updated certificate in advance of " +
-			"the expiration of the current certificate. " +
-			"However, the old certificate remains valid until its true expiration time, since this resource " +
-			"does not (and cannot) support certificate revocation. " +
-			"Also, this advance update can only be performed should the Terraform configuration be applied " +
-			"during the early renewal period. (default: `0`)",
+	serial.Add(serial, big.NewInt(1))
+	serialHex := strings.ToUpper(hex.EncodeToString(serial.Bytes()))
+	if _, err := parseCertificateSerialHex(serialHex); err != nil {
+		return nil, fmt.Errorf("serial numbers in file %q are exhausted: %w", path, err)
 	}
 
-	s["is_ca_certificate"] = &schema.Schema{
-		Type:        schema.TypeBool,
-		Optional:    true,
-		ForceNew:    true,
-		Description: "Is the generated certificate representing a Certificate Authority (CA) (default: `false`).",
+	if err := writeFileAtomically(path, []byte(serialHex+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write serial number file: %w", err)
 	}
 
-	s["allowed_uses"] = &schema.Schema{
-		Type:     schema.TypeList,
-		Required: true,
-		ForceNew: true,
-		Elem: &schema.Schema{
-			Type:             schema.TypeString,
-			ValidateDiagFunc: StringInSliceOrWarn(supportedKeyUsages(), false),
-		},
-		Description: "List of key usages allowed for the issued certificate. " +
-			"Values are defined in [RFC 5280](https://datatracker.ietf.org/doc/html/rfc5280) " +
-			"and combine flags defined by both " +
-			"[Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.3) " +
-			"and [Extended Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.12). " +
-			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedKeyUsages(), "`, `")),
+	return serial, nil
+}
+
+// validateCertificateSerialHex is a schema.SchemaValidateFunc that ensures
+// the value can be parsed by parseCertificateSerialHex.
+func validateCertificateSerialHex(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
 	}
 
-	s["cert_pem"] = &schema.Schema{
-		Type:     schema.TypeString,
-		Computed: true,
-		Description: "Certificate data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
-			"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
-			"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
-			"value append a `\\n` at the end of the PEM. " +
-			"In case this disrupts your use case, we recommend using " +
-			"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+	if _, err := parseCertificateSerialHex(v); err != nil {
+		errors = append(errors, fmt.Errorf("invalid %s: %w", k, err))
 	}
 
-	s["ready_for_renewal"] = &schema.Schema{
-		Type:     schema.TypeBool,
-		Computed: true,
-		Description: "Is the certificate either expired (i.e. beyond the `validity_period_hours`) " +
-			"or ready for an early renewal (i.e. within the `early_renewal_hours`)?",
+	return warnings, errors
+}
+
+// validateUniqueIDHex is a schema.SchemaValidateFunc that ensures
+// the value is a non-empty hexadecimal string, as expected by `issuer_unique_id` and `subject_unique_id`.
+func validateUniqueIDHex(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
 	}
 
-	s["validity_start_time"] = &schema.Schema{
-		Type:     schema.TypeString,
-		Computed: true,
-		Description: "The time after which the certificate is valid, " +
-			"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+	if b, err := hex.DecodeString(v); err != nil || len(b) == 0 {
+		errors = append(errors, fmt.Errorf("invalid %s: %q is not a valid hexadecimal string", k, v))
 	}
 
-	s["validity_end_time"] = &schema.Schema{
-		Type:     schema.TypeString,
-		Computed: true,
-		Description: "The time until which the certificate is invalid, " +
-			"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+	return warnings, errors
+}
+
+// signatureAlgorithmHashes maps the algorithms that certificates can be signed with,
+// to the hash functions they use (none for `Ed25519`, that signs the whole message).
+var signatureAlgorithmHashes = map[x509.SignatureAlgorithm]crypto.Hash{
+	x509.SHA256WithRSA:    crypto.SHA256,
+	x509.SHA384WithRSA:    crypto.SHA384,
+	x509.SHA512WithRSA:    crypto.SHA512,
+	x509.SHA256WithRSAPSS: crypto.SHA256,
+	x509.SHA384WithRSAPSS: crypto.SHA384,
+	x509.SHA512WithRSAPSS: crypto.SHA512,
+	x509.ECDSAWithSHA256:  crypto.SHA256,
+	x509.ECDSAWithSHA384:  crypto.SHA384,
+	x509.ECDSAWithSHA512:  crypto.SHA512,
+	x509.PureEd25519:      crypto.Hash(0),
+}
+
+// setCertificateUniqueIDs returns the given DER certificate with the `issuerUniqueID` and `subjectUniqueID`
+// fields of its TBSCertificate set to the given (non-empty) identifiers, signed again with the given private key
+// (see https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.8).
+// The source of randomness is passed to the signer, like x509.CreateCertificate does.
+func setCertificateUniqueIDs(random io.Reader, certDER, issuerUniqueID, subjectUniqueID []byte, prv crypto.PrivateKey) ([]byte, error) {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, err
 	}
 
-	s["set_subject_key_id"] = &schema.Schema{
-		Type:     schema.TypeBool,
-		Optional: true,
-		ForceNew: true,
-		Description: "Should the generated certificate include a " +
-			"[subject key identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.2) (default: `false`).",
+	var certFields struct {
+		TBSCertificate     asn1.RawValue
+		SignatureAlgorithm asn1.RawValue
+		SignatureValue     asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(certDER, &certFields); err != nil {
+		return nil, err
 	}
 
-	s["id"] = &schema.Schema{
-		Type:        schema.TypeString,
-		Computed:    true,
-		Description: "Unique identifier for this resource: the certificate serial number.",
+	// The fields of the TBSCertificate up to `subjectPublicKeyInfo` (included) are always present,
+	// as x509.CreateCertificate always sets the version: the unique identifiers follow them
+	var tbsFields [][]byte
+	for rest := certFields.TBSCertificate.Bytes; len(rest) > 0; {
+		var field asn1.RawValue
+		if rest, err = asn1.Unmarshal(rest, &field); err != nil {
+			return nil, err
+		}
+		tbsFields = append(tbsFields, field.FullBytes)
+	}
+	if len(tbsFields) < 7 {
+		return nil, fmt.Errorf("unexpected number of fields in TBSCertificate: %d", len(tbsFields))
 	}
-}
 
-func createCertificate(d *schema.ResourceData, template, parent *x509.Certificate, pub crypto.PublicKey, prv interface{}) diag.Diagnostics {
-	var err error
+	tbs := bytes.Join(tbsFields[:7], nil)
+	for i, uniqueID := range [][]byte{issuerUniqueID, subjectUniqueID} {
+		if len(uniqueID) == 0 {
+			continue
+		}
 
-	template.NotBefore = overridableTimeFunc()
-	validityPeriodHours := d.Get("validity_period_hours").(int)
-	template.NotAfter = template.NotBefore.Add(time.Duration(validityPeriodHours) * time.Hour)
+		// NOTE: the identifiers are IMPLICIT BIT STRINGs, tagged [1] and [2] respectively,
+		// whose content starts with the number of unused bits
+		uniqueIDBytes, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: i + 1, Bytes: append([]byte{0}, uniqueID...)})
+		if err != nil {
+			return nil, err
+		}
+		tbs = append(tbs, uniqueIDBytes...)
+	}
+	tbs = append(tbs, bytes.Join(tbsFields[7:], nil)...)
+
+	tbsBytes, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true, Bytes: tbs})
+	if err != nil {
+		return nil, err
+	}
+
+	hash, ok := signatureAlgorithmHashes[cert.SignatureAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm: %s", cert.SignatureAlgorithm)
+	}
+
+	signed := tbsBytes
+	if hash != 0 {
+		h := hash.New()
+		h.Write(tbsBytes)
+		signed = h.Sum(nil)
+	}
+
+	var signerOpts crypto.SignerOpts = hash
+	switch cert.SignatureAlgorithm {
+	case x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS:
+		signerOpts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+
+	signer, ok := prv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("private key of type %T cannot sign", prv)
+	}
+	signature, err := signer.Sign(random, signed, signerOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(struct {
+		TBSCertificate     asn1.RawValue
+		SignatureAlgorithm asn1.RawValue
+		SignatureValue     asn1.BitString
+	}{
+		TBSCertificate:     asn1.RawValue{FullBytes: tbsBytes},
+		SignatureAlgorithm: certFields.SignatureAlgorithm,
+		SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+}
+
+// oidExtensionSCTList is the OID of the Signed Certificate Timestamp List extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc6962#section-3.3.
+var oidExtensionSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// marshalSCTListExtension creates a pkix.Extension containing the given (already serialized)
+// Signed Certificate Timestamps.
+//
+// The extension value is an OCTET STRING, containing a TLS-encoded SignedCertificateTimestampList:
+// every SCT is prefixed by its 2 bytes length, and the whole list is prefixed by its 2 bytes length.
+func marshalSCTListExtension(scts [][]byte) (pkix.Extension, error) {
+	var list []byte
+	for _, sct := range scts {
+		if len(sct) == 0 || len(sct) > math.MaxUint16 {
+			return pkix.Extension{}, fmt.Errorf("invalid signed certificate timestamp length: %d", len(sct))
+		}
+		list = append(list, byte(len(sct)>>8), byte(len(sct)))
+		list = append(list, sct...)
+	}
+	if len(list) > math.MaxUint16 {
+		return pkix.Extension{}, fmt.Errorf("signed certificate timestamp list too long: %d bytes", len(list))
+	}
+
+	value, err := asn1.Marshal(append([]byte{byte(len(list) >> 8), byte(len(list))}, list...))
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionSCTList,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionSubjectAltName is the OID of the Subject Alternative Name extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.6.
+var oidExtensionSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+// oidOtherNameUPN is the OID of the Microsoft User Principal Name (UPN) otherName,
+// used for Active Directory smartcard logon.
+var oidOtherNameUPN = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 20, 2, 3}
+
+// upnOtherName is the otherName GeneralName form of a User Principal Name.
+type upnOtherName struct {
+	TypeID asn1.ObjectIdentifier
+	Value  string `asn1:"utf8,explicit,tag:0"`
+}
+
+// marshalSANExtensionWithUPNs creates a pkix.Extension containing the Subject Alternative Names
+// of the given x509.Certificate template (DNS names, email addresses, IP addresses and URIs), followed by the given
+// User Principal Names encoded as otherName.
+//
+// As the standard library cannot encode otherName, the extension is built here: once added to
+// the template ExtraExtensions, it replaces the one x509.CreateCertificate would have generated.
+func marshalSANExtensionWithUPNs(template *x509.Certificate, upns []string) (pkix.Extension, error) {
+	var names []asn1.RawValue
+	for _, name := range template.DNSNames {
+		names = append(names, asn1.RawValue{Tag: 2, Class: asn1.ClassContextSpecific, Bytes: []byte(name)})
+	}
+	for _, email := range template.EmailAddresses {
+		names = append(names, asn1.RawValue{Tag: 1, Class: asn1.ClassContextSpecific, Bytes: []byte(email)})
+	}
+	for _, ip := range template.IPAddresses {
+		ipBytes := ip.To4()
+		if ipBytes == nil {
+			ipBytes = ip
+		}
+		names = append(names, asn1.RawValue{Tag: 7, Class: asn1.ClassContextSpecific, Bytes: ipBytes})
+	}
+	for _, uri := range template.URIs {
+		names = append(names, asn1.RawValue{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(uri.String())})
+	}
+	for _, upn := range upns {
+		otherName, err := asn1.MarshalWithParams(upnOtherName{TypeID: oidOtherNameUPN, Value: upn}, "tag:0")
+		if err != nil {
+			return pkix.Extension{}, err
+		}
+		names = append(names, asn1.RawValue{FullBytes: otherName})
+	}
+
+	return marshalSANExtension(names, template.Subject)
+}
+
+// marshalSANExtension creates a pkix.Extension containing the given GeneralNames, in the given order,
+// for a certificate (or certificate request) with the given subject.
+func marshalSANExtension(names []asn1.RawValue, subject pkix.Name) (pkix.Extension, error) {
+	value, err := asn1.Marshal(names)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id: oidExtensionSubjectAltName,
+		// NOTE: as per RFC 5280, the extension must be critical if the subject is empty
+		Critical: len(subject.ToRDNSequence()) == 0,
+		Value:    value,
+	}, nil
+}
+
+// orderedSANs holds the Subject Alternative Names configured via `san`.
+type orderedSANs struct {
+	dnsNames       []string
+	emailAddresses []string
+	ipAddresses    []net.IP
+	uris           []*url.URL
+
+	// generalNames are all the names, encoded in the configured order
+	generalNames []asn1.RawValue
+}
+
+// parseOrderedSANs parses the Subject Alternative Names configured via `san`,
+// both grouped by type, as x509.Certificate and x509.CertificateRequest hold them,
+// and encoded as GeneralNames in the configured order, for marshalSANExtension.
+func parseOrderedSANs(sansI []interface{}) (*orderedSANs, error) {
+	sans := &orderedSANs{}
+	for i, sanI := range sansI {
+		san, _ := sanI.(map[string]interface{})
+		if san == nil {
+			return nil, fmt.Errorf("san.%d: block cannot be empty", i)
+		}
+		sanType, value := SANType(san["type"].(string)), san["value"].(string)
+
+		switch sanType {
+		case SANTypeDNS:
+			sans.dnsNames = append(sans.dnsNames, value)
+			sans.generalNames = append(sans.generalNames, asn1.RawValue{Tag: 2, Class: asn1.ClassContextSpecific, Bytes: []byte(value)})
+		case SANTypeEmail:
+			sans.emailAddresses = append(sans.emailAddresses, value)
+			sans.generalNames = append(sans.generalNames, asn1.RawValue{Tag: 1, Class: asn1.ClassContextSpecific, Bytes: []byte(value)})
+		case SANTypeIP:
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("san.%d: invalid IP address %q", i, value)
+			}
+			sans.ipAddresses = append(sans.ipAddresses, ip)
+			ipBytes := ip.To4()
+			if ipBytes == nil {
+				ipBytes = ip
+			}
+			sans.generalNames = append(sans.generalNames, asn1.RawValue{Tag: 7, Class: asn1.ClassContextSpecific, Bytes: ipBytes})
+		case SANTypeURI:
+			uri, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("san.%d: invalid URI %q", i, value)
+			}
+			sans.uris = append(sans.uris, uri)
+			sans.generalNames = append(sans.generalNames, asn1.RawValue{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(uri.String())})
+		default:
+			return nil, fmt.Errorf("san.%d: unsupported type %q", i, sanType)
+		}
+	}
+
+	return sans, nil
+}
+
+// checkSANValue checks that the given value is a valid Subject Alternative Name of the given type.
+func checkSANValue(sanType SANType, value string, checkDNSNames bool) error {
+	switch sanType {
+	case SANTypeDNS:
+		if checkDNSNames {
+			if err := checkDNSNameSyntax(value); err != nil {
+				return fmt.Errorf("not a valid DNS name: %w (set skip_dns_name_syntax_check to accept it anyway)", err)
+			}
+		}
+	case SANTypeIP:
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("not a valid IP address")
+		}
+	case SANTypeURI:
+		if u, err := url.Parse(value); err != nil || !u.IsAbs() {
+			return fmt.Errorf("not an absolute URI, with a scheme")
+		}
+	case SANTypeEmail:
+		if at := strings.LastIndex(value, "@"); at <= 0 || at == len(value)-1 {
+			return fmt.Errorf("not an email address of the form 'user@domain'")
+		}
+	}
+	return nil
+}
+
+// otherName is the otherName GeneralName form, with a value of any type.
+//
+// NOTE: `encoding/asn1` doesn't unwrap explicitly tagged asn1.RawValue fields:
+// Value holds the `[0]` tagged element, that wraps the actual value.
+type otherName struct {
+	TypeID asn1.ObjectIdentifier
+	Value  asn1.RawValue
+}
+
+// otherNameSANsToList returns the otherName Subject Alternative Names of the given x509.Certificate,
+// in the order they appear in the extension: `crypto/x509` skips them when parsing the certificate.
+//
+// The value is returned as a string when it is one of the ASN.1 string types (as for User Principal Names),
+// and always in DER format, encoded in base64.
+func otherNameSANsToList(cert *x509.Certificate) []interface{} {
+	res := []interface{}{}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidExtensionSubjectAltName) {
+			continue
+		}
+
+		// NOTE: the extension has already been validated by x509.ParseCertificate
+		var names []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			return res
+		}
+		for _, name := range names {
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+				continue
+			}
+
+			// GOTCHA: the content of otherName is not validated by x509.ParseCertificate,
+			// so those that cannot be unmarshalled are skipped
+			var on otherName
+			if _, err := asn1.UnmarshalWithParams(name.FullBytes, &on, "tag:0"); err != nil {
+				continue
+			}
+			if on.Value.Class != asn1.ClassContextSpecific || on.Value.Tag != 0 {
+				continue
+			}
+			var onValue asn1.RawValue
+			if _, err := asn1.Unmarshal(on.Value.Bytes, &onValue); err != nil {
+				continue
+			}
+
+			var value string
+			if onValue.Class == asn1.ClassUniversal {
+				switch onValue.Tag {
+				case asn1.TagUTF8String, asn1.TagPrintableString, asn1.TagIA5String:
+					value = string(onValue.Bytes)
+				}
+			}
+
+			res = append(res, map[string]interface{}{
+				"type_id":      on.TypeID.String(),
+				"value":        value,
+				"value_base64": base64.StdEncoding.EncodeToString(onValue.FullBytes),
+			})
+		}
+	}
+
+	return res
+}
+
+// oidExtensionAuthorityKeyID is the OID of the Authority Key Identifier extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.1.
+var oidExtensionAuthorityKeyID = asn1.ObjectIdentifier{2, 5, 29, 35}
+
+// authorityKeyIdentifier is the value of the Authority Key Identifier extension.
+type authorityKeyIdentifier struct {
+	KeyIdentifier             []byte          `asn1:"optional,tag:0"`
+	AuthorityCertIssuer       []asn1.RawValue `asn1:"optional,tag:1"`
+	AuthorityCertSerialNumber *big.Int        `asn1:"optional,tag:2"`
+}
+
+// marshalAuthorityKeyIDExtensionWithIssuerAndSerial creates a pkix.Extension containing the
+// Authority Key Identifier of certificates signed by the given CA certificate, in its full form:
+// the key identifier of the CA (if any), followed by the issuer (as directoryName) and the serial number
+// of the CA certificate.
+//
+// As the standard library only encodes the key identifier, the extension is built here: once added to
+// the template ExtraExtensions, it replaces the one x509.CreateCertificate would have generated.
+func marshalAuthorityKeyIDExtensionWithIssuerAndSerial(caCert *x509.Certificate) (pkix.Extension, error) {
+	value, err := asn1.Marshal(authorityKeyIdentifier{
+		KeyIdentifier: caCert.SubjectKeyId,
+		AuthorityCertIssuer: []asn1.RawValue{
+			{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: caCert.RawIssuer},
+		},
+		AuthorityCertSerialNumber: caCert.SerialNumber,
+	})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionAuthorityKeyID,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionBasicConstraints is the OID of the Basic Constraints extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.9.
+var oidExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+
+// basicConstraints is the value of the Basic Constraints extension.
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+// marshalBasicConstraintsExtension creates a pkix.Extension containing the given basic constraints,
+// the same way x509.CreateCertificate does, but with the given criticality.
+func marshalBasicConstraintsExtension(isCA bool, maxPathLen int, maxPathLenZero bool, critical bool) (pkix.Extension, error) {
+	// Leaving MaxPathLen as zero indicates that no maximum path
+	// length is desired, unless MaxPathLenZero is set
+	if maxPathLen == 0 && !maxPathLenZero {
+		maxPathLen = -1
+	}
+
+	value, err := asn1.Marshal(basicConstraints{isCA, maxPathLen})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:       oidExtensionBasicConstraints,
+		Critical: critical,
+		Value:    value,
+	}, nil
+}
+
+// oidExtensionSubjectInfoAccess is the OID of the Subject Information Access extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.2.2.
+var oidExtensionSubjectInfoAccess = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 11}
+
+// accessDescription is an AccessDescription, as used by the Subject Information Access extension.
+type accessDescription struct {
+	Method   asn1.ObjectIdentifier
+	Location asn1.RawValue
+}
+
+// parseOID parses an object identifier from its dotted decimal representation (e.g. `1.3.6.1.5.5.7.48.5`).
+//
+// GOTCHA: The first two arcs are encoded together as `40 * first + second`
+// (see https://www.itu.int/rec/T-REC-X.690 section 8.19.4), so the first arc can only be
+// `0`, `1` or `2`, and the second one must be lower than `40` unless the first is `2`:
+// otherwise asn1.Marshal would fail.
+func parseOID(oidStr string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(oidStr, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("%q is not a valid object identifier", oidStr)
+	}
+
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%q is not a valid object identifier", oidStr)
+		}
+		oid[i] = n
+	}
+
+	if oid[0] > 2 || (oid[0] < 2 && oid[1] >= 40) {
+		return nil, fmt.Errorf("%q is not a valid object identifier: the first arc must be 0, 1 or 2, "+
+			"and the second arc must be lower than 40 when the first is 0 or 1", oidStr)
+	}
+
+	return oid, nil
+}
+
+// validateOID is a schema.SchemaValidateFunc that ensures
+// the value can be parsed by parseOID.
+func validateOID(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if _, err := parseOID(v); err != nil {
+		errors = append(errors, fmt.Errorf("invalid %s: %w", k, err))
+	}
+
+	return warnings, errors
+}
+
+// marshalSubjectInfoAccessExtension creates a pkix.Extension containing the given
+// access descriptions, each pointing at a URI.
+func marshalSubjectInfoAccessExtension(descriptions []accessDescription) (pkix.Extension, error) {
+	value, err := asn1.Marshal(descriptions)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionSubjectInfoAccess,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionFreshestCRL is the OID of the Freshest CRL (a.k.a. Delta CRL Distribution Point) extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.15.
+var oidExtensionFreshestCRL = asn1.ObjectIdentifier{2, 5, 29, 46}
+
+// distributionPoint is a DistributionPoint, as used by the CRL Distribution Points and Freshest CRL extensions:
+// only the full name of the distribution point is supported, without reasons nor CRL issuer.
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+}
+
+// distributionPointName is a DistributionPointName, holding the full name of a distribution point.
+type distributionPointName struct {
+	FullName []asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// marshalFreshestCRLExtension creates a pkix.Extension containing a distribution point for each of the given URIs.
+func marshalFreshestCRLExtension(uris []string) (pkix.Extension, error) {
+	points := make([]distributionPoint, len(uris))
+	for i, uri := range uris {
+		points[i].DistributionPoint.FullName = []asn1.RawValue{
+			{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(uri)},
+		}
+	}
+
+	value, err := asn1.Marshal(points)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionFreshestCRL,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionCertificatePolicies is the OID of the Certificate Policies extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.4.
+var oidExtensionCertificatePolicies = asn1.ObjectIdentifier{2, 5, 29, 32}
+
+var (
+	// oidPolicyQualifierCPS is the OID of the CPS Pointer policy qualifier (id-qt-cps).
+	oidPolicyQualifierCPS = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 1}
+	// oidPolicyQualifierUserNotice is the OID of the User Notice policy qualifier (id-qt-unotice).
+	oidPolicyQualifierUserNotice = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 2}
+)
+
+// policyInformation is a PolicyInformation, as used by the Certificate Policies extension.
+type policyInformation struct {
+	PolicyIdentifier asn1.ObjectIdentifier
+	PolicyQualifiers []policyQualifierInfo `asn1:"optional,omitempty"`
+}
+
+// policyQualifierInfo is a PolicyQualifierInfo, as used by the Certificate Policies extension:
+// the Qualifier is either a CPSuri (IA5String) or a UserNotice, depending on the PolicyQualifierID.
+type policyQualifierInfo struct {
+	PolicyQualifierID asn1.ObjectIdentifier
+	Qualifier         asn1.RawValue
+}
+
+// userNotice is a UserNotice, as used by the Certificate Policies extension:
+// the (optional) noticeRef is not supported, and the explicitText is always a UTF8String,
+// as recommended by RFC 5280.
+type userNotice struct {
+	ExplicitText string `asn1:"utf8"`
+}
+
+// certificatePolicy describes a certificate policy, with its (optional) qualifiers.
+type certificatePolicy struct {
+	identifier     asn1.ObjectIdentifier
+	cpsURIs        []string
+	userNoticeText string
+}
+
+// marshalCertificatePoliciesExtension creates a pkix.Extension containing the given certificate policies,
+// each with a CPS Pointer qualifier per CPS URI, followed by a User Notice qualifier if it has a notice text.
+func marshalCertificatePoliciesExtension(policies []certificatePolicy) (pkix.Extension, error) {
+	infos := make([]policyInformation, len(policies))
+	for i, policy := range policies {
+		infos[i].PolicyIdentifier = policy.identifier
+
+		for _, cpsURI := range policy.cpsURIs {
+			infos[i].PolicyQualifiers = append(infos[i].PolicyQualifiers, policyQualifierInfo{
+				PolicyQualifierID: oidPolicyQualifierCPS,
+				Qualifier:         asn1.RawValue{Tag: asn1.TagIA5String, Bytes: []byte(cpsURI)},
+			})
+		}
+
+		if policy.userNoticeText != "" {
+			notice, err := asn1.Marshal(userNotice{ExplicitText: policy.userNoticeText})
+			if err != nil {
+				return pkix.Extension{}, err
+			}
+			infos[i].PolicyQualifiers = append(infos[i].PolicyQualifiers, policyQualifierInfo{
+				PolicyQualifierID: oidPolicyQualifierUserNotice,
+				Qualifier:         asn1.RawValue{FullBytes: notice},
+			})
+		}
+	}
+
+	value, err := asn1.Marshal(infos)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionCertificatePolicies,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionAdmission is the OID of the Admission extension of Common PKI (formerly ISIS-MTT).
+//
+// See the Common PKI specification, Part 1 (Certificate and CRL Profiles), for its definition.
+var oidExtensionAdmission = asn1.ObjectIdentifier{1, 3, 36, 8, 3, 3}
+
+// admissionSyntax is the value of the Admission extension.
+type admissionSyntax struct {
+	AdmissionAuthority   asn1.RawValue `asn1:"optional"`
+	ContentsOfAdmissions []admissions
+}
+
+// admissions is an Admissions, as used by the Admission extension:
+// the (optional) admissionAuthority and namingAuthority are not supported.
+type admissions struct {
+	ProfessionInfos []professionInfo
+}
+
+// professionInfo is a ProfessionInfo, as used by the Admission extension:
+// the (optional) namingAuthority and addProfessionInfo are not supported.
+type professionInfo struct {
+	ProfessionItems    []string
+	ProfessionOIDs     []asn1.ObjectIdentifier `asn1:"optional"`
+	RegistrationNumber string                  `asn1:"optional,printable"`
+}
+
+// marshalAdmissionExtension creates a pkix.Extension containing the given profession infos,
+// and optionally the URI of the admission authority.
+func marshalAdmissionExtension(admissionAuthorityURI string, infos []professionInfo) (pkix.Extension, error) {
+	syntax := admissionSyntax{
+		ContentsOfAdmissions: []admissions{{ProfessionInfos: infos}},
+	}
+	if admissionAuthorityURI != "" {
+		syntax.AdmissionAuthority = asn1.RawValue{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(admissionAuthorityURI)}
+	}
+
+	value, err := asn1.Marshal(syntax)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionAdmission,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionLogotype is the OID of the Logotype extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc3709#section-4.1.
+var oidExtensionLogotype = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 12}
+
+// oidHashSHA256 is the OID of the SHA256 hash algorithm.
+var oidHashSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+
+// logotypeTags maps the types of logotype accepted by the `logotype` block
+// to the tags of the respective fields of the Logotype extension.
+var logotypeTags = map[string]int{
+	"community": 0,
+	"issuer":    1,
+	"subject":   2,
+}
+
+// hashAlgAndValue is a HashAlgAndValue, as used by the Logotype extension.
+type hashAlgAndValue struct {
+	HashAlg   pkix.AlgorithmIdentifier
+	HashValue []byte
+}
+
+// logotypeDetails is a LogotypeDetails, as used by the Logotype extension.
+// The URIs are IA5Strings: encoding/asn1 can't tag the elements of a slice of strings as such.
+type logotypeDetails struct {
+	MediaType    string `asn1:"ia5"`
+	LogotypeHash []hashAlgAndValue
+	LogotypeURI  []asn1.RawValue
+}
+
+// logotypeImage is a LogotypeImage, as used by the Logotype extension:
+// the (optional) imageInfo is not supported.
+type logotypeImage struct {
+	ImageDetails logotypeDetails
+}
+
+// logotypeData is a LogotypeData, as used by the Logotype extension:
+// the (optional) audio is not supported.
+type logotypeData struct {
+	Image []logotypeImage
+}
+
+// marshalLogotypeExtension creates a pkix.Extension containing a single logotype of the given type
+// (see logotypeTags), referencing directly the image at the given URI, with the given media type and SHA256 hash.
+func marshalLogotypeExtension(logoType, mediaType, imageURI string, imageSHA256 []byte) (pkix.Extension, error) {
+	tag, ok := logotypeTags[logoType]
+	if !ok {
+		return pkix.Extension{}, fmt.Errorf("unsupported logotype type: %s", logoType)
+	}
+
+	data, err := asn1.Marshal(logotypeData{
+		Image: []logotypeImage{{
+			ImageDetails: logotypeDetails{
+				MediaType: mediaType,
+				LogotypeHash: []hashAlgAndValue{{
+					HashAlg:   pkix.AlgorithmIdentifier{Algorithm: oidHashSHA256},
+					HashValue: imageSHA256,
+				}},
+				LogotypeURI: []asn1.RawValue{{Tag: asn1.TagIA5String, Bytes: []byte(imageURI)}},
+			},
+		}},
+	})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	// NOTE: the module of the Logotype extension uses IMPLICIT tags: the `direct` LogotypeInfo
+	// replaces the tag of the LogotypeData sequence, while the (CHOICE) LogotypeInfo is always tagged
+	// explicitly, either by itself or as the only element of the `communityLogos` sequence
+	var dataSeq asn1.RawValue
+	if _, err := asn1.Unmarshal(data, &dataSeq); err != nil {
+		return pkix.Extension{}, err
+	}
+	info, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: dataSeq.Bytes})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	value, err := asn1.Marshal(struct {
+		Logo asn1.RawValue
+	}{
+		Logo: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: info},
+	})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionLogotype,
+		Value: value,
+	}, nil
+}
+
+// oidExtensionSMIMECapabilities is the OID of the S/MIME Capabilities extension.
+//
+// See https://datatracker.ietf.org/doc/html/rfc4262#section-2.
+var oidExtensionSMIMECapabilities = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 15}
+
+// smimeCapability is the ASN.1 structure of an SMIMECapability.
+//
+// See https://datatracker.ietf.org/doc/html/rfc8551#section-2.5.2.
+type smimeCapability struct {
+	CapabilityID asn1.ObjectIdentifier
+	Parameters   asn1.RawValue `asn1:"optional"`
+}
+
+// marshalSMIMECapabilitiesExtension returns the S/MIME Capabilities extension listing the given capabilities,
+// in order of preference.
+func marshalSMIMECapabilitiesExtension(capabilities []smimeCapability) (pkix.Extension, error) {
+	value, err := asn1.Marshal(capabilities)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionSMIMECapabilities,
+		Value: value,
+	}, nil
+}
+
+// setCertificateSubjectSchema sets on the given reference to map of schema.Schema
+// all the keys required by a resource representing a certificate's subject.
+func setCertificateSubjectSchema(s map[string]*schema.Schema) {
+	setCertificateSubjectNamesSchema(s)
+
+	s["key_algorithm"] = &schema.Schema{
+		Type:       schema.TypeString,
+		Optional:   true,
+		Computed:   true,
+		ForceNew:   true,
+		Deprecated: "This is now ignored, as the key algorithm is inferred from the `private_key_pem`.",
+		Description: "Name of the algorithm used when generating the private key provided in `private_key_pem`. " +
+			"**NOTE**: this is deprecated and ignored, as the key algorithm is now inferred from the key. ",
+	}
+
+	s["rsa_bits"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+		Description: "Size in bits of the private key provided in `private_key_pem`, " +
+			"when the key algorithm is `RSA` (`0` otherwise).",
+	}
+
+	s["ecdsa_curve"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Elliptic curve of the private key provided in `private_key_pem`, " +
+			"when the key algorithm is `ECDSA` (empty otherwise).",
+	}
+
+	s["private_key_pem"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		Sensitive:    true,
+		ExactlyOneOf: []string{"private_key_pem", "private_key_pem_file"},
+		StateFunc: func(v interface{}) string {
+			return hashForState(v.(string))
+		},
+		Description: "Private key in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+			"that the certificate will belong to. " +
+			"This can be read from a separate file using the [`file`](https://www.terraform.io/language/functions/file) " +
+			"interpolation function. " +
+			"Only an irreversible secure hash of the private key will be stored in the Terraform state. " +
+			"This is _mutually exclusive_ with `private_key_pem_file`.",
+	}
+
+	s["private_key_pem_file"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"private_key_pem", "private_key_pem_file"},
+		Description: "Path of a file containing the private key in " +
+			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+			"that the certificate will belong to. " +
+			"The file is read on the machine running `terraform apply`, only when the resource is created: " +
+			"only its path is stored in the Terraform state, and changes to its content are not detected. " +
+			"This is _mutually exclusive_ with `private_key_pem`.",
+	}
+}
+
+// setCertificateSubjectNamesSchema sets on the given reference to map of schema.Schema
+// the keys representing the names (i.e. subject and SANs) a certificate is issued to.
+func setCertificateSubjectNamesSchema(s map[string]*schema.Schema) {
+	s["dns_names"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateDNSName,
+		},
+		Description: "List of DNS names for which a certificate is being requested (i.e. certificate subjects). " +
+			"IP addresses and URIs are not accepted: use `ip_addresses` and `uris` instead. Values must be unique, " +
+			"and syntactically valid host names (e.g. `example.com`), optionally with a wildcard as the leftmost label " +
+			"(e.g. `*.example.com`), unless `skip_dns_name_syntax_check` is `true`.",
+	}
+
+	s["skip_dns_name_syntax_check"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Default:  false,
+		Description: "By default, each of `dns_names` must be a syntactically valid host name: made of labels of at most 63 letters, " +
+			"digits and hyphens (not at the start or end of a label), without a trailing dot, and with internationalized names " +
+			"in their ASCII form (e.g. `xn--bcher-kva.example`). When `true`, any other name is accepted too, " +
+			"for example to embed names with underscores on purpose (default: `false`).",
+	}
+
+	s["ip_addresses"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateIPAddress,
+		},
+		Description: "List of IP addresses for which a certificate is being requested (i.e. certificate subjects). " +
+			"Each value must be a single IP address: IP ranges in CIDR notation (e.g. `10.0.0.0/8`) are not accepted, " +
+			"as they can only be used in the name constraints of CA certificates. Values must be unique.",
+	}
+
+	s["uris"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateURI,
+		},
+		Description: "List of URIs for which a certificate is being requested (i.e. certificate subjects). " +
+			"Each URI must be absolute (i.e. include a scheme, like `spiffe://`). Values must be unique.",
+	}
+
+	s["san"] = &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"dns_names", "ip_addresses", "uris"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedSANTypesStr(), false)),
+					Description: "Type of the Subject Alternative Name. " +
+						fmt.Sprintf("Accepted values are: `%s`.", strings.Join(SupportedSANTypesStr(), "`, `")),
+				},
+				"value": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+					Description: "The Subject Alternative Name, with the same syntax as the respective list " +
+						"(i.e. `dns_names`, `ip_addresses` or `uris`), or of the form `user@domain` for `email`.",
+				},
+			},
+		},
+		Description: "Subject Alternative Names (SANs) of mixed types, encoded in exactly the configured order: " +
+			"instead, the names given via `dns_names`, `ip_addresses` and `uris` are grouped by type. " +
+			"This allows re-issuing a certificate (or certificate request) that is identical to an existing one, " +
+			"byte for byte, and adding email addresses (i.e. `rfc822Name`). " +
+			"This is _mutually exclusive_ with `dns_names`, `ip_addresses` and `uris`. Values must be unique.",
+	}
+
+	s["subject"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Required: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"organization": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubOrganizationName),
+					Description:      "Distinguished name: `O`",
+				},
+				"common_name": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubCommonName),
+					Description:      "Distinguished name: `CN`",
+				},
+				"organizational_unit": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubOrganizationalUnitName),
+					Description:      "Distinguished name: `OU`",
+				},
+				"street_address": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: StringLenAtMostOrWarn(ubStreetAddress),
+					},
+					ForceNew:    true,
+					Description: "Distinguished name: `STREET`",
+				},
+				"locality": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubLocalityName),
+					Description:      "Distinguished name: `L`",
+				},
+				"province": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubStateName),
+					Description:      "Distinguished name: `ST`",
+				},
+				"country": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateCountryName,
+					Description:      "Distinguished name: `C`",
+				},
+				"postal_code": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubPostalCode),
+					Description:      "Distinguished name: `PC`",
+				},
+				"serial_number": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: StringLenAtMostOrWarn(ubSerialNumber),
+					Description:      "Distinguished name: `SERIALNUMBER`",
+				},
+			},
+		},
+		Description: "The subject for which a certificate is being requested. " +
+			"The acceptable arguments are all optional and their naming is based upon " +
+			"[Issuer Distinguished Names (RFC5280)](https://tools.ietf.org/html/rfc5280#section-4.1.2.4) section. " +
+			"The `country` must be a two letters code: lowercase codes, and values exceeding " +
+			"the [upper bounds](https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1) of the other arguments, " +
+			"only produce a warning, as some (but not all) validators accept them. " +
+			"At most one `subject` block can be given: a certificate has a single subject, " +
+			"use `dns_names`, `ip_addresses` and `uris` to cover multiple identities.",
+	}
+
+	s["subject_serial_from_key"] = &schema.Schema{
+		Type:          schema.TypeBool,
+		Optional:      true,
+		ForceNew:      true,
+		Default:       false,
+		ConflictsWith: []string{"subject.0.serial_number"},
+		Description: "When `true`, the `serial_number` of the `subject` (i.e. `SERIALNUMBER`) is set to " +
+			"the hexadecimal representation of the SHA256 fingerprint of the public key, " +
+			"in PKIX `SubjectPublicKeyInfo` DER format: this is common for device certificates, " +
+			"to tie the identity of the device to its key. " +
+			"This is _mutually exclusive_ with `subject.serial_number` (default: `false`).",
+	}
+
+	s["subject_rdn_order"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedSubjectAttributeTypes(), false)),
+		},
+		Description: "Order in which the listed attributes of `subject` are encoded in the subject distinguished name, " +
+			"ahead of any other attribute (that keeps its default order: `country`, `province`, `locality`, " +
+			"`street_address`, `postal_code`, `organization`, `organizational_unit`, `common_name`, `serial_number`). " +
+			"The order doesn't change the meaning of the subject: " +
+			"this is only intended for systems that compare subjects byte by byte. " +
+			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedSubjectAttributeTypes(), "`, `")),
+	}
+}
+
+// supportedSubjectAttributeTypes returns a sorted slice with all the keys in subjectAttributeTypes.
+func supportedSubjectAttributeTypes() []string {
+	res := make([]string, 0, len(subjectAttributeTypes))
+
+	for k := range subjectAttributeTypes {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// setCertificateCommonSchema sets on the given reference to map of schema.Schema
+// all the keys required by a resource representing a certificate.
+func setCertificateCommonSchema(s map[string]*schema.Schema) {
+	s["validity_period_hours"] = &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		Computed:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		ConflictsWith:    []string{"validity_period_days"},
+		Description: "Number of hours, after initial issuing, that the certificate will remain valid for. " +
+			"If neither this nor `validity_period_days` is set, the provider `default_validity_period_hours` is used: " +
+			"one of the three must be set. When the validity period is given in days, this is set to the equivalent number of hours.",
+	}
+
+	s["validity_period_days"] = &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		ConflictsWith:    []string{"validity_period_hours"},
+		Description: "Number of days, after initial issuing, that the certificate will remain valid for: " +
+			"this is an alternative to `validity_period_hours`, more convenient for long-lived certificates " +
+			"(e.g. `825` days). A day is always counted as 24 hours.",
+	}
+
+	s["round_validity_to_day"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Default:  false,
+		Description: "Round the end of the validity period of the certificate down to the end of a day in UTC " +
+			"(i.e. `23:59:59Z`, as the last second is included in the validity period), " +
+			"so that it never exceeds the requested validity period: useful to comply with maximum validity periods " +
+			"counted in days, like those of the CA/Browser Forum (default: `false`).",
+	}
+
+	s["signature_algorithm"] = signatureAlgorithmSchema("certificate")
+
+	s["early_renewal_hours"] = &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		Default:          0,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		Description: "The resource will consider the certificate to have expired the given number of hours " +
+			"before its actual expiry time. This can be useful to deploy an updated certificate in advance of " +
+			"the expiration of the current certificate. " +
+			"However, the old certificate remains valid until its true expiration time, since this resource " +
+			"does not (and cannot) support certificate revocation. " +
+			"Also, this advance update can only be performed should the Terraform configuration be applied " +
+			"during the early renewal period. (default: `0`)",
+	}
+
+	s["is_ca_certificate"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "Is the generated certificate representing a Certificate Authority (CA) (default: `false`).",
+	}
+
+	s["max_path_length"] = &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		Description: "Maximum number of intermediate CA certificates that may follow this one in a certification path, " +
+			"set as the `pathLenConstraint` of the " +
+			"[basic constraints](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.9): " +
+			"`0` means that this CA can only issue end-entity certificates. " +
+			"If not set, the path length is unlimited. " +
+			"It can only be set when `is_ca_certificate` is `true`.",
+	}
+
+	s["allowed_uses"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: StringInSliceOrWarn(supportedKeyUsages(), false),
+		},
+		Description: "List of key usages allowed for the issued certificate. " +
+			"Values are defined in [RFC 5280](https://datatracker.ietf.org/doc/html/rfc5280) " +
+			"and combine flags defined by both " +
+			"[Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.3) " +
+			"and [Extended Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.12). " +
+			"Key usages are ignored if `key_usages` is set, and extended key usages are ignored if " +
+			"`extended_key_usages` is set. " +
+			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedKeyUsages(), "`, `")),
+	}
+
+	s["key_usages"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedBasicKeyUsages(), false)),
+		},
+		Description: "List of [Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.3) " +
+			"allowed for the issued certificate. When set, this takes precedence over " +
+			"the key usages listed in `allowed_uses`. " +
+			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedBasicKeyUsages(), "`, `")),
+	}
+
+	s["extended_key_usages"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedExtendedKeyUsages(), false)),
+		},
+		Description: "List of [Extended Key Usages](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.12) " +
+			"allowed for the issued certificate. When set, this takes precedence over " +
+			"the extended key usages listed in `allowed_uses`. " +
+			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedExtendedKeyUsages(), "`, `")),
+	}
+
+	s["early_renewal_jitter_hours"] = &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		Default:          0,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		Description: "Maximum number of hours to add to `early_renewal_hours`, so that certificates sharing the same " +
+			"`early_renewal_hours` are not all renewed at once. The added amount is derived from the resource ID: " +
+			"it is different for every certificate, but it does not change between applies. (default: `0`)",
+	}
+
+	s["write_to"] = writeToSchema("cert_pem", "0644")
+
+	s["cert_pem"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Certificate data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
+			"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
+			"value append a `\\n` at the end of the PEM. " +
+			"In case this disrupts your use case, we recommend using " +
+			"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+	}
+
+	s["cert_pem_base64"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The whole `cert_pem` (i.e. the PEM text, not the DER data) encoded in base64, on a single line. " +
+			"This is useful to inject the certificate into environment variables or Kubernetes secrets.",
+	}
+
+	s["cert_pem_base64url"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The whole `cert_pem` encoded in base64url without padding " +
+			"([RFC 4648 §5](https://datatracker.ietf.org/doc/html/rfc4648#section-5)), on a single line: " +
+			"this is the same as `cert_pem_base64`, but safe to embed in URLs and JWTs.",
+	}
+
+	s["cert_thumbprint_sha1"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The SHA1 thumbprint of the certificate, computed over its DER encoding, in uppercase hexadecimal " +
+			"without separators, as cloud APIs reference certificates by (e.g. Azure Key Vault and App Service).",
+	}
+
+	s["cert_thumbprint_sha256"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The SHA256 thumbprint of the certificate, computed over its DER encoding, in uppercase hexadecimal " +
+			"without separators. This is the same hash as `content_hash`, that is in lowercase hexadecimal instead.",
+	}
+
+	s["content_hash"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Hexadecimal representation of the SHA256 checksum of the certificate, in DER format. " +
+			"It only changes when a new certificate is generated, so it can be used to trigger " +
+			"other resources (e.g. via `replace_triggered_by`) only when the certificate actually changes.",
+	}
+
+	s["ready_for_renewal"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Computed: true,
+		Description: "Is the certificate either expired (i.e. beyond the `validity_period_hours`) " +
+			"or ready for an early renewal (i.e. within the `early_renewal_hours`)?",
+	}
+
+	s["validity_start_time"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The time after which the certificate is valid, " +
+			"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+	}
+
+	s["validity_end_time"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "The time until which the certificate is invalid, " +
+			"expressed as an [RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+	}
+
+	s["issuance_duration_ms"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+		Description: "How long, in milliseconds, the issuance of the certificate took: " +
+			"this includes loading (or generating) the signing key and signing the certificate. " +
+			"It's measured on a best-effort basis, as an aid to find which certificates slow down large applies " +
+			"(e.g. those signed with large RSA keys).",
+	}
+
+	s["set_subject_key_id"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Description: "Should the generated certificate include a " +
+			"[subject key identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.2) " +
+			"(default: `true` if `is_ca_certificate` is `true`, `false` otherwise). " +
+			"When explicitly set to `false`, no subject key identifier is included, even for CA certificates: " +
+			"in that case, the certificates signed by this one will have no " +
+			"[authority key identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.1) derived from it.",
+	}
+
+	s["basic_constraints_critical"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Description: "Should the [basic constraints](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.9) " +
+			"extension of the generated certificate be marked as critical " +
+			"(default: `true` if `is_ca_certificate` is `true`, `false` otherwise). " +
+			"RFC 5280 requires it to be critical for CA certificates: " +
+			"set this to `false` only for interoperability with clients that do not support it. " +
+			"The extension is always included, with `CA:FALSE` for certificates that are not CAs, " +
+			"unless `minimal_profile` is `true` and this is not set: " +
+			"set this to `true` to have it critical on those too, as some strict validators require.",
+	}
+
+	s["minimal_profile"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Default:  false,
+		Description: "When `true`, the optional extensions that are otherwise added by default are left out, " +
+			"to produce the smallest possible certificate (e.g. for constrained devices): " +
+			"the subject key identifier of CA certificates (unless `set_subject_key_id` is explicitly `true`), " +
+			"the authority key identifier derived from the issuer, and the basic constraints of certificates " +
+			"that are not CAs (unless `basic_constraints_critical` is explicitly set). " +
+			"Only the extensions that are explicitly requested, like the key usages and the subject alternative names, " +
+			"are included (default: `false`).",
+	}
+
+	s["not_before"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+		Description: "Fixed time after which the certificate is valid, expressed as an " +
+			"[RFC3339](https://tools.ietf.org/html/rfc3339) timestamp, instead of the time of creation. " +
+			"When set, the certificate is deterministic: given identical inputs, the same certificate is produced, " +
+			"byte for byte, every time it is created. To achieve this, unless set via `certificate_serial_hex`, " +
+			"the serial number is derived from the content of the certificate, and the signature must be deterministic: " +
+			"the `RSA-PSS` signature algorithms cannot be used, and the certificate cannot be signed with an `ECDSA` key. " +
+			"This is only intended for pinning the whole certificate, and for testing.",
+	}
+
+	s["certificate_serial_hex"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validateCertificateSerialHex),
+		Description: "Serial number to assign to the certificate, expressed as a hexadecimal string " +
+			"(e.g. `0a1b2c3d`). It must be a positive number and no longer than 20 octets, as required by " +
+			"[RFC 5280](https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.2). " +
+			"If not set, a random 128 bit serial number will be generated.",
+	}
+
+	s["issuer_unique_id"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validateUniqueIDHex),
+		Description: "[Issuer unique identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.8) " +
+			"to set in the certificate, expressed as a hexadecimal string (e.g. `0a1b2c3d`). " +
+			"RFC 5280 recommends against using unique identifiers: " +
+			"this is only intended for reproducing certificates in test suites.",
+	}
+
+	s["subject_unique_id"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validateUniqueIDHex),
+		Description: "[Subject unique identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2.8) " +
+			"to set in the certificate, expressed as a hexadecimal string (e.g. `0a1b2c3d`). " +
+			"RFC 5280 recommends against using unique identifiers: " +
+			"this is only intended for reproducing certificates in test suites.",
+	}
+
+	s["sct_list_base64"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+		},
+		Description: "List of [Signed Certificate Timestamps (RFC 6962)](https://datatracker.ietf.org/doc/html/rfc6962#section-3.2), " +
+			"each already serialized and then encoded in base64, to embed in the certificate " +
+			"via the SCT list extension (`1.3.6.1.4.1.11129.2.4.2`). " +
+			"The provider takes care of the length-prefixed encoding of the list. " +
+			"This is only intended for testing clients that are aware of Certificate Transparency.",
+	}
+
+	s["subject_info_access"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"access_method": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validateOID),
+					Description: "Object identifier of the access method, in dotted decimal notation " +
+						"(e.g. `1.3.6.1.5.5.7.48.5` for `id-ad-caRepository`, " +
+						"`1.3.6.1.5.5.7.48.3` for `id-ad-timeStamping`).",
+				},
+				"url": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					Description:      "URL where the service or information described by `access_method` can be accessed.",
+				},
+			},
+		},
+		Description: "List of access descriptions to embed in the certificate via the " +
+			"[Subject Information Access](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.2.2) " +
+			"extension (`1.3.6.1.5.5.7.1.11`), for example pointing at a CA repository or a time-stamping service.",
+	}
+
+	s["ocsp_servers"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https"})),
+		},
+		Description: "List of URLs of the OCSP responders of the issuer, to embed in the certificate via the " +
+			"[Authority Information Access](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.2.1) " +
+			"extension (`1.3.6.1.5.5.7.1.1`). " +
+			"The URLs are emitted in the given order, without sorting nor removing duplicates, " +
+			"as some clients only try the first one. " +
+			"Accepted schemes are: `http`, `https`.",
+	}
+
+	s["issuing_certificate_urls"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https", "ldap", "ldaps"})),
+		},
+		Description: "List of URLs where the certificate of the issuer can be retrieved, to embed in the certificate via the " +
+			"[Authority Information Access](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.2.1) " +
+			"extension (`1.3.6.1.5.5.7.1.1`), after the `ocsp_servers`. " +
+			"The URLs are emitted in the given order, without sorting nor removing duplicates. " +
+			"Accepted schemes are: `http`, `https`, `ldap`, `ldaps`.",
+	}
+
+	s["crl_distribution_points"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https", "ldap", "ldaps"})),
+		},
+		Description: "List of URLs where the CRLs of the issuer can be retrieved, to embed in the certificate via the " +
+			"[CRL Distribution Points](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.13) extension (`2.5.29.31`): " +
+			"each URL becomes a distribution point, identified by its full name. " +
+			"The URLs are emitted in the given order, without sorting nor removing duplicates. " +
+			"Accepted schemes are: `http`, `https`, `ldap`, `ldaps`.",
+	}
+
+	s["freshest_crl_distribution_points"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https", "ldap", "ldaps"})),
+		},
+		Description: "List of URLs where the delta CRLs of the issuer can be retrieved, to embed in the certificate via the " +
+			"[Freshest CRL](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.15) extension (`2.5.29.46`). " +
+			"This is structurally identical to the CRL Distribution Points extension: " +
+			"each URL becomes a distribution point, identified by its full name. " +
+			"Accepted schemes are: `http`, `https`, `ldap`, `ldaps`.",
+	}
+
+	s["certificate_policy"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"policy_identifier": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validateOID),
+					Description: "Object identifier of the policy, in dotted decimal notation " +
+						"(e.g. `2.23.140.1.2.1` for the CA/Browser Forum domain-validated policy, `2.5.29.32.0` for `anyPolicy`).",
+				},
+				"cps_uris": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https"})),
+					},
+					Description: "List of URLs of the Certification Practice Statement (CPS) published for the policy, " +
+						"each emitted as a CPS Pointer qualifier, in the given order. " +
+						"Accepted schemes are: `http`, `https`.",
+				},
+				"user_notice_text": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 200)),
+					Description: "Text to display to relying parties when the certificate is used, " +
+						"emitted as the explicit text of a User Notice qualifier, after the CPS Pointer ones. " +
+						"It is encoded as a `UTF8String`, and limited to 200 characters as required by RFC 5280.",
+				},
+			},
+		},
+		Description: "List of policies to embed in the certificate via the " +
+			"[Certificate Policies](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.4) " +
+			"extension (`2.5.29.32`), in the given order, each optionally with its qualifiers.",
+	}
+
+	s["extension_order"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedCertificateExtensions(), false)),
+		},
+		Description: "Order in which the listed extensions are emitted in the certificate, " +
+			"ahead of any other extension (that keeps its default order). " +
+			"Extensions that are not present in the certificate are ignored. " +
+			"This is only intended for interoperability testing with parsers that are sensitive to the order of the extensions. " +
+			fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedCertificateExtensions(), "`, `")),
+	}
+
+	s["name_constraints"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"permitted_dns_domains": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of DNS domains the certificates in the chain are permitted to be issued for " +
+						"(e.g. `example.com` matches `example.com` and all its subdomains, `.example.com` only its subdomains).",
+				},
+				"excluded_dns_domains": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of DNS domains the certificates in the chain are not permitted to be issued for, " +
+						"using the same syntax as `permitted_dns_domains`.",
+				},
+				"permitted_ip_ranges": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IsCIDR),
+					},
+					Description: "List of IP address ranges, in CIDR notation (e.g. `10.0.0.0/8`), " +
+						"the certificates in the chain are permitted to be issued for.",
+				},
+				"excluded_ip_ranges": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IsCIDR),
+					},
+					Description: "List of IP address ranges, in CIDR notation (e.g. `10.0.0.0/8`), " +
+						"the certificates in the chain are not permitted to be issued for.",
+				},
+				"permitted_email_addresses": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of email addresses or domains (e.g. `user@example.com`, `example.com` or `.example.com`) " +
+						"the certificates in the chain are permitted to be issued for.",
+				},
+				"excluded_email_addresses": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of email addresses or domains the certificates in the chain are not permitted to be issued for, " +
+						"using the same syntax as `permitted_email_addresses`.",
+				},
+				"permitted_uri_domains": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of domains (e.g. `example.com` or `.example.com`) the hosts of the URIs " +
+						"of the certificates in the chain are permitted to belong to.",
+				},
+				"excluded_uri_domains": {
+					Type:     schema.TypeList,
+					Optional: true,
+					ForceNew: true,
+					Elem: &schema.Schema{
+						Type:             schema.TypeString,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					},
+					Description: "List of domains the hosts of the URIs of the certificates in the chain are not permitted to belong to, " +
+						"using the same syntax as `permitted_uri_domains`.",
+				},
+				"critical": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					ForceNew: true,
+					Default:  true,
+					Description: "Should the name constraints extension be marked as critical, " +
+						"as required by [RFC 5280](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.10) " +
+						"(default: `true`).",
+				},
+			},
+		},
+		Description: "The [name constraints](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.10) " +
+			"restricting the names of the certificates issued by this one (and by its subordinate CAs), " +
+			"for example when cross-signing another Certificate Authority (CA). " +
+			"It can only be set when `is_ca_certificate` is `true`. " +
+			"A warning is raised if the DNS names of the certificate itself violate its DNS name constraints, " +
+			"as that is most likely a misconfiguration.",
+	}
+
+	s["id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Unique identifier for this resource: the certificate serial number.",
+	}
+}
+
+// errMissingValidityPeriod is reported when the validity period of a certificate is set neither
+// in the configuration of the resource nor in the one of the provider.
+var errMissingValidityPeriod = fmt.Errorf("missing validity period: either set 'validity_period_hours', 'validity_period_days' or the provider 'default_validity_period_hours'")
+
+// customizeValidityPeriodDiff checks at plan time that the validity period of the certificate can be resolved,
+// the same way createCertificate does at apply time.
+// The check is only done when the certificate is about to be created, as the validity period is then stored in the state.
+func customizeValidityPeriodDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config, ok := m.(*providerConfig)
+	if !ok || d.Id() != "" {
+		return nil
+	}
+
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+
+	if rawConfig.GetAttr("validity_period_hours").IsNull() && rawConfig.GetAttr("validity_period_days").IsNull() &&
+		config.defaultValidityPeriodHours <= 0 {
+		return errMissingValidityPeriod
+	}
+	return nil
+}
+
+func createCertificate(d *schema.ResourceData, config *providerConfig, template, parent *x509.Certificate, pub crypto.PublicKey, prv interface{}) diag.Diagnostics {
+	var err error
+	var diags diag.Diagnostics
+
+	// Resolve the validity period, given either in hours or in days, falling back to the provider default
+	var validityPeriodHours int
+	if !d.GetRawConfig().GetAttr("validity_period_hours").IsNull() {
+		validityPeriodHours = d.Get("validity_period_hours").(int)
+	} else if !d.GetRawConfig().GetAttr("validity_period_days").IsNull() {
+		validityPeriodHours = d.Get("validity_period_days").(int) * 24
+	} else if config.defaultValidityPeriodHours > 0 {
+		validityPeriodHours = config.defaultValidityPeriodHours
+	} else {
+		return diag.FromErr(errMissingValidityPeriod)
+	}
+	if err := d.Set("validity_period_hours", validityPeriodHours); err != nil {
+		return diag.Errorf("error setting value on key 'validity_period_hours': %s", err)
+	}
+
+	// With a fixed `not_before`, the certificate is deterministic: this requires the signature
+	// to be deterministic as well, that the crypto/* signers provide when no source of randomness is given
+	random := rand.Reader
+	template.NotBefore = overridableTimeFunc()
+	if notBefore, ok := d.GetOk("not_before"); ok {
+		random = nil
+		template.NotBefore, err = time.Parse(time.RFC3339, notBefore.(string))
+		if err != nil {
+			return diag.Errorf("invalid not_before: %s", err)
+		}
+	}
+	template.NotAfter = template.NotBefore.Add(time.Duration(validityPeriodHours) * time.Hour)
+	if d.Get("round_validity_to_day").(bool) {
+		template.NotAfter = roundDownToEndOfDay(template.NotAfter)
+		if !template.NotAfter.After(template.NotBefore) {
+			return diag.Errorf("round_validity_to_day: the validity period doesn't include the end of any day in UTC, "+
+				"so rounding its end down to %s would leave no validity at all",
+				template.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	serialNumberFile, serialFromFile := d.GetOk("serial_number_file")
+	if serialHex, ok := d.GetOk("certificate_serial_hex"); ok {
+		template.SerialNumber, err = parseCertificateSerialHex(serialHex.(string))
+		if err != nil {
+			return diag.Errorf("invalid certificate_serial_hex: %s", err)
+		}
+	} else if template.SerialNumber == nil && random != nil && !serialFromFile {
+		serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+		template.SerialNumber, err = rand.Int(rand.Reader, serialNumberLimit)
+		if err != nil {
+			return diag.Errorf("failed to generate serial number: %s", err)
+		}
+	}
+
+	// `key_usages` and `extended_key_usages`, when set, take precedence
+	// over the respective flags listed in `allowed_uses`
+	keyUsesI := d.Get("allowed_uses").([]interface{})
+	for _, keyUseI := range keyUsesI {
+		keyUse := keyUseI.(string)
+		if usage, ok := keyUsages[keyUse]; ok {
+			template.KeyUsage |= usage
+		}
+		if usage, ok := extendedKeyUsages[keyUse]; ok {
+			template.ExtKeyUsage = append(template.ExtKeyUsage, usage)
+		}
+	}
+	if keyUsesI, ok := d.GetOk("key_usages"); ok {
+		template.KeyUsage = 0
+		for _, keyUseI := range keyUsesI.([]interface{}) {
+			template.KeyUsage |= keyUsages[keyUseI.(string)]
+		}
+	}
+	if extKeyUsesI, ok := d.GetOk("extended_key_usages"); ok {
+		template.ExtKeyUsage = nil
+		for _, extKeyUseI := range extKeyUsesI.([]interface{}) {
+			template.ExtKeyUsage = append(template.ExtKeyUsage, extendedKeyUsages[extKeyUseI.(string)])
+		}
+	}
+
+	// CA certificates get a subject key identifier by default, unless `set_subject_key_id`
+	// is explicitly set to `false`, or `minimal_profile` is set
+	minimalProfile := d.Get("minimal_profile").(bool)
+	setSubjectKeyID := d.Get("is_ca_certificate").(bool) && !minimalProfile
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("set_subject_key_id").IsNull() {
+		setSubjectKeyID = d.Get("set_subject_key_id").(bool)
+	}
+
+	if d.Get("is_ca_certificate").(bool) {
+		template.IsCA = true
+	}
+
+	// GOTCHA: a `max_path_length` of `0` is different from an unset one (i.e. unlimited),
+	// so it is read from the raw configuration
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("max_path_length").IsNull() {
+		template.MaxPathLen = d.Get("max_path_length").(int)
+		template.MaxPathLenZero = template.MaxPathLen == 0
+	}
+
+	if err := config.checkUsagePolicy(template.IsCA, certificateUsages(template)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if setSubjectKeyID {
+		template.SubjectKeyId, err = generateSubjectKeyID(pub)
+		if err != nil {
+			return diag.Errorf("failed to set subject key identifier: %s", err)
+		}
+	}
+
+	if ncI := d.Get("name_constraints").([]interface{}); len(ncI) > 0 && ncI[0] != nil {
+		nc := ncI[0].(map[string]interface{})
+
+		template.PermittedDNSDomainsCritical = nc["critical"].(bool)
+		template.PermittedDNSDomains = toStringSlice(nc["permitted_dns_domains"].([]interface{}))
+		template.ExcludedDNSDomains = toStringSlice(nc["excluded_dns_domains"].([]interface{}))
+		template.PermittedEmailAddresses = toStringSlice(nc["permitted_email_addresses"].([]interface{}))
+		template.ExcludedEmailAddresses = toStringSlice(nc["excluded_email_addresses"].([]interface{}))
+		template.PermittedURIDomains = toStringSlice(nc["permitted_uri_domains"].([]interface{}))
+		template.ExcludedURIDomains = toStringSlice(nc["excluded_uri_domains"].([]interface{}))
+
+		template.PermittedIPRanges, err = parseIPRanges(nc["permitted_ip_ranges"].([]interface{}))
+		if err != nil {
+			return diag.Errorf("invalid name_constraints.0.permitted_ip_ranges: %s", err)
+		}
+		template.ExcludedIPRanges, err = parseIPRanges(nc["excluded_ip_ranges"].([]interface{}))
+		if err != nil {
+			return diag.Errorf("invalid name_constraints.0.excluded_ip_ranges: %s", err)
+		}
+
+		// NOTE: name constraints only apply to the certificates issued by this one, so this is not invalid per se,
+		// but a CA excluding its own names is almost certainly misconfigured
+		if violations := nameConstraintsDNSViolations(template); len(violations) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Subject alternative names violate the certificate's own name constraints",
+				Detail: "The name constraints only apply to the certificates issued by this one, " +
+					"but a CA whose own DNS names fall outside them is most likely misconfigured: " +
+					strings.Join(violations, ", ") + ".",
+			})
+		}
+	}
+
+	if sctsI := d.Get("sct_list_base64").([]interface{}); len(sctsI) > 0 {
+		scts := make([][]byte, len(sctsI))
+		for i, sctI := range sctsI {
+			scts[i], err = base64.StdEncoding.DecodeString(sctI.(string))
+			if err != nil {
+				return diag.Errorf("invalid Signed Certificate Timestamp at sct_list_base64.%d: %s", i, err)
+			}
+		}
+
+		sctListExt, err := marshalSCTListExtension(scts)
+		if err != nil {
+			return diag.Errorf("failed to marshal Signed Certificate Timestamp list extension: %s", err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, sctListExt)
+	}
+
+	if siaI := d.Get("subject_info_access").([]interface{}); len(siaI) > 0 {
+		descriptions := make([]accessDescription, len(siaI))
+		for i, adI := range siaI {
+			ad := adI.(map[string]interface{})
+
+			descriptions[i].Method, err = parseOID(ad["access_method"].(string))
+			if err != nil {
+				return diag.Errorf("invalid subject_info_access.%d.access_method: %s", i, err)
+			}
+
+			adURL, err := url.Parse(ad["url"].(string))
+			if err != nil {
+				return diag.Errorf("invalid subject_info_access.%d.url: %s", i, err)
+			}
+			descriptions[i].Location = asn1.RawValue{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(adURL.String())}
+		}
+
+		siaExt, err := marshalSubjectInfoAccessExtension(descriptions)
+		if err != nil {
+			return diag.Errorf("failed to marshal Subject Information Access extension: %s", err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, siaExt)
+	}
+
+	// NOTE: x509.CreateCertificate emits these URLs in the order they are given,
+	// so they are passed through as configured, without sorting or deduplication
+	template.OCSPServer = toStringSlice(d.Get("ocsp_servers").([]interface{}))
+	template.IssuingCertificateURL = toStringSlice(d.Get("issuing_certificate_urls").([]interface{}))
+	template.CRLDistributionPoints = toStringSlice(d.Get("crl_distribution_points").([]interface{}))
+
+	if crlDPsI := d.Get("freshest_crl_distribution_points").([]interface{}); len(crlDPsI) > 0 {
+		freshestCRLExt, err := marshalFreshestCRLExtension(toStringSlice(crlDPsI))
+		if err != nil {
+			return diag.Errorf("failed to marshal Freshest CRL extension: %s", err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, freshestCRLExt)
+	}
+
+	if policiesI := d.Get("certificate_policy").([]interface{}); len(policiesI) > 0 {
+		policies := make([]certificatePolicy, len(policiesI))
+		for i, policyI := range policiesI {
+			policy := policyI.(map[string]interface{})
+
+			policies[i].identifier, err = parseOID(policy["policy_identifier"].(string))
+			if err != nil {
+				return diag.Errorf("invalid certificate_policy.%d.policy_identifier: %s", i, err)
+			}
+			policies[i].cpsURIs = toStringSlice(policy["cps_uris"].([]interface{}))
+			policies[i].userNoticeText = policy["user_notice_text"].(string)
+		}
+
+		policiesExt, err := marshalCertificatePoliciesExtension(policies)
+		if err != nil {
+			return diag.Errorf("failed to marshal Certificate Policies extension: %s", err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, policiesExt)
+	}
+
+	if sigAlg, ok := d.GetOk("signature_algorithm"); ok {
+		if err := checkSignatureAlgorithmForKey(sigAlg.(string), prv); err != nil {
+			return diag.FromErr(err)
+		}
+		template.SignatureAlgorithm = signatureAlgorithms[sigAlg.(string)]
 
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	template.SerialNumber, err = rand.Int(rand.Reader, serialNumberLimit)
-	if err != nil {
-		return diag.Errorf("failed to generate serial number: %s", err)
+		switch template.SignatureAlgorithm {
+		case x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS:
+			if random == nil {
+				return diag.Errorf("signature_algorithm %s cannot be used with not_before: RSA-PSS signatures are not deterministic", sigAlg)
+			}
+		}
 	}
 
-	keyUsesI := d.Get("allowed_uses").([]interface{})
-	for _, keyUseI := range keyUsesI {
-		keyUse := keyUseI.(string)
-		if usage, ok := keyUsages[keyUse]; ok {
-			template.KeyUsage |= usage
+	// GOTCHA: ECDSA signatures are randomized and, before Go 1.24, crypto/ecdsa can't even sign without a source of randomness
+	if random == nil {
+		if keyAlgorithm, err := privateKeyToAlgorithm(prv); err == nil && keyAlgorithm == ECDSA {
+			return diag.Errorf("not_before cannot be used with %s keys: %s signatures are not deterministic", keyAlgorithm, keyAlgorithm)
 		}
-		if usage, ok := extendedKeyUsages[keyUse]; ok {
-			template.ExtKeyUsage = append(template.ExtKeyUsage, usage)
+	}
+
+	// Basic constraints are included in all certificates (with `CA:FALSE` for leaf ones),
+	// and are critical for CA certificates by default, unless `basic_constraints_critical` is explicitly set
+	basicConstraintsCritical := template.IsCA
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("basic_constraints_critical").IsNull() {
+		basicConstraintsCritical = d.Get("basic_constraints_critical").(bool)
+	} else if minimalProfile && !template.IsCA {
+		template.BasicConstraintsValid = false
+	}
+
+	// GOTCHA: x509.CreateCertificate always marks the basic constraints as critical,
+	// and generates a subject key identifier for CA certificates that lack one:
+	// to control both, the basic constraints are encoded here, and removed from the template
+	if template.BasicConstraintsValid {
+		basicConstraintsExt, err := marshalBasicConstraintsExtension(template.IsCA, template.MaxPathLen, template.MaxPathLenZero, basicConstraintsCritical)
+		if err != nil {
+			return diag.Errorf("failed to marshal Basic Constraints extension: %s", err)
 		}
+		template.ExtraExtensions = append(template.ExtraExtensions, basicConstraintsExt)
+
+		template.IsCA = false
+		template.BasicConstraintsValid = false
+		template.MaxPathLen = 0
+		template.MaxPathLenZero = false
 	}
 
-	if d.Get("is_ca_certificate").(bool) {
-		template.IsCA = true
+	// GOTCHA: x509.CreateCertificate derives the authority key identifier from the subject key identifier
+	// of the parent, when the certificate is not self-signed: to leave it out, the parent is copied without it
+	if minimalProfile && len(parent.SubjectKeyId) > 0 && parent != template {
+		parentWithoutSKI := *parent
+		parentWithoutSKI.SubjectKeyId = nil
+		parent = &parentWithoutSKI
+	}
 
-		template.SubjectKeyId, err = generateSubjectKeyID(pub)
+	// The serial number is taken from `serial_number_file` (of tls_locally_signed_cert) only right before signing,
+	// so that an apply failing any of the checks above doesn't consume one
+	if serialFromFile {
+		template.SerialNumber, err = nextSerialNumberFromFile(serialNumberFile.(string))
 		if err != nil {
-			return diag.Errorf("failed to set subject key identifier: %s", err)
+			return diag.Errorf("failed to assign serial number from serial_number_file: %s", err)
 		}
 	}
 
-	if d.Get("set_subject_key_id").(bool) {
-		template.SubjectKeyId, err = generateSubjectKeyID(pub)
+	// With a fixed `not_before`, the serial number (unless given) is derived from the content of the certificate
+	if template.SerialNumber == nil {
+		template.SerialNumber, err = deterministicSerialNumber(template, parent, pub, prv)
 		if err != nil {
-			return diag.Errorf("failed to set subject key identifier: %s", err)
+			return diag.Errorf("failed to derive serial number: %s", err)
 		}
 	}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, pub, prv)
+	certBytes, err := x509.CreateCertificate(random, template, parent, pub, prv)
 	if err != nil {
 		return diag.Errorf("error creating certificate: %s", err)
 	}
+
+	// GOTCHA: x509.CreateCertificate emits the extensions it builds in a fixed order, followed by
+	// the `ExtraExtensions`, and skips those it builds that are already in `ExtraExtensions`:
+	// to control the order, the certificate is created again with all its extensions,
+	// reordered, as `ExtraExtensions`
+	if orderI := d.Get("extension_order").([]interface{}); len(orderI) > 0 {
+		cert, err := x509.ParseCertificate(certBytes)
+		if err != nil {
+			return diag.Errorf("error parsing certificate: %s", err)
+		}
+
+		template.ExtraExtensions = orderExtensions(cert.Extensions, toStringSlice(orderI))
+		certBytes, err = x509.CreateCertificate(random, template, parent, pub, prv)
+		if err != nil {
+			return diag.Errorf("error creating certificate: %s", err)
+		}
+	}
+
+	// GOTCHA: x509.CreateCertificate doesn't support the unique identifiers of the certificate:
+	// when set, these are added to the TBSCertificate, and the certificate is signed again
+	issuerUniqueID, _ := hex.DecodeString(d.Get("issuer_unique_id").(string))
+	subjectUniqueID, _ := hex.DecodeString(d.Get("subject_unique_id").(string))
+	if len(issuerUniqueID) > 0 || len(subjectUniqueID) > 0 {
+		certBytes, err = setCertificateUniqueIDs(random, certBytes, issuerUniqueID, subjectUniqueID, prv)
+		if err != nil {
+			return diag.Errorf("error setting unique identifiers of certificate: %s", err)
+		}
+	}
+
 	certPem := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: certBytes}))
 
 	validFromBytes, err := template.NotBefore.MarshalText()
@@ -383,10 +2391,29 @@ func createCertificate(d *schema.ResourceData, template, parent *x509.Certificat
 		return diag.Errorf("error serializing validity_end_time: %s", err)
 	}
 
+	if err := writeToFile(d, []byte(certPem)); err != nil {
+		return diag.Errorf("error writing certificate to write_to: %s", err)
+	}
+
 	d.SetId(template.SerialNumber.String())
 	if err := d.Set("cert_pem", certPem); err != nil {
 		return diag.Errorf("error setting value on key 'cert_pem': %s", err)
 	}
+	if err := d.Set("cert_pem_base64", base64.StdEncoding.EncodeToString([]byte(certPem))); err != nil {
+		return diag.Errorf("error setting value on key 'cert_pem_base64': %s", err)
+	}
+	if err := d.Set("cert_pem_base64url", base64.RawURLEncoding.EncodeToString([]byte(certPem))); err != nil {
+		return diag.Errorf("error setting value on key 'cert_pem_base64url': %s", err)
+	}
+	if err := d.Set("cert_thumbprint_sha1", fmt.Sprintf("%X", sha1.Sum(certBytes))); err != nil {
+		return diag.Errorf("error setting value on key 'cert_thumbprint_sha1': %s", err)
+	}
+	if err := d.Set("cert_thumbprint_sha256", fmt.Sprintf("%X", sha256.Sum256(certBytes))); err != nil {
+		return diag.Errorf("error setting value on key 'cert_thumbprint_sha256': %s", err)
+	}
+	if err := d.Set("content_hash", contentHash(certBytes)); err != nil {
+		return diag.Errorf("error setting value on key 'content_hash': %s", err)
+	}
 	if err := d.Set("ready_for_renewal", false); err != nil {
 		return diag.Errorf("error setting value on key 'ready_for_renewal': %s", err)
 	}
@@ -397,6 +2424,15 @@ func createCertificate(d *schema.ResourceData, template, parent *x509.Certificat
 		return diag.Errorf("error setting value on key 'validity_end_time': %s", err)
 	}
 
+	return diags
+}
+
+// setIssuanceDuration sets `issuance_duration_ms` to the time elapsed since the issuance started.
+func setIssuanceDuration(d *schema.ResourceData, issuanceStart time.Time) diag.Diagnostics {
+	// NOTE: this measures actual elapsed time, so it doesn't use overridableTimeFunc
+	if err := d.Set("issuance_duration_ms", int(time.Since(issuanceStart).Milliseconds())); err != nil {
+		return diag.Errorf("error setting value on key 'issuance_duration_ms': %s", err)
+	}
 	return nil
 }
 
@@ -420,8 +2456,9 @@ func customizeCertificateDiff(_ context.Context, d *schema.ResourceDiff, _ inter
 		readyForRenewal = true
 	} else {
 		earlyRenewalHours := d.Get("early_renewal_hours").(int)
-		earlyRenewalPeriod := time.Duration(-earlyRenewalHours) * time.Hour
-		endTime = endTime.Add(earlyRenewalPeriod)
+		earlyRenewalPeriod := time.Duration(earlyRenewalHours) * time.Hour
+		earlyRenewalPeriod += earlyRenewalJitter(d.Id(), d.Get("early_renewal_jitter_hours").(int))
+		endTime = endTime.Add(-earlyRenewalPeriod)
 
 		currentTime := overridableTimeFunc()
 		timeToRenewal := endTime.Sub(currentTime)
@@ -444,6 +2481,130 @@ func customizeCertificateDiff(_ context.Context, d *schema.ResourceDiff, _ inter
 	return nil
 }
 
+// customizeNameConstraintsDiff checks that `name_constraints` is only set on CA certificates,
+// as name constraints are ignored by clients on any other certificate.
+func customizeNameConstraintsDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	// Values not yet known at plan time are validated at apply time
+	if !d.NewValueKnown("is_ca_certificate") || !d.NewValueKnown("name_constraints") {
+		return nil
+	}
+
+	if len(d.Get("name_constraints").([]interface{})) > 0 && !d.Get("is_ca_certificate").(bool) {
+		return fmt.Errorf("name_constraints can only be set when is_ca_certificate is true")
+	}
+
+	return nil
+}
+
+// customizeMaxPathLengthDiff checks that `max_path_length` is only set on CA certificates,
+// as the path length constraint is meaningless on any other certificate.
+func customizeMaxPathLengthDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	// Values not yet known at plan time are validated at apply time
+	if !d.NewValueKnown("is_ca_certificate") || !d.NewValueKnown("max_path_length") {
+		return nil
+	}
+
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("max_path_length").IsNull() && !d.Get("is_ca_certificate").(bool) {
+		return fmt.Errorf("max_path_length can only be set when is_ca_certificate is true")
+	}
+
+	return nil
+}
+
+// customizeSubjectAlternativeNamesDiff checks that the Subject Alternative Names given via
+// `dns_names`, `ip_addresses` and `uris` (or `san`) contain no duplicates, and that DNS names are valid host names
+// (unless `skip_dns_name_syntax_check` is set).
+func customizeSubjectAlternativeNamesDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	checkDNSNames := !d.Get("skip_dns_name_syntax_check").(bool)
+
+	for _, key := range []string{"dns_names", "ip_addresses", "uris"} {
+		values := config.GetAttr(key)
+		if values.IsNull() || !values.IsKnown() {
+			continue
+		}
+
+		seen := map[string]int{}
+		for i, it := 0, values.ElementIterator(); it.Next(); i++ {
+			// Values not yet known at plan time are validated at the next plan
+			_, v := it.Element()
+			if v.IsNull() || !v.IsKnown() {
+				continue
+			}
+			value := v.AsString()
+
+			// NOTE: IP addresses can be written in different forms (e.g. `::1` and `0:0:0:0:0:0:0:1`)
+			normalized := value
+			if ip := net.ParseIP(value); key == "ip_addresses" && ip != nil {
+				normalized = ip.String()
+			}
+
+			if j, ok := seen[normalized]; ok {
+				return fmt.Errorf("%s.%d: duplicate value %q, already present at %s.%d", key, i, value, key, j)
+			}
+			seen[normalized] = i
+
+			if key == "dns_names" && checkDNSNames {
+				if err := checkDNSNameSyntax(value); err != nil {
+					return fmt.Errorf("%s.%d: %q is not a valid DNS name: %w "+
+						"(set skip_dns_name_syntax_check to accept it anyway)", key, i, value, err)
+				}
+			}
+		}
+	}
+
+	sans := config.GetAttr("san")
+	if sans.IsNull() || !sans.IsKnown() {
+		return nil
+	}
+	seen := map[string]int{}
+	for i, it := 0, sans.ElementIterator(); it.Next(); i++ {
+		_, san := it.Element()
+		if san.IsNull() || !san.IsKnown() {
+			continue
+		}
+		sanTypeV, valueV := san.GetAttr("type"), san.GetAttr("value")
+		if sanTypeV.IsNull() || !sanTypeV.IsKnown() || valueV.IsNull() || !valueV.IsKnown() {
+			continue
+		}
+		sanType, value := SANType(sanTypeV.AsString()), valueV.AsString()
+
+		if err := checkSANValue(sanType, value, checkDNSNames); err != nil {
+			return fmt.Errorf("san.%d: %q is %w", i, value, err)
+		}
+
+		normalized := value
+		if ip := net.ParseIP(value); sanType == SANTypeIP && ip != nil {
+			normalized = ip.String()
+		}
+		normalized = sanType.String() + ":" + normalized
+
+		if j, ok := seen[normalized]; ok {
+			return fmt.Errorf("san.%d: duplicate %s %q, already present at san.%d", i, sanType, value, j)
+		}
+		seen[normalized] = i
+	}
+
+	return nil
+}
+
+// earlyRenewalJitter returns a duration between 0 and the given number of hours, derived from
+// the given resource ID: this way it is stable across plans of the same resource, but it spreads
+// over time the renewal of different resources.
+func earlyRenewalJitter(id string, jitterHours int) time.Duration {
+	if jitterHours <= 0 {
+		return 0
+	}
+
+	hash := sha256.Sum256([]byte(id))
+	jitterSeconds := binary.BigEndian.Uint64(hash[:8]) % uint64(jitterHours*3600+1)
+
+	return time.Duration(jitterSeconds) * time.Second
+}
+
 func updateCertificate(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	return nil
 }
@@ -487,24 +2648,106 @@ func distinguishedNamesFromSubjectAttributes(nameMap map[string]interface{}) *pk
 	return result
 }
 
-func parseCertificate(d *schema.ResourceData, pemKey string) (*x509.Certificate, error) {
-	block, err := decodePEM(d, pemKey, "")
-	if err != nil {
-		return nil, err
+// setCertificateNamesFromConfig sets on the given certificate template the subject and the SANs
+// configured via the keys set by setCertificateSubjectNamesSchema.
+func setCertificateNamesFromConfig(d *schema.ResourceData, cert *x509.Certificate, pubKey crypto.PublicKey) diag.Diagnostics {
+	if subjectConfs := d.Get("subject").([]interface{}); len(subjectConfs) > 0 {
+		subjectConf, ok := subjectConfs[0].(map[string]interface{})
+		if !ok {
+			return diag.Errorf("subject block cannot be empty")
+		}
+		cert.Subject = *distinguishedNamesFromSubjectAttributes(subjectConf)
 	}
 
-	certs, err := x509.ParseCertificates(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse %s: %s", pemKey, err)
+	if d.Get("subject_serial_from_key").(bool) {
+		serial, err := publicKeySPKISHA256(pubKey)
+		if err != nil {
+			return diag.Errorf("error computing fingerprint of public key for subject serial number: %s", err)
+		}
+		cert.Subject.SerialNumber = serial
+	}
+
+	// GOTCHA: `crypto/x509` encodes the subject in a fixed order:
+	// to control the order, the subject is encoded here and set as `RawSubject`
+	if orderI := d.Get("subject_rdn_order").([]interface{}); len(orderI) > 0 {
+		var err error
+		cert.RawSubject, err = marshalOrderedSubject(cert.Subject, toStringSlice(orderI))
+		if err != nil {
+			return diag.Errorf("error marshaling subject: %s", err)
+		}
+	}
+
+	dnsNamesI := d.Get("dns_names").([]interface{})
+	for _, nameI := range dnsNamesI {
+		cert.DNSNames = append(cert.DNSNames, nameI.(string))
+	}
+	ipAddressesI := d.Get("ip_addresses").([]interface{})
+	for _, ipStrI := range ipAddressesI {
+		ip := net.ParseIP(ipStrI.(string))
+		if ip == nil {
+			return diag.Errorf("invalid IP address %#v", ipStrI.(string))
+		}
+		cert.IPAddresses = append(cert.IPAddresses, ip)
+	}
+	urisI := d.Get("uris").([]interface{})
+	for _, uriStrI := range urisI {
+		uri, err := url.Parse(uriStrI.(string))
+		if err != nil {
+			return diag.Errorf("invalid URI %#v", uriStrI.(string))
+		}
+		cert.URIs = append(cert.URIs, uri)
+	}
+
+	// GOTCHA: `crypto/x509` encodes the SANs grouped by type:
+	// to keep the configured order, the extension is encoded here and replaces the generated one
+	if sansI := d.Get("san").([]interface{}); len(sansI) > 0 {
+		sans, err := parseOrderedSANs(sansI)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		cert.DNSNames, cert.EmailAddresses, cert.IPAddresses, cert.URIs = sans.dnsNames, sans.emailAddresses, sans.ipAddresses, sans.uris
+
+		sanExt, err := marshalSANExtension(sans.generalNames, cert.Subject)
+		if err != nil {
+			return diag.Errorf("failed to marshal subject alternative names extension: %s", err)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, sanExt)
 	}
-	if len(certs) < 1 {
-		return nil, fmt.Errorf("no certificates found in %s", pemKey)
+
+	return nil
+}
+
+// parseCertificatesPEM takes a slice of bytes containing one or more certificates
+// encoded in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format,
+// and returns them parsed. Any content that is not a PEM certificate is an error.
+func parseCertificatesPEM(certsPEMBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := bytes.TrimSpace(certsPEMBytes)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode PEM block")
+		}
+		if block.Type != PreambleCertificate.String() {
+			return nil, fmt.Errorf("invalid PEM type: %s", block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %s", err)
+		}
+		certs = append(certs, cert)
+
+		rest = bytes.TrimSpace(rest)
 	}
-	if len(certs) > 1 {
-		return nil, fmt.Errorf("multiple certificates found in %s", pemKey)
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found")
 	}
 
-	return certs[0], nil
+	return certs, nil
 }
 
 func parseCertificateRequest(d *schema.ResourceData, pemKey string) (*x509.CertificateRequest, error) {
@@ -521,12 +2764,38 @@ func parseCertificateRequest(d *schema.ResourceData, pemKey string) (*x509.Certi
 	return certReq, nil
 }
 
+// isWeakSignatureAlgorithm returns true if the given signature algorithm relies on
+// a hash function that is no longer considered collision resistant (i.e. MD2, MD5 or SHA-1).
+func isWeakSignatureAlgorithm(sigAlg x509.SignatureAlgorithm) bool {
+	switch sigAlg {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	default:
+		return false
+	}
+}
+
 func certificateToMap(cert *x509.Certificate) map[string]interface{} {
 	certPem := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: cert.Raw}))
 
+	ipAddresses := make([]string, len(cert.IPAddresses))
+	for i, ip := range cert.IPAddresses {
+		ipAddresses[i] = ip.String()
+	}
+
+	uris := make([]string, len(cert.URIs))
+	for i, uri := range cert.URIs {
+		uris[i] = uri.String()
+	}
+
+	rsaBits, ecdsaCurve := publicKeyParameters(cert.PublicKey)
+
 	return map[string]interface{}{
 		"signature_algorithm":  cert.SignatureAlgorithm.String(),
+		"uses_weak_signature":  isWeakSignatureAlgorithm(cert.SignatureAlgorithm),
 		"public_key_algorithm": cert.PublicKeyAlgorithm.String(),
+		"rsa_bits":             rsaBits,
+		"ecdsa_curve":          string(ecdsaCurve),
 		"serial_number":        cert.SerialNumber.String(),
 		"is_ca":                cert.IsCA,
 		"version":              cert.Version,
@@ -535,10 +2804,195 @@ func certificateToMap(cert *x509.Certificate) map[string]interface{} {
 		"not_before":           cert.NotBefore.Format(time.RFC3339),
 		"not_after":            cert.NotAfter.Format(time.RFC3339),
 		"sha1_fingerprint":     fmt.Sprintf("%x", sha1.Sum(cert.Raw)),
+		"dns_names":            cert.DNSNames,
+		"ip_addresses":         ipAddresses,
+		"uris":                 uris,
+		"email_addresses":      cert.EmailAddresses,
+		"other_name_sans":      otherNameSANsToList(cert),
 		"cert_pem":             certPem,
 	}
 }
 
+// Upper bounds of the subject attributes, in characters.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1.
+const (
+	ubCommonName             = 64
+	ubLocalityName           = 128
+	ubStateName              = 128
+	ubOrganizationName       = 64
+	ubOrganizationalUnitName = 64
+	ubSerialNumber           = 64
+	ubPostalCode             = 40
+	ubStreetAddress          = 128
+)
+
+// validateCountryName is a SchemaValidateDiagFunc which tests if the provided value
+// is a two letters country code (see https://datatracker.ietf.org/doc/html/rfc5280#appendix-A.1).
+//
+// As country codes are defined in uppercase by ISO 3166, lowercase letters produce a warning.
+var validateCountryName = validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if len(v) != 2 || !isASCIILetter(v[0]) || !isASCIILetter(v[1]) {
+		errors = append(errors, fmt.Errorf("expected %s to be a two letters country code, got %q", k, v))
+		return warnings, errors
+	}
+
+	if upper := strings.ToUpper(v); upper != v {
+		warnings = append(warnings, fmt.Sprintf("expected %s to be an uppercase country code (e.g. %q), got %q", k, upper, v))
+	}
+
+	return warnings, errors
+})
+
+// validateDNSName is a SchemaValidateDiagFunc which tests if the provided value
+// is of type string and looks like a DNS name (e.g. `example.com` or `*.example.com`),
+// pointing at `ip_addresses` or `uris` if the value belongs there instead.
+var validateDNSName = validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	switch {
+	case v == "":
+		errors = append(errors, fmt.Errorf("expected %s to not contain empty values", k))
+	case net.ParseIP(v) != nil:
+		errors = append(errors, fmt.Errorf("expected %s to contain DNS names, got IP address %q: use ip_addresses instead", k, v))
+	case strings.Contains(v, "://"):
+		errors = append(errors, fmt.Errorf("expected %s to contain DNS names, got URI %q: use uris instead", k, v))
+	case strings.ContainsAny(v, " \t\r\n"):
+		errors = append(errors, fmt.Errorf("expected %s to contain DNS names, got %q: whitespaces are not allowed", k, v))
+	case len(v) > 253:
+		errors = append(errors, fmt.Errorf("expected %s to contain DNS names of at most 253 characters, got %d", k, len(v)))
+	}
+
+	return warnings, errors
+})
+
+// checkDNSNameSyntax checks that the given DNS name is a valid host name (see RFC 1123, section 2.1),
+// optionally with a wildcard as its leftmost label, as accepted by clients in certificates.
+func checkDNSNameSyntax(dnsName string) error {
+	if strings.HasSuffix(dnsName, ".") {
+		return fmt.Errorf("trailing dots are not allowed")
+	}
+
+	labels := strings.Split(dnsName, ".")
+	for i, label := range labels {
+		switch {
+		case label == "*" && i == 0 && len(labels) > 1:
+			continue
+		case strings.Contains(label, "*"):
+			return fmt.Errorf("a wildcard can only be the whole leftmost label, followed by at least another label")
+		case label == "":
+			return fmt.Errorf("empty labels are not allowed")
+		case len(label) > 63:
+			return fmt.Errorf("label %q is longer than 63 characters", label)
+		case label[0] == '-' || label[len(label)-1] == '-':
+			return fmt.Errorf("label %q starts or ends with a hyphen", label)
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return fmt.Errorf("label %q contains %q: only letters, digits and hyphens are allowed, "+
+					"and internationalized names must be in their ASCII form (e.g. xn--bcher-kva.example)", label, c)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateIPAddress is a SchemaValidateDiagFunc which tests if the provided value
+// is of type string and is a single IP address, rejecting IP ranges in CIDR notation
+// (e.g. `10.0.0.0/8`), that only name constraints can contain.
+var validateIPAddress = validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if net.ParseIP(v) == nil {
+		if _, _, err := net.ParseCIDR(v); err == nil {
+			errors = append(errors, fmt.Errorf("expected %s to contain single IP addresses, got IP range %q in CIDR notation: "+
+				"IP ranges can only be used in name_constraints", k, v))
+		} else {
+			errors = append(errors, fmt.Errorf("expected %s to contain a valid IP, got: %s", k, v))
+		}
+	}
+
+	return warnings, errors
+})
+
+// validateURI is a SchemaValidateDiagFunc which tests if the provided value
+// is of type string and is an absolute URI (i.e. it has a scheme).
+var validateURI = validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	u, err := url.Parse(v)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("expected %s to contain valid URIs, got %q: %w", k, v, err))
+		return warnings, errors
+	}
+	if !u.IsAbs() {
+		errors = append(errors, fmt.Errorf("expected %s to contain absolute URIs, with a scheme, got %q", k, v))
+	}
+
+	return warnings, errors
+})
+
+// validateUserPrincipalName is a SchemaValidateDiagFunc which tests if the provided value
+// is of type string and has the form of a User Principal Name (i.e. `user@domain`).
+var validateUserPrincipalName = validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if at := strings.LastIndex(v, "@"); at <= 0 || at == len(v)-1 {
+		errors = append(errors, fmt.Errorf("expected %s to contain User Principal Names of the form 'user@domain', got %q", k, v))
+	}
+
+	return warnings, errors
+})
+
+// isASCIILetter reports whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// StringLenAtMostOrWarn returns a SchemaValidateFunc which tests if the provided value
+// is of type string and has at most max characters.
+//
+// Differently from validation.StringLenBetween, if the value is longer, a warning is produced.
+func StringLenAtMostOrWarn(max int) schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return warnings, errors
+		}
+
+		if l := utf8.RuneCountInString(v); l > max {
+			warnings = append(warnings, fmt.Sprintf("expected length of %s to be at most %d characters, got %d: some validators might reject the certificate", k, max, l))
+		}
+
+		return warnings, errors
+	})
+}
+
 // StringInSliceOrWarn returns a SchemaValidateFunc which tests if the provided value
 // is of type string and matches the value of an element in the valid slice.
 //
@@ -562,3 +3016,132 @@ func StringInSliceOrWarn(valid []string, ignoreCase bool) schema.SchemaValidateD
 		return warnings, errors
 	})
 }
+
+// toStringSlice converts a list of strings, as stored by schema.ResourceData, to a slice of strings.
+func toStringSlice(values []interface{}) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.(string)
+	}
+	return result
+}
+
+// parseIPRanges parses a list of IP address ranges in CIDR notation, as stored by schema.ResourceData.
+func parseIPRanges(values []interface{}) ([]*net.IPNet, error) {
+	var ipRanges []*net.IPNet
+	for i, value := range values {
+		_, ipRange, err := net.ParseCIDR(value.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP range at index %d: %w", i, err)
+		}
+		ipRanges = append(ipRanges, ipRange)
+	}
+	return ipRanges, nil
+}
+
+// marshalOrderedSubject marshals the given pkix.Name into an RDNSequence, with the relative distinguished names
+// of the attribute types listed in order first, followed by any other in the default order of `crypto/x509`.
+func marshalOrderedSubject(name pkix.Name, order []string) ([]byte, error) {
+	rdns := name.ToRDNSequence()
+	res := make(pkix.RDNSequence, 0, len(rdns))
+	added := make([]bool, len(rdns))
+
+	for _, typeName := range order {
+		for i, rdn := range rdns {
+			if !added[i] && rdn[0].Type.Equal(subjectAttributeTypes[typeName]) {
+				res = append(res, rdn)
+				added[i] = true
+			}
+		}
+	}
+	for i, rdn := range rdns {
+		if !added[i] {
+			res = append(res, rdn)
+		}
+	}
+
+	return asn1.Marshal(res)
+}
+
+// orderExtensions returns the given extensions, starting with those named in order
+// (see certificateExtensions), followed by all the others in their original order.
+func orderExtensions(extensions []pkix.Extension, order []string) []pkix.Extension {
+	res := make([]pkix.Extension, 0, len(extensions))
+	added := make([]bool, len(extensions))
+
+	for _, name := range order {
+		for i, ext := range extensions {
+			if !added[i] && ext.Id.Equal(certificateExtensions[name]) {
+				res = append(res, ext)
+				added[i] = true
+			}
+		}
+	}
+	for i, ext := range extensions {
+		if !added[i] {
+			res = append(res, ext)
+		}
+	}
+
+	return res
+}
+
+// nameConstraintsDNSViolations returns the DNS names among the subject alternative names of the given certificate
+// that violate its own DNS name constraints: either because they are excluded, or because they are not permitted.
+func nameConstraintsDNSViolations(cert *x509.Certificate) []string {
+	var violations []string
+	for _, dnsName := range cert.DNSNames {
+		excluded := false
+		for _, constraint := range cert.ExcludedDNSDomains {
+			if dnsNameMatchesConstraint(dnsName, constraint) {
+				violations = append(violations, fmt.Sprintf("%q is excluded by %q", dnsName, constraint))
+				excluded = true
+				break
+			}
+		}
+		if excluded || len(cert.PermittedDNSDomains) == 0 {
+			continue
+		}
+
+		permitted := false
+		for _, constraint := range cert.PermittedDNSDomains {
+			if dnsNameMatchesConstraint(dnsName, constraint) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			violations = append(violations, fmt.Sprintf("%q is not permitted by any of %q", dnsName, cert.PermittedDNSDomains))
+		}
+	}
+	return violations
+}
+
+// dnsNameMatchesConstraint tells if the given DNS name is within the given DNS name constraint,
+// following RFC 5280: a constraint matches the domain itself and all its subdomains,
+// unless it starts with a `.`, in which case it matches only the subdomains.
+func dnsNameMatchesConstraint(dnsName, constraint string) bool {
+	dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	constraint = strings.ToLower(strings.TrimSuffix(constraint, "."))
+
+	if constraint == "" {
+		return true
+	}
+	if strings.HasPrefix(constraint, ".") {
+		return strings.HasSuffix(dnsName, constraint)
+	}
+	return dnsName == constraint || strings.HasSuffix(dnsName, "."+constraint)
+}
+
+// roundDownToEndOfDay returns the last second of the latest day in UTC that ends at or before the given time.
+//
+// NOTE: the `NotAfter` of a certificate is included in its validity period (see RFC 5280, section 4.1.2.5),
+// so a day ends at 23:59:59, not at midnight.
+func roundDownToEndOfDay(t time.Time) time.Time {
+	t = t.UTC().Add(time.Second)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Second)
+}
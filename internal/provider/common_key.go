@@ -5,21 +5,29 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
-	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"golang.org/x/crypto/ssh"
 )
 
-// keyGenerator extracts data from the given *schema.ResourceData,
-// and generates a new public/private key-pair according to the
-// selected algorithm.
-type keyGenerator func(d *schema.ResourceData) (crypto.PrivateKey, error)
+// keyGenerator generates a new public/private key-pair according to the selected algorithm,
+// reading randomness from the given io.Reader and using the given RSA size in bits or ECDSA curve where relevant.
+type keyGenerator func(random io.Reader, rsaBits int, ecdsaCurve string) (crypto.PrivateKey, error)
 
 // keyParser parses a private key from the given []byte,
 // according to the selected algorithm.
@@ -27,27 +35,26 @@ type keyParser func([]byte) (crypto.PrivateKey, error)
 
 // keyGenerators provides a keyGenerator given a specific Algorithm.
 var keyGenerators = map[Algorithm]keyGenerator{
-	RSA: func(d *schema.ResourceData) (crypto.PrivateKey, error) {
-		rsaBits := d.Get("rsa_bits").(int)
-		return rsa.GenerateKey(rand.Reader, rsaBits)
+	RSA: func(random io.Reader, rsaBits int, _ string) (crypto.PrivateKey, error) {
+		return rsa.GenerateKey(random, rsaBits)
 	},
-	ECDSA: func(d *schema.ResourceData) (crypto.PrivateKey, error) {
-		curve := ECDSACurve(d.Get("ecdsa_curve").(string))
+	ECDSA: func(random io.Reader, _ int, ecdsaCurve string) (crypto.PrivateKey, error) {
+		curve := NormalizeECDSACurve(ecdsaCurve)
 		switch curve {
 		case P224:
-			return ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+			return ecdsa.GenerateKey(elliptic.P224(), random)
 		case P256:
-			return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			return ecdsa.GenerateKey(elliptic.P256(), random)
 		case P384:
-			return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			return ecdsa.GenerateKey(elliptic.P384(), random)
 		case P521:
-			return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+			return ecdsa.GenerateKey(elliptic.P521(), random)
 		default:
 			return nil, fmt.Errorf("invalid ECDSA curve; supported values are: %v", SupportedECDSACurves())
 		}
 	},
-	ED25519: func(d *schema.ResourceData) (crypto.PrivateKey, error) {
-		_, key, err := ed25519.GenerateKey(rand.Reader)
+	ED25519: func(random io.Reader, _ int, _ string) (crypto.PrivateKey, error) {
+		_, key, err := ed25519.GenerateKey(random)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate ED25519 key: %s", err)
 		}
@@ -55,6 +62,58 @@ var keyGenerators = map[Algorithm]keyGenerator{
 	},
 }
 
+// keyProfile holds the algorithm and parameters of the keys generated for a KeyProfile:
+// only the parameter relevant to the algorithm is set.
+type keyProfile struct {
+	algorithm  Algorithm
+	rsaBits    int
+	ecdsaCurve ECDSACurve
+}
+
+// keyProfiles provides the keyProfile given a specific KeyProfile.
+var keyProfiles = map[KeyProfile]keyProfile{
+	KeyProfileModern:       {algorithm: ED25519},
+	KeyProfileFIPS:         {algorithm: ECDSA, ecdsaCurve: P384},
+	KeyProfileLegacyCompat: {algorithm: RSA, rsaBits: 2048},
+}
+
+// minFIPSRSABits is the minimum size of the RSA keys approved by FIPS 186-4 for signatures.
+const minFIPSRSABits = 2048
+
+// fipsECDSACurves are the elliptic curves approved by FIPS 186-4 among the supported ones,
+// as it only approves the NIST curves, and SP 800-131A disallows P224 as too weak.
+var fipsECDSACurves = []ECDSACurve{P256, P384, P521}
+
+// checkFIPSKeyParameters checks that keys generated with the given parameters are approved by FIPS 186-4,
+// as required by the provider `fips_mode`, explaining which alternatives are approved if not.
+func checkFIPSKeyParameters(algorithm Algorithm, rsaBits int, ecdsaCurve ECDSACurve) error {
+	fipsECDSACurvesStr := make([]string, len(fipsECDSACurves))
+	for i, curve := range fipsECDSACurves {
+		fipsECDSACurvesStr[i] = curve.String()
+	}
+
+	switch algorithm {
+	case RSA:
+		if rsaBits < minFIPSRSABits {
+			return fmt.Errorf("RSA keys of %d bits are not approved in fips_mode: "+
+				"set rsa_bits to at least %d", rsaBits, minFIPSRSABits)
+		}
+	case ECDSA:
+		for _, curve := range fipsECDSACurves {
+			if ecdsaCurve == curve {
+				return nil
+			}
+		}
+		return fmt.Errorf("ECDSA keys with curve %s are not approved in fips_mode: "+
+			"set ecdsa_curve to one of %s", ecdsaCurve, strings.Join(fipsECDSACurvesStr, ", "))
+	default:
+		return fmt.Errorf("%s keys are not approved in fips_mode: "+
+			"use either RSA keys of at least %d bits, or ECDSA keys with curve %s",
+			algorithm, minFIPSRSABits, strings.Join(fipsECDSACurvesStr, ", "))
+	}
+	return nil
+}
+
 // keyParsers provides a keyParser given a specific PEMPreamble.
 var keyParsers = map[PEMPreamble]keyParser{
 	PreamblePrivateKeyRSA: func(der []byte) (crypto.PrivateKey, error) {
@@ -71,11 +130,25 @@ var keyParsers = map[PEMPreamble]keyParser{
 // parsePrivateKeyPEM takes a slide of bytes containing a private key
 // encoded in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format,
 // and returns a crypto.PrivateKey implementation, together with the Algorithm used by the key.
-func parsePrivateKeyPEM(keyPEMBytes []byte) (crypto.PrivateKey, Algorithm, error) {
+//
+// If strict is true, anything but whitespaces after the PEM block is rejected,
+// instead of being silently ignored.
+func parsePrivateKeyPEM(keyPEMBytes []byte, strict bool) (crypto.PrivateKey, Algorithm, error) {
 	pemBlock, rest := pem.Decode(keyPEMBytes)
 	if pemBlock == nil {
 		return nil, "", fmt.Errorf("failed to decode PEM block: decoded bytes %d, undecoded %d", len(keyPEMBytes)-len(rest), len(rest))
 	}
+	if strict {
+		if err := checkTrailingData(rest); err != nil {
+			return nil, "", fmt.Errorf("%w (rejected as the provider is configured with `strict_pem`)", err)
+		}
+	}
+
+	// Encrypted private keys are rejected, explaining how they are encrypted
+	if mechanism, encrypted := privateKeyPEMBlockEncryption(pemBlock); encrypted {
+		return nil, "", fmt.Errorf("private key is encrypted with %s: a passphrase is required to decrypt it, "+
+			"but encrypted private keys are not supported: decrypt it first (e.g. with `openssl pkey`)", mechanism)
+	}
 
 	// Identify the PEM preamble from the block
 	preamble, err := PEMBlockToPEMPreamble(pemBlock)
@@ -92,6 +165,18 @@ func parsePrivateKeyPEM(keyPEMBytes []byte) (crypto.PrivateKey, Algorithm, error
 	// Parse the specific crypto.PrivateKey from the PEM Block bytes
 	prvKey, err := parser(pemBlock.Bytes)
 	if err != nil {
+		// A key encoded in a different format than the one the PEM preamble labels is rejected, explaining why
+		for _, format := range SupportedPrivateKeyFormats() {
+			formatPreamble := privateKeyFormatPreambles[format]
+			if formatPreamble == preamble {
+				continue
+			}
+			if _, fErr := keyParsers[formatPreamble](pemBlock.Bytes); fErr == nil {
+				return nil, "", fmt.Errorf("PEM preamble '%s' doesn't match the encoding of the private key, "+
+					"that is in %s format: expected PEM preamble '%s'", preamble, format, formatPreamble)
+			}
+		}
+
 		return nil, "", fmt.Errorf("failed to parse private key given PEM preamble '%s': %w", preamble, err)
 	}
 
@@ -104,11 +189,84 @@ func parsePrivateKeyPEM(keyPEMBytes []byte) (crypto.PrivateKey, Algorithm, error
 	return prvKey, algorithm, nil
 }
 
+// encryptedPrivateKeyAlgorithms maps the OIDs of the algorithms used to encrypt
+// PKCS #8 private keys to their names, to describe how a private key is encrypted.
+var encryptedPrivateKeyAlgorithms = map[string]string{
+	"1.2.840.113549.1.5.3":    "PBES1 (MD5, DES-CBC)",
+	"1.2.840.113549.1.5.10":   "PBES1 (SHA1, DES-CBC)",
+	"1.2.840.113549.1.5.13":   "PBES2",
+	"1.2.840.113549.1.5.12":   "PBKDF2",
+	"1.3.6.1.4.1.11591.4.11":  "scrypt",
+	"1.2.840.113549.1.12.1.3": "PKCS #12 PBE (SHA1, 3DES-CBC)",
+	"1.2.840.113549.3.7":      "DES-EDE3-CBC",
+	"2.16.840.1.101.3.4.1.2":  "AES-128-CBC",
+	"2.16.840.1.101.3.4.1.22": "AES-192-CBC",
+	"2.16.840.1.101.3.4.1.42": "AES-256-CBC",
+}
+
+// privateKeyPEMBlockEncryption returns a description of the mechanism used to encrypt the given PEM block,
+// and true if it's either a legacy encrypted PEM block (RFC 1423) or a PKCS #8 encrypted private key.
+func privateKeyPEMBlockEncryption(block *pem.Block) (string, bool) {
+	// NOTE: x509.IsEncryptedPEMBlock is deprecated, so the legacy `Proc-Type` header is checked directly
+	if block.Headers["Proc-Type"] == "4,ENCRYPTED" {
+		cipher := strings.SplitN(block.Headers["DEK-Info"], ",", 2)[0]
+		if cipher == "" {
+			cipher = "unknown cipher"
+		}
+		return fmt.Sprintf("legacy PEM encryption ('Proc-Type: 4,ENCRYPTED', %s)", cipher), true
+	}
+
+	if block.Type != PreamblePrivateKeyPKCS8Encrypted.String() {
+		return "", false
+	}
+
+	var encryptedPrivateKeyInfo struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}
+	if _, err := asn1.Unmarshal(block.Bytes, &encryptedPrivateKeyInfo); err != nil {
+		return "PKCS #8 encryption (unknown algorithm)", true
+	}
+
+	mechanism := encryptedPrivateKeyAlgorithmName(encryptedPrivateKeyInfo.Algorithm.Algorithm)
+
+	// PBES2 is parameterized by a key derivation function and an encryption scheme (RFC 8018, appendix A.4)
+	var pbes2Params struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}
+	if mechanism == "PBES2" {
+		if _, err := asn1.Unmarshal(encryptedPrivateKeyInfo.Algorithm.Parameters.FullBytes, &pbes2Params); err == nil {
+			mechanism = fmt.Sprintf("PBES2 (%s, %s)",
+				encryptedPrivateKeyAlgorithmName(pbes2Params.KeyDerivationFunc.Algorithm),
+				encryptedPrivateKeyAlgorithmName(pbes2Params.EncryptionScheme.Algorithm))
+		}
+	}
+
+	return fmt.Sprintf("PKCS #8 encryption (%s)", mechanism), true
+}
+
+// encryptedPrivateKeyAlgorithmName returns the name of the given algorithm
+// in encryptedPrivateKeyAlgorithms, or its OID in dotted decimal notation if unknown.
+func encryptedPrivateKeyAlgorithmName(oid asn1.ObjectIdentifier) string {
+	if name, ok := encryptedPrivateKeyAlgorithms[oid.String()]; ok {
+		return name
+	}
+	return oid.String()
+}
+
 // parsePrivateKeyOpenSSHPEM takes a slide of bytes containing a private key
 // encoded in [OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format,
 // and returns a crypto.PrivateKey implementation, together with the Algorithm used by the key.
-func parsePrivateKeyOpenSSHPEM(keyOpenSSHPEMBytes []byte) (crypto.PrivateKey, Algorithm, error) {
-	prvKey, err := ssh.ParseRawPrivateKey(keyOpenSSHPEMBytes)
+// If the private key is encrypted, the passphrase to decrypt it must be given.
+func parsePrivateKeyOpenSSHPEM(keyOpenSSHPEMBytes, passphrase []byte) (crypto.PrivateKey, Algorithm, error) {
+	var prvKey interface{}
+	var err error
+	if len(passphrase) > 0 {
+		prvKey, err = ssh.ParseRawPrivateKeyWithPassphrase(keyOpenSSHPEMBytes, passphrase)
+	} else {
+		prvKey, err = ssh.ParseRawPrivateKey(keyOpenSSHPEMBytes)
+	}
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to parse openssh private key: %w", err)
 	}
@@ -132,6 +290,91 @@ func privateKeyToPublicKey(prvKey crypto.PrivateKey) (crypto.PublicKey, error) {
 	return signer.Public(), nil
 }
 
+// publicKeysEqual returns true if the two given crypto.PublicKey are of the same type and value.
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	// NOTE: all the crypto.PublicKey implementations in the standard library implement this interface
+	aEq, ok := a.(interface {
+		Equal(crypto.PublicKey) bool
+	})
+	if !ok {
+		return false
+	}
+
+	return aEq.Equal(b)
+}
+
+// publicKeyParameters returns the size in bits of the given crypto.PublicKey, if it's an RSA key,
+// or the ECDSACurve it uses, if it's an ECDSA key. For any other type of key, zero values are returned.
+func publicKeyParameters(pubKey crypto.PublicKey) (int, ECDSACurve) {
+	switch k := pubKey.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen(), ""
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P224():
+			return 0, P224
+		case elliptic.P256():
+			return 0, P256
+		case elliptic.P384():
+			return 0, P384
+		case elliptic.P521():
+			return 0, P521
+		}
+	}
+
+	return 0, ""
+}
+
+// parsePrivateKeyPEMAttribute parses the private key held, in PEM format, by the attribute with the given key,
+// or by the file at the path held by the attribute with the same key and the `_file` suffix.
+// See parsePrivateKeyPEM for the meaning of strict.
+func parsePrivateKeyPEMAttribute(d *schema.ResourceData, key string, strict bool) (crypto.PrivateKey, Algorithm, error) {
+	fileKey := key + "_file"
+
+	path, ok := d.GetOk(fileKey)
+	if !ok {
+		return parsePrivateKeyPEM([]byte(d.Get(key).(string)), strict)
+	}
+
+	keyPEM, err := os.ReadFile(path.(string))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", fileKey, err)
+	}
+
+	prvKey, algorithm, err := parsePrivateKeyPEM(keyPEM, strict)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid private key in %s: %w", fileKey, err)
+	}
+
+	return prvKey, algorithm, nil
+}
+
+// setKeyParametersAttributes takes a crypto.PrivateKey and encodes on the given schema.ResourceData
+// the RSA size in bits and the ECDSA curve of the key, on attributes named with the given prefix.
+func setKeyParametersAttributes(d *schema.ResourceData, prefix string, prvKey crypto.PrivateKey) diag.Diagnostics {
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return diag.Errorf("failed to get public key from private key: %v", err)
+	}
+
+	return setPublicKeyParametersAttributes(d, prefix, pubKey)
+}
+
+// setPublicKeyParametersAttributes is like setKeyParametersAttributes, but takes a crypto.PublicKey.
+func setPublicKeyParametersAttributes(d *schema.ResourceData, prefix string, pubKey crypto.PublicKey) diag.Diagnostics {
+	rsaBits, ecdsaCurve := publicKeyParameters(pubKey)
+
+	if err := d.Set(prefix+"rsa_bits", rsaBits); err != nil {
+		return diag.Errorf("error setting value on key '%srsa_bits': %s", prefix, err)
+	}
+
+	if err := d.Set(prefix+"ecdsa_curve", string(ecdsaCurve)); err != nil {
+		return diag.Errorf("error setting value on key '%secdsa_curve': %s", prefix, err)
+	}
+
+	return nil
+}
+
 // privateKeyToAlgorithm identifies the Algorithm used by a given crypto.PrivateKey.
 func privateKeyToAlgorithm(prvKey crypto.PrivateKey) (Algorithm, error) {
 	switch prvKey.(type) {
@@ -146,6 +389,208 @@ func privateKeyToAlgorithm(prvKey crypto.PrivateKey) (Algorithm, error) {
 	}
 }
 
+// publicKeyToAlgorithm identifies the Algorithm used by a given crypto.PublicKey.
+func publicKeyToAlgorithm(pubKey crypto.PublicKey) (Algorithm, error) {
+	switch pubKey.(type) {
+	case *rsa.PublicKey:
+		return RSA, nil
+	case *ecdsa.PublicKey:
+		return ECDSA, nil
+	case ed25519.PublicKey:
+		return ED25519, nil
+	default:
+		return "", fmt.Errorf("unsupported public key type: %T", pubKey)
+	}
+}
+
+// privateKeyToPEMBlock takes a crypto.PrivateKey and marshals it into a pem.Block, using the default
+// PrivateKeyFormat of its Algorithm: PKCS#1 for `RSA` keys, SEC 1 for `ECDSA` keys and PKCS#8 for `ED25519` keys.
+func privateKeyToPEMBlock(prvKey crypto.PrivateKey) (*pem.Block, error) {
+	algorithm, err := privateKeyToAlgorithm(prvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return privateKeyToFormattedPEMBlock(prvKey, algorithmPrivateKeyFormats[algorithm][0])
+}
+
+// privateKeyToFormattedPEMBlock takes a crypto.PrivateKey and marshals it into a pem.Block,
+// using the given PrivateKeyFormat, that must be supported by the Algorithm of the key.
+func privateKeyToFormattedPEMBlock(prvKey crypto.PrivateKey, format PrivateKeyFormat) (*pem.Block, error) {
+	algorithm, err := privateKeyToAlgorithm(prvKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKeyFormat(algorithm, format); err != nil {
+		return nil, err
+	}
+
+	if kp, ok := prvKey.(*ed25519.PrivateKey); ok {
+		prvKey = *kp
+	}
+
+	switch format {
+	case PrivateKeyFormatPKCS1:
+		return &pem.Block{
+			Type:  privateKeyFormatPreambles[format].String(),
+			Bytes: x509.MarshalPKCS1PrivateKey(prvKey.(*rsa.PrivateKey)),
+		}, nil
+	case PrivateKeyFormatSEC1:
+		keyBytes, err := x509.MarshalECPrivateKey(prvKey.(*ecdsa.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+
+		return &pem.Block{
+			Type:  privateKeyFormatPreambles[format].String(),
+			Bytes: keyBytes,
+		}, nil
+	default:
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(prvKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pem.Block{
+			Type:  privateKeyFormatPreambles[format].String(),
+			Bytes: keyBytes,
+		}, nil
+	}
+}
+
+// checkPrivateKeyFormat returns an error if private keys of the given Algorithm
+// cannot be encoded using the given PrivateKeyFormat.
+func checkPrivateKeyFormat(algorithm Algorithm, format PrivateKeyFormat) error {
+	for _, supported := range algorithmPrivateKeyFormats[algorithm] {
+		if supported == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s keys cannot be encoded in %s format", algorithm, format)
+}
+
+// publicKeyToPEM takes a crypto.PublicKey and marshals it in PKIX form, returning both
+// the raw bytes and the [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) encoding.
+func publicKeyToPEM(pubKey crypto.PublicKey) ([]byte, string, error) {
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	if err != nil {
+		return nil, "", err
+	}
+	pubKeyPemBlock := &pem.Block{
+		Type:  PreamblePublicKey.String(),
+		Bytes: pubKeyBytes,
+	}
+
+	return pubKeyBytes, string(pem.EncodeToMemory(pubKeyPemBlock)), nil
+}
+
+// publicKeySPKISHA256 returns the hexadecimal representation of the SHA256 checksum
+// of the given crypto.PublicKey, in PKIX `SubjectPublicKeyInfo` DER format.
+func publicKeySPKISHA256(pubKey crypto.PublicKey) (string, error) {
+	pubKeyDER, _, err := publicKeyToPEM(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return contentHash(pubKeyDER), nil
+}
+
+// publicKeyToOpenSSH takes a crypto.PublicKey and marshals it in OpenSSH 'Authorized Keys' format,
+// returning it together with its MD5 and SHA256 fingerprints.
+//
+// NOTE: ECDSA keys with elliptic curve P-224 are not supported by `x/crypto/ssh`,
+// so in that case empty strings are returned.
+func publicKeyToOpenSSH(pubKey crypto.PublicKey) (pubKeySSH, fingerprintMD5, fingerprintSHA256 string) {
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	if err != nil {
+		return "", "", ""
+	}
+
+	return string(ssh.MarshalAuthorizedKey(sshPubKey)), ssh.FingerprintLegacyMD5(sshPubKey), ssh.FingerprintSHA256(sshPubKey)
+}
+
+// opensshCommentSchema returns the schema.Schema for the `openssh_comment` attribute.
+func opensshCommentSchema(forceNew bool, extraDescription string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         forceNew,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringDoesNotContainAny("\r\n")),
+		Description: strings.TrimSpace("Comment to append to `public_key_openssh`, like the `user@host` that `ssh-keygen` uses by default. " +
+			"It must be on a single line. " + extraDescription),
+	}
+}
+
+// withOpenSSHComment appends the given comment to the given public key in OpenSSH 'Authorized Keys' format,
+// before its trailing `\n`. The public key is returned unchanged if either is empty.
+func withOpenSSHComment(pubKeySSH, comment string) string {
+	if pubKeySSH == "" || comment == "" {
+		return pubKeySSH
+	}
+
+	return strings.TrimSuffix(pubKeySSH, "\n") + " " + comment + "\n"
+}
+
+// publicKeyToOpenSSHFingerprintSHA256Hex returns the SHA256 fingerprint of the given crypto.PublicKey,
+// computed over its OpenSSH wire format, as colon-separated hexadecimal (e.g. `aa:bb:cc:...`):
+// this is the same hash as `ssh.FingerprintSHA256`, encoded like `ssh.FingerprintLegacyMD5`.
+//
+// NOTE: as per publicKeyToOpenSSH, an empty string is returned for keys not supported by `x/crypto/ssh`.
+func publicKeyToOpenSSHFingerprintSHA256Hex(pubKey crypto.PublicKey) string {
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	if err != nil {
+		return ""
+	}
+
+	sha256sum := sha256.Sum256(sshPubKey.Marshal())
+	hexPairs := make([]string, len(sha256sum))
+	for i, b := range sha256sum {
+		hexPairs[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hexPairs, ":")
+}
+
+// publicKeySupportsOpenSSH returns false for `ECDSA` keys with curves that the SSH ECC Algorithm Integration
+// ([RFC 5656](https://datatracker.ietf.org/doc/html/rfc5656)) doesn't support, like P-224.
+func publicKeySupportsOpenSSH(pubKey crypto.PublicKey) bool {
+	if _, ecdsaCurve := publicKeyParameters(pubKey); ecdsaCurve != "" {
+		return ecdsaCurve == P256 || ecdsaCurve == P384 || ecdsaCurve == P521
+	}
+	return true
+}
+
+// privateKeyToRaw returns the raw bytes of a crypto.PrivateKey: the 32 bytes seed for `ED25519` keys,
+// and the big-endian private scalar, padded to the size of the curve, for `ECDSA` keys.
+//
+// NOTE: `RSA` keys have no such raw form, so in that case nil is returned.
+func privateKeyToRaw(prvKey crypto.PrivateKey) []byte {
+	switch k := prvKey.(type) {
+	case *ecdsa.PrivateKey:
+		return k.D.FillBytes(make([]byte, (k.Curve.Params().BitSize+7)/8))
+	case ed25519.PrivateKey:
+		return k.Seed()
+	case *ed25519.PrivateKey:
+		return k.Seed()
+	default:
+		return nil
+	}
+}
+
+// publicKeyToRaw returns the raw bytes of a crypto.PublicKey: the 32 bytes public key for `ED25519` keys,
+// and the uncompressed point (see https://www.secg.org/sec1-v2.pdf, section 2.3.3) for `ECDSA` keys.
+//
+// NOTE: `RSA` keys have no such raw form, so in that case nil is returned.
+func publicKeyToRaw(pubKey crypto.PublicKey) []byte {
+	switch k := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		return elliptic.Marshal(k.Curve, k.X, k.Y)
+	case ed25519.PublicKey:
+		return []byte(k)
+	default:
+		return nil
+	}
+}
+
 // setPublicKeyAttributes takes a crypto.PrivateKey, extracts the corresponding crypto.PublicKey and then
 // encodes related attributes on the given schema.ResourceData.
 func setPublicKeyAttributes(d *schema.ResourceData, prvKey crypto.PrivateKey) diag.Diagnostics {
@@ -153,34 +598,24 @@ func setPublicKeyAttributes(d *schema.ResourceData, prvKey crypto.PrivateKey) di
 	if err != nil {
 		return diag.Errorf("failed to get public key from private key: %v", err)
 	}
-	pubKeyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	pubKeyBytes, pubKeyPEM, err := publicKeyToPEM(pubKey)
 	if err != nil {
 		return diag.Errorf("failed to marshal public key: %v", err)
 	}
-	pubKeyPemBlock := &pem.Block{
-		Type:  PreamblePublicKey.String(),
-		Bytes: pubKeyBytes,
-	}
 
 	d.SetId(hashForState(string(pubKeyBytes)))
 
-	if err := d.Set("public_key_pem", string(pem.EncodeToMemory(pubKeyPemBlock))); err != nil {
+	if err := d.Set("public_key_pem", pubKeyPEM); err != nil {
 		return diag.Errorf("error setting value on key 'public_key_pem': %s", err)
 	}
 
-	// NOTE: ECDSA keys with elliptic curve P-224 are not supported by `x/crypto/ssh`,
-	// so this will return an error: in that case, we set the below fields to emptry strings
-	sshPubKey, err := ssh.NewPublicKey(pubKey)
-	var pubKeySSH, pubKeySSHFingerprintMD5, pubKeySSHFingerprintSHA256 string
-	if err == nil {
-		sshPubKeyBytes := ssh.MarshalAuthorizedKey(sshPubKey)
-
-		pubKeySSH = string(sshPubKeyBytes)
-		pubKeySSHFingerprintMD5 = ssh.FingerprintLegacyMD5(sshPubKey)
-		pubKeySSHFingerprintSHA256 = ssh.FingerprintSHA256(sshPubKey)
+	if err := d.Set("public_key_der_base64", base64.StdEncoding.EncodeToString(pubKeyBytes)); err != nil {
+		return diag.Errorf("error setting value on key 'public_key_der_base64': %s", err)
 	}
 
-	if err := d.Set("public_key_openssh", pubKeySSH); err != nil {
+	pubKeySSH, pubKeySSHFingerprintMD5, pubKeySSHFingerprintSHA256 := publicKeyToOpenSSH(pubKey)
+
+	if err := d.Set("public_key_openssh", withOpenSSHComment(pubKeySSH, d.Get("openssh_comment").(string))); err != nil {
 		return diag.Errorf("error setting value on key 'public_key_openssh': %s", err)
 	}
 
@@ -192,5 +627,84 @@ func setPublicKeyAttributes(d *schema.ResourceData, prvKey crypto.PrivateKey) di
 		return diag.Errorf("error setting value on key 'public_key_fingerprint_sha256': %s", err)
 	}
 
+	if err := d.Set("public_key_fingerprint_sha256_hex", publicKeyToOpenSSHFingerprintSHA256Hex(pubKey)); err != nil {
+		return diag.Errorf("error setting value on key 'public_key_fingerprint_sha256_hex': %s", err)
+	}
+
 	return nil
 }
+
+// jsonWebKey is a public key in JSON Web Key format (see https://datatracker.ietf.org/doc/html/rfc7517).
+// Its members are declared in lexicographic order, as required to compute the thumbprint.
+type jsonWebKey struct {
+	Crv string `json:"crv,omitempty"`
+	E   string `json:"e,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty"`
+	N   string `json:"n,omitempty"`
+	Use string `json:"use,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// publicKeyToJWK converts a crypto.PublicKey to a jsonWebKey, with no `kid` nor `use` members.
+//
+// NOTE: `ECDSA` keys are only supported with the curves registered for JSON Web Keys
+// (see https://datatracker.ietf.org/doc/html/rfc7518#section-6.2.1.1): P-256, P-384 and P-521.
+func publicKeyToJWK(pubKey crypto.PublicKey) (*jsonWebKey, error) {
+	switch k := pubKey.(type) {
+	case *rsa.PublicKey:
+		return &jsonWebKey{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		var crv string
+		switch k.Curve {
+		case elliptic.P256():
+			crv = "P-256"
+		case elliptic.P384():
+			crv = "P-384"
+		case elliptic.P521():
+			crv = "P-521"
+		default:
+			return nil, fmt.Errorf("ECDSA keys with curve %s cannot be converted to JWK", k.Curve.Params().Name)
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &jsonWebKey{
+			Kty: "EC",
+			Crv: crv,
+			X:   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return &jsonWebKey{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %T", pubKey)
+	}
+}
+
+// thumbprint returns the JWK Thumbprint of the key (see https://datatracker.ietf.org/doc/html/rfc7638),
+// encoded in base64url: the SHA256 hash of its required members, serialized in lexicographic order.
+func (k *jsonWebKey) thumbprint() (string, error) {
+	required, err := json.Marshal(&jsonWebKey{
+		Crv: k.Crv,
+		E:   k.E,
+		Kty: k.Kty,
+		N:   k.N,
+		X:   k.X,
+		Y:   k.Y,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(required)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
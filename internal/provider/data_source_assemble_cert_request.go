@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	oidSignatureSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSignatureSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSignatureSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidSignatureRSAPSS          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+	oidSignatureECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidSignatureECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidSignatureECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidSignatureEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidMGF1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 8}
+)
+
+func dataSourceAssembleCertRequest() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceAssembleCertRequest,
+
+		Description: "Assemble a Certificate Signing Request (CSR) from its `CertificationRequestInfo` " +
+			"(the part of the certificate request that is signed), as produced by `tls_cert_request` " +
+			"when given a `subject_public_key_pem`, and a signature produced externally, for example by " +
+			"a Hardware Security Module (HSM) or a cloud Key Management Service (KMS) holding the private key.\n\n" +
+			"The signature is verified against the public key carried in the certificate request.",
+
+		Schema: map[string]*schema.Schema{
+			"cert_request_tbs_der_base64": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description: "The `CertificationRequestInfo` structure " +
+					"(see [RFC 2986](https://datatracker.ietf.org/doc/html/rfc2986#section-4.1)) in DER format, " +
+					"encoded in base64: this is the data that was signed.",
+			},
+
+			"signature_base64": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description: "The signature of `cert_request_tbs_der_base64`, encoded in base64. " +
+					"For `ECDSA`, this is the DER encoding of the `r` and `s` values, as most signers produce it.",
+			},
+
+			"signature_algorithm": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedSignatureAlgorithms(), false)),
+				Description: "Algorithm used to produce `signature_base64`. " +
+					"Accepted values are `SHA256-RSA`, `SHA384-RSA`, `SHA512-RSA`, " +
+					"`SHA256-RSAPSS`, `SHA384-RSAPSS`, `SHA512-RSAPSS` (RSA-PSS), " +
+					"`ECDSA-SHA256`, `ECDSA-SHA384`, `ECDSA-SHA512` and `Ed25519`.",
+			},
+
+			"cert_request_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The assembled certificate request, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) library that generates this " +
+					"value appends a `\\n` at the end of the PEM. " +
+					"In case this disrupts your use case, we recommend using " +
+					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA256 checksum of the assembled certificate request in DER format.",
+			},
+		},
+	}
+}
+
+func readDataSourceAssembleCertRequest(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tbsBytes, err := base64.StdEncoding.DecodeString(d.Get("cert_request_tbs_der_base64").(string))
+	if err != nil {
+		return diag.Errorf("unable to decode cert_request_tbs_der_base64: %v", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(d.Get("signature_base64").(string))
+	if err != nil {
+		return diag.Errorf("unable to decode signature_base64: %v", err)
+	}
+
+	sigAlg := d.Get("signature_algorithm").(string)
+	certReqDER, err := assembleCertRequest(tbsBytes, signatureAlgorithms[sigAlg], signature)
+	if err != nil {
+		return diag.Errorf("invalid cert_request_tbs_der_base64: %v", err)
+	}
+
+	certReq, err := x509.ParseCertificateRequest(certReqDER)
+	if err != nil {
+		return diag.Errorf("failed to parse assembled certificate request: %v", err)
+	}
+
+	if err := certReq.CheckSignature(); err != nil {
+		return diag.Errorf("signature_base64 is not a valid %s signature by the public key of the certificate request: %v", sigAlg, err)
+	}
+
+	certReqPEM := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificateRequest.String(), Bytes: certReqDER}))
+	if err := d.Set("cert_request_pem", certReqPEM); err != nil {
+		return diag.Errorf("error setting value on key 'cert_request_pem': %s", err)
+	}
+
+	d.SetId(contentHash(certReqDER))
+
+	return nil
+}
+
+// assembleCertRequest returns the DER certificate request made of the given DER CertificationRequestInfo
+// and signature, produced with the given x509.SignatureAlgorithm.
+//
+// NOTE: Unlike a TBSCertificate, a CertificationRequestInfo doesn't declare the algorithm it's signed with,
+// so the AlgorithmIdentifier is built here, the same way `crypto/x509` does.
+func assembleCertRequest(tbsBytes []byte, sigAlg x509.SignatureAlgorithm, signature []byte) ([]byte, error) {
+	var tbs asn1.RawValue
+	rest, err := asn1.Unmarshal(tbsBytes, &tbs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after CertificationRequestInfo")
+	}
+	if tbs.Class != asn1.ClassUniversal || tbs.Tag != asn1.TagSequence {
+		return nil, fmt.Errorf("CertificationRequestInfo is not a SEQUENCE")
+	}
+
+	algorithmIdentifier, err := signatureAlgorithmIdentifier(sigAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(struct {
+		CertificationRequestInfo asn1.RawValue
+		SignatureAlgorithm       pkix.AlgorithmIdentifier
+		Signature                asn1.BitString
+	}{
+		CertificationRequestInfo: asn1.RawValue{FullBytes: tbsBytes},
+		SignatureAlgorithm:       algorithmIdentifier,
+		Signature:                asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+}
+
+// signatureAlgorithmIdentifier returns the pkix.AlgorithmIdentifier of the given x509.SignatureAlgorithm.
+// The parameters are NULL for RSA (see https://datatracker.ietf.org/doc/html/rfc4055#section-5),
+// absent for ECDSA and Ed25519, and the ones of https://datatracker.ietf.org/doc/html/rfc4055#section-3.1 for RSA-PSS.
+func signatureAlgorithmIdentifier(sigAlg x509.SignatureAlgorithm) (pkix.AlgorithmIdentifier, error) {
+	switch sigAlg {
+	case x509.SHA256WithRSA:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureSHA256WithRSA, Parameters: asn1.NullRawValue}, nil
+	case x509.SHA384WithRSA:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureSHA384WithRSA, Parameters: asn1.NullRawValue}, nil
+	case x509.SHA512WithRSA:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureSHA512WithRSA, Parameters: asn1.NullRawValue}, nil
+	case x509.SHA256WithRSAPSS:
+		return pssAlgorithmIdentifier(oidSHA256, 32)
+	case x509.SHA384WithRSAPSS:
+		return pssAlgorithmIdentifier(oidSHA384, 48)
+	case x509.SHA512WithRSAPSS:
+		return pssAlgorithmIdentifier(oidSHA512, 64)
+	case x509.ECDSAWithSHA256:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA256}, nil
+	case x509.ECDSAWithSHA384:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA384}, nil
+	case x509.ECDSAWithSHA512:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA512}, nil
+	case x509.PureEd25519:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureEd25519}, nil
+	default:
+		return pkix.AlgorithmIdentifier{}, fmt.Errorf("unsupported signature algorithm: %s", sigAlg)
+	}
+}
+
+// pssAlgorithmIdentifier returns the pkix.AlgorithmIdentifier of RSA-PSS with the given hash,
+// using MGF1 with the same hash, and a salt as long as the hash (i.e. the given salt length).
+func pssAlgorithmIdentifier(hashOID asn1.ObjectIdentifier, saltLength int) (pkix.AlgorithmIdentifier, error) {
+	hashAlgorithm := pkix.AlgorithmIdentifier{Algorithm: hashOID, Parameters: asn1.NullRawValue}
+	mgfParameters, err := asn1.Marshal(hashAlgorithm)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+
+	params, err := asn1.Marshal(struct {
+		Hash       pkix.AlgorithmIdentifier `asn1:"explicit,tag:0"`
+		MGF        pkix.AlgorithmIdentifier `asn1:"explicit,tag:1"`
+		SaltLength int                      `asn1:"explicit,tag:2"`
+	}{
+		Hash:       hashAlgorithm,
+		MGF:        pkix.AlgorithmIdentifier{Algorithm: oidMGF1, Parameters: asn1.RawValue{FullBytes: mgfParameters}},
+		SaltLength: saltLength,
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+
+	return pkix.AlgorithmIdentifier{Algorithm: oidSignatureRSAPSS, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAssembleCertRequest(t *testing.T) {
+	prvKey, _, err := parsePrivateKeyPEM([]byte(testPrivateKeyPEM), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The CertificationRequestInfo and its signature are taken apart from a certificate request,
+	// as they would be produced by an external signer
+	certReqDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: "example.com"},
+		DNSNames:           []string{"example.com"},
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}, prvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certReq, err := x509.ParseCertificateRequest(certReqDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbsBase64 := base64.StdEncoding.EncodeToString(certReq.RawTBSCertificateRequest)
+	signatureBase64 := base64.StdEncoding.EncodeToString(certReq.Signature)
+	certReqPEM := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificateRequest.String(), Bytes: certReqDER}))
+
+	tampered := append([]byte{}, certReq.Signature...)
+	tampered[0] ^= 0xff
+
+	config := func(tbs, signature, signatureAlgorithm string) string {
+		return fmt.Sprintf(`
+			data "tls_assemble_cert_request" "test" {
+				cert_request_tbs_der_base64 = %q
+				signature_base64            = %q
+				signature_algorithm         = %q
+			}
+		`, tbs, signature, signatureAlgorithm)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(tbsBase64, signatureBase64, "SHA256-RSA"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_assemble_cert_request.test", "cert_request_pem", certReqPEM),
+					resource.TestCheckResourceAttr("data.tls_assemble_cert_request.test", "id", contentHash(certReqDER)),
+				),
+			},
+			{
+				Config:      config(tbsBase64, base64.StdEncoding.EncodeToString(tampered), "SHA256-RSA"),
+				ExpectError: regexp.MustCompile(`signature_base64 is not a valid SHA256-RSA signature by the public key of\s+the certificate request`),
+			},
+			{
+				Config:      config(tbsBase64, signatureBase64, "SHA384-RSA"),
+				ExpectError: regexp.MustCompile(`signature_base64 is not a valid SHA384-RSA signature by the public key of\s+the certificate request`),
+			},
+			{
+				Config:      config(base64.StdEncoding.EncodeToString([]byte("not a CertificationRequestInfo")), signatureBase64, "SHA256-RSA"),
+				ExpectError: regexp.MustCompile(`invalid cert_request_tbs_der_base64`),
+			},
+		},
+	})
+}
+
+func TestAssembleCertRequest(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[x509.SignatureAlgorithm]crypto.Signer{
+		x509.SHA256WithRSA:    rsaKey,
+		x509.SHA384WithRSA:    rsaKey,
+		x509.SHA512WithRSA:    rsaKey,
+		x509.SHA256WithRSAPSS: rsaKey,
+		x509.SHA384WithRSAPSS: rsaKey,
+		x509.SHA512WithRSAPSS: rsaKey,
+		x509.ECDSAWithSHA256:  ecdsaKey,
+		x509.ECDSAWithSHA384:  ecdsaKey,
+		x509.ECDSAWithSHA512:  ecdsaKey,
+		x509.PureEd25519:      ed25519Key,
+	}
+
+	for _, sigAlg := range signatureAlgorithms {
+		t.Run(sigAlg.String(), func(t *testing.T) {
+			certReqDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+				Subject:            pkix.Name{CommonName: "example.com"},
+				SignatureAlgorithm: sigAlg,
+			}, keys[sigAlg])
+			if err != nil {
+				t.Fatal(err)
+			}
+			certReq, err := x509.ParseCertificateRequest(certReqDER)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The assembled certificate request must be identical to the one created by `crypto/x509`
+			assembled, err := assembleCertRequest(certReq.RawTBSCertificateRequest, sigAlg, certReq.Signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(assembled) != string(certReqDER) {
+				t.Errorf("assembled certificate request differs from the one created by crypto/x509")
+			}
+		})
+	}
+}
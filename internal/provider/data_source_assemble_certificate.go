@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAssembleCertificate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceAssembleCertificate,
+
+		Description: "Assemble a certificate from its `TBSCertificate` (the part of the certificate that is signed) " +
+			"and a signature produced externally, for example by a Hardware Security Module (HSM) " +
+			"or a cloud Key Management Service (KMS) that can sign data, but not certificates.\n\n" +
+			"The assembled certificate is parsed, to confirm it is well formed, " +
+			"and its signature is verified if the certificate of the issuer is provided.",
+
+		Schema: map[string]*schema.Schema{
+			"tbs_certificate_base64": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description: "The `TBSCertificate` structure " +
+					"(see [RFC 5280](https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.2)) in DER format, " +
+					"encoded in base64: this is the data that was signed.",
+			},
+
+			"signature_base64": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description: "The signature of `tbs_certificate_base64`, encoded in base64. " +
+					"For `ECDSA`, this is the DER encoding of the `r` and `s` values, as most signers produce it.",
+			},
+
+			"signature_algorithm": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedSignatureAlgorithms(), false)),
+				Description: "Algorithm used to produce `signature_base64`: it must match the signature algorithm " +
+					"declared in `tbs_certificate_base64`. Accepted values are `SHA256-RSA`, `SHA384-RSA`, `SHA512-RSA`, " +
+					"`SHA256-RSAPSS`, `SHA384-RSAPSS`, `SHA512-RSAPSS` (RSA-PSS), " +
+					"`ECDSA-SHA256`, `ECDSA-SHA384`, `ECDSA-SHA512` and `Ed25519`.",
+			},
+
+			"ca_cert_pem": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Certificate of the issuer, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"If set, the signature of the assembled certificate is verified against its public key.",
+			},
+
+			"cert_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The assembled certificate, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) library that generates this " +
+					"value appends a `\\n` at the end of the PEM. " +
+					"In case this disrupts your use case, we recommend using " +
+					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA256 checksum of the assembled certificate in DER format.",
+			},
+		},
+	}
+}
+
+func readDataSourceAssembleCertificate(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tbsBytes, err := base64.StdEncoding.DecodeString(d.Get("tbs_certificate_base64").(string))
+	if err != nil {
+		return diag.Errorf("unable to decode tbs_certificate_base64: %v", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(d.Get("signature_base64").(string))
+	if err != nil {
+		return diag.Errorf("unable to decode signature_base64: %v", err)
+	}
+
+	certDER, err := assembleCertificate(tbsBytes, signature)
+	if err != nil {
+		return diag.Errorf("invalid tbs_certificate_base64: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return diag.Errorf("failed to parse assembled certificate: %v", err)
+	}
+
+	sigAlg := d.Get("signature_algorithm").(string)
+	if cert.SignatureAlgorithm != signatureAlgorithms[sigAlg] {
+		return diag.Errorf("signature_algorithm %s doesn't match the signature algorithm declared in tbs_certificate_base64: %s",
+			sigAlg, cert.SignatureAlgorithm)
+	}
+
+	if caCertPEM, ok := d.GetOk("ca_cert_pem"); ok {
+		caCerts, err := parseCertificatesPEM([]byte(caCertPEM.(string)))
+		if err != nil {
+			return diag.Errorf("invalid ca_cert_pem: %v", err)
+		}
+		if err := cert.CheckSignatureFrom(caCerts[0]); err != nil {
+			return diag.Errorf("signature_base64 is not a valid signature by the key of ca_cert_pem: %v", err)
+		}
+	}
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: certDER}))
+	if err := d.Set("cert_pem", certPEM); err != nil {
+		return diag.Errorf("error setting value on key 'cert_pem': %s", err)
+	}
+
+	d.SetId(contentHash(certDER))
+
+	return nil
+}
+
+// assembleCertificate returns the DER certificate made of the given DER TBSCertificate and signature,
+// using as the outer signature algorithm the one declared in the TBSCertificate, as they must be identical
+// (see https://datatracker.ietf.org/doc/html/rfc5280#section-4.1.1.2).
+func assembleCertificate(tbsBytes, signature []byte) ([]byte, error) {
+	var tbs asn1.RawValue
+	rest, err := asn1.Unmarshal(tbsBytes, &tbs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after TBSCertificate")
+	}
+	if tbs.Class != asn1.ClassUniversal || tbs.Tag != asn1.TagSequence {
+		return nil, fmt.Errorf("TBSCertificate is not a SEQUENCE")
+	}
+
+	// The `signature` field is the first SEQUENCE of the TBSCertificate,
+	// after the optional `version` (tagged [0]) and the `serialNumber` (an INTEGER)
+	var signatureAlgorithm asn1.RawValue
+	for rest = tbs.Bytes; signatureAlgorithm.FullBytes == nil; {
+		var field asn1.RawValue
+		if rest, err = asn1.Unmarshal(rest, &field); err != nil {
+			return nil, fmt.Errorf("failed to find the signature algorithm of TBSCertificate: %w", err)
+		}
+		if field.Class == asn1.ClassUniversal && field.Tag == asn1.TagSequence {
+			signatureAlgorithm = field
+		}
+	}
+
+	return asn1.Marshal(struct {
+		TBSCertificate     asn1.RawValue
+		SignatureAlgorithm asn1.RawValue
+		SignatureValue     asn1.BitString
+	}{
+		TBSCertificate:     asn1.RawValue{FullBytes: tbsBytes},
+		SignatureAlgorithm: signatureAlgorithm,
+		SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAssembleCertificate(t *testing.T) {
+	caKey, _, err := parsePrivateKeyPEM([]byte(testCAPrivateKey), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCerts, err := parseCertificatesPEM([]byte(testCACert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvKey, _, err := parsePrivateKeyPEM([]byte(testPrivateKeyPEM), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The TBSCertificate and its signature are taken apart from a certificate issued by the test CA,
+	// as they would be produced by an external signer
+	certDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:     []string{"example.com"},
+	}, caCerts[0], pubKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbsBase64 := base64.StdEncoding.EncodeToString(cert.RawTBSCertificate)
+	signatureBase64 := base64.StdEncoding.EncodeToString(cert.Signature)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: certDER}))
+
+	tampered := append([]byte{}, cert.Signature...)
+	tampered[0] ^= 0xff
+
+	config := func(tbs, signature, signatureAlgorithm, extra string) string {
+		return fmt.Sprintf(`
+			data "tls_assemble_certificate" "test" {
+				tbs_certificate_base64 = %q
+				signature_base64       = %q
+				signature_algorithm    = %q
+				%s
+			}
+		`, tbs, signature, signatureAlgorithm, extra)
+	}
+	withCACert := fmt.Sprintf(`
+				ca_cert_pem = <<EOT
+%s
+EOT
+	`, testCACert)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(tbsBase64, signatureBase64, "SHA256-RSA", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_assemble_certificate.test", "cert_pem", certPEM),
+					resource.TestCheckResourceAttr("data.tls_assemble_certificate.test", "id", contentHash(certDER)),
+				),
+			},
+			{
+				Config: config(tbsBase64, signatureBase64, "SHA256-RSA", withCACert),
+				Check:  resource.TestCheckResourceAttr("data.tls_assemble_certificate.test", "cert_pem", certPEM),
+			},
+			{
+				Config:      config(tbsBase64, base64.StdEncoding.EncodeToString(tampered), "SHA256-RSA", withCACert),
+				ExpectError: regexp.MustCompile(`signature_base64 is not a valid signature by the key of ca_cert_pem`),
+			},
+			{
+				Config:      config(tbsBase64, signatureBase64, "ECDSA-SHA256", ""),
+				ExpectError: regexp.MustCompile(`signature_algorithm ECDSA-SHA256 doesn't match the signature algorithm\s+declared in tbs_certificate_base64: SHA256-RSA`),
+			},
+			{
+				Config:      config(base64.StdEncoding.EncodeToString([]byte("not a TBSCertificate")), signatureBase64, "SHA256-RSA", ""),
+				ExpectError: regexp.MustCompile(`invalid tbs_certificate_base64`),
+			},
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCABundle() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceCABundle,
+
+		Description: "Build a bundle of Certificate Authority (CA) certificates from multiple PEM-encoded certificates.\n\n" +
+			"Use this data source to concatenate certificates in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
+			"format into a single trust bundle, validating that each of them can be parsed.",
+
+		Schema: map[string]*schema.Schema{
+			"certificate_pem": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "List of certificates in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"Each element can contain one or more certificates, but nothing else.",
+			},
+
+			"deduplicate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to remove duplicate certificates from the bundle (default: `false`).",
+			},
+
+			"sort": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Whether to sort the certificates in the bundle by subject, " +
+					"instead of preserving the order they are given in (default: `false`).",
+			},
+
+			"bundle_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The bundle of certificates, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
+					"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
+					"value append a `\\n` at the end of the PEM. " +
+					"In case this disrupts your use case, we recommend using " +
+					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of the data source.",
+			},
+		},
+	}
+}
+
+func readDataSourceCABundle(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var certs []*x509.Certificate
+	for i, certPEMI := range d.Get("certificate_pem").([]interface{}) {
+		certPEM, _ := certPEMI.(string)
+
+		parsed, err := parseCertificatesPEM([]byte(certPEM))
+		if err != nil {
+			return diag.Errorf("invalid certificate_pem.%d: %s", i, err)
+		}
+		certs = append(certs, parsed...)
+	}
+
+	if d.Get("deduplicate").(bool) {
+		unique := make([]*x509.Certificate, 0, len(certs))
+		for _, cert := range certs {
+			duplicate := false
+			for _, u := range unique {
+				if cert.Equal(u) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				unique = append(unique, cert)
+			}
+		}
+		certs = unique
+	}
+
+	if d.Get("sort").(bool) {
+		sort.SliceStable(certs, func(i, j int) bool {
+			if si, sj := certs[i].Subject.String(), certs[j].Subject.String(); si != sj {
+				return si < sj
+			}
+			return bytes.Compare(certs[i].Raw, certs[j].Raw) < 0
+		})
+	}
+
+	var bundle strings.Builder
+	for _, cert := range certs {
+		bundle.Write(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: cert.Raw}))
+	}
+
+	d.SetId(hashForState(bundle.String()))
+
+	if err := d.Set("bundle_pem", bundle.String()); err != nil {
+		return diag.Errorf("error setting value on key 'bundle_pem': %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCABundle(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					locals {
+						ca_cert_pem = <<EOT
+%s
+EOT
+					}
+					data "tls_ca_bundle" "test" {
+						certificate_pem = [
+							file("testdata/tls_certs/public.pem"),
+							local.ca_cert_pem,
+							file("testdata/tls_certs/certificate.pem"),
+						]
+					}
+				`, testCACert),
+				Check: resource.TestCheckResourceAttrWith("data.tls_ca_bundle.test", "bundle_pem",
+					testCheckCABundleCommonNames("Child Cert", "Root CA", "root", "Child Cert"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					locals {
+						ca_cert_pem = <<EOT
+%s
+EOT
+					}
+					data "tls_ca_bundle" "test" {
+						certificate_pem = [
+							file("testdata/tls_certs/public.pem"),
+							local.ca_cert_pem,
+							file("testdata/tls_certs/certificate.pem"),
+						]
+						deduplicate = true
+						sort        = true
+					}
+				`, testCACert),
+				Check: resource.TestCheckResourceAttrWith("data.tls_ca_bundle.test", "bundle_pem",
+					testCheckCABundleCommonNames("Child Cert", "Root CA", "root"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCABundle_InvalidCertificates(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "tls_ca_bundle" "test" {
+						certificate_pem = [
+							file("testdata/tls_certs/certificate.pem"),
+							"not a certificate",
+						]
+					}
+				`,
+				ExpectError: regexp.MustCompile("invalid certificate_pem.1: failed to decode PEM block"),
+			},
+			{
+				Config: `
+					data "tls_ca_bundle" "test" {
+						certificate_pem = [
+							file("testdata/tls_certs/private.pem"),
+						]
+					}
+				`,
+				ExpectError: regexp.MustCompile("invalid certificate_pem.0: invalid PEM type"),
+			},
+			{
+				Config: `
+					data "tls_ca_bundle" "test" {
+						certificate_pem = []
+					}
+				`,
+				ExpectError: regexp.MustCompile("Attribute requires 1 item minimum, but config has only 0"),
+			},
+		},
+	})
+}
+
+func testCheckCABundleCommonNames(expected ...string) resource.CheckResourceAttrWithFunc {
+	return func(value string) error {
+		certs, err := parseCertificatesPEM([]byte(value))
+		if err != nil {
+			return err
+		}
+
+		if len(certs) != len(expected) {
+			return fmt.Errorf("incorrect number of certificates in bundle: expected %d, got %d", len(expected), len(certs))
+		}
+		for i, cert := range certs {
+			if cert.Subject.CommonName != expected[i] {
+				return fmt.Errorf("incorrect certificate at position %d: expected common name %q, got %q", i, expected[i], cert.Subject.CommonName)
+			}
+		}
+
+		return nil
+	}
+}
@@ -1,7 +1,11 @@
 package provider
 
 import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"testing"
@@ -24,7 +28,10 @@ func TestAccDataSourceCertificate_CertificateContent(t *testing.T) {
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "1"),
 
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.signature_algorithm", "SHA256-RSA"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.uses_weak_signature", "false"),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.public_key_algorithm", "RSA"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.rsa_bits", "2048"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ecdsa_curve", ""),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.serial_number", "266244246501122064554217434340898012243"),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.is_ca", "false"),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.version", "3"),
@@ -33,8 +40,228 @@ func TestAccDataSourceCertificate_CertificateContent(t *testing.T) {
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.not_before", "2019-11-08T09:01:36Z"),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.not_after", "2019-11-08T19:01:36Z"),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.sha1_fingerprint", "61b65624427d75b61169100836904e44364df817"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.dns_names.#", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ip_addresses.#", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.uris.#", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.email_addresses.#", "0"),
 					testCheckPEMFormat("data.tls_certificate.test", "certificates.0.cert_pem", PreambleCertificate),
 					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.cert_pem", strings.TrimSpace(testTlsDataSourceCertFromContent)+"\n"),
+
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.#", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "uses_weak_signature", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_WeakSignature(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "tls_certificate" "test" {
+					  content = file("testdata/tls_certs/certificate_sha1.pem")
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.signature_algorithm", "SHA1-RSA"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.uses_weak_signature", "true"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "uses_weak_signature", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_SubjectAlternativeNames(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+					  private_key_pem = <<EOT
+%s
+EOT
+					  subject {
+					    common_name = "example.com"
+					  }
+					  dns_names             = ["example.com", "*.example.net"]
+					  ip_addresses          = ["127.0.0.1", "::1"]
+					  uris                  = ["spiffe://example.com/workload"]
+					  validity_period_hours = 1
+					  allowed_uses          = ["server_auth"]
+					}
+
+					data "tls_certificate" "test" {
+					  content = tls_self_signed_cert.test.cert_pem
+					}
+				`, testPrivateKeyPEM),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.dns_names.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.dns_names.0", "example.com"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.dns_names.1", "*.example.net"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ip_addresses.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ip_addresses.0", "127.0.0.1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ip_addresses.1", "::1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.uris.#", "1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.uris.0", "spiffe://example.com/workload"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.email_addresses.#", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_VerifyHostname(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+					  private_key_pem = <<EOT
+%s
+EOT
+					  subject {
+					    common_name = "example.com"
+					  }
+					  dns_names             = ["example.com", "*.example.net"]
+					  ip_addresses          = ["127.0.0.1"]
+					  validity_period_hours = 1
+					  allowed_uses          = ["server_auth"]
+					}
+
+					data "tls_certificate" "exact" {
+					  content         = tls_self_signed_cert.test.cert_pem
+					  verify_hostname = "example.com"
+					}
+
+					data "tls_certificate" "wildcard" {
+					  content         = tls_self_signed_cert.test.cert_pem
+					  verify_hostname = "www.example.net"
+					}
+
+					data "tls_certificate" "wildcard_too_deep" {
+					  content         = tls_self_signed_cert.test.cert_pem
+					  verify_hostname = "a.www.example.net"
+					}
+
+					data "tls_certificate" "ip_address" {
+					  content         = tls_self_signed_cert.test.cert_pem
+					  verify_hostname = "127.0.0.1"
+					}
+
+					data "tls_certificate" "mismatch" {
+					  content         = tls_self_signed_cert.test.cert_pem
+					  verify_hostname = "example.org"
+					}
+
+					data "tls_certificate" "unset" {
+					  content = tls_self_signed_cert.test.cert_pem
+					}
+				`, testPrivateKeyPEM),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.exact", "hostname_valid", "true"),
+					resource.TestCheckResourceAttr("data.tls_certificate.exact", "hostname_error", ""),
+					resource.TestCheckResourceAttr("data.tls_certificate.wildcard", "hostname_valid", "true"),
+					resource.TestCheckResourceAttr("data.tls_certificate.wildcard", "hostname_error", ""),
+					resource.TestCheckResourceAttr("data.tls_certificate.wildcard_too_deep", "hostname_valid", "false"),
+					resource.TestMatchResourceAttr("data.tls_certificate.wildcard_too_deep", "hostname_error", regexp.MustCompile(`certificate is valid for example.com, \*.example.net, not a.www.example.net`)),
+					resource.TestCheckResourceAttr("data.tls_certificate.ip_address", "hostname_valid", "true"),
+					resource.TestCheckResourceAttr("data.tls_certificate.mismatch", "hostname_valid", "false"),
+					resource.TestMatchResourceAttr("data.tls_certificate.mismatch", "hostname_error", regexp.MustCompile(`not example.org`)),
+					resource.TestCheckResourceAttr("data.tls_certificate.unset", "hostname_valid", "false"),
+					resource.TestCheckResourceAttr("data.tls_certificate.unset", "hostname_error", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_OtherNameSANs(t *testing.T) {
+	upnDER, err := asn1.MarshalWithParams("user@example.com", "utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+					  cert_request_pem = <<EOT
+%s
+EOT
+					  ca_cert_pem = <<EOT
+%s
+EOT
+					  ca_private_key_pem = <<EOT
+%s
+EOT
+					  user_principal_names  = ["user@example.com", "other.user@example.net"]
+					  validity_period_hours = 1
+					  allowed_uses          = ["client_auth"]
+					}
+
+					data "tls_certificate" "test" {
+					  content = tls_locally_signed_cert.test.cert_pem
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.dns_names.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.0.type_id", "1.3.6.1.4.1.311.20.2.3"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.0.value", "user@example.com"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.0.value_base64", base64.StdEncoding.EncodeToString(upnDER)),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.1.type_id", "1.3.6.1.4.1.311.20.2.3"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.other_name_sans.1.value", "other.user@example.net"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_PublicKeyParameters(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+					  algorithm   = "ECDSA"
+					  ecdsa_curve = "P384"
+					}
+
+					resource "tls_self_signed_cert" "test" {
+					  private_key_pem = tls_private_key.test.private_key_pem
+					  subject {
+					    common_name = "example.com"
+					  }
+					  validity_period_hours = 1
+					  allowed_uses          = ["server_auth"]
+					}
+
+					data "tls_certificate" "test" {
+					  content = tls_self_signed_cert.test.cert_pem
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.public_key_algorithm", "ECDSA"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.rsa_bits", "0"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.ecdsa_curve", "P384"),
 				),
 			},
 		},
@@ -107,6 +334,68 @@ func TestAccDataSourceCertificate_HTTPSScheme(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceCertificate_VerifiedChain(t *testing.T) {
+	root, intermediate, leaf, leafKey, err := generateTestCertificateChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	verifyPeerCertificatesRoots = roots
+	defer func() { verifyPeerCertificatesRoots = nil }()
+
+	testCases := map[string][]*x509.Certificate{
+		"in order":     {leaf, intermediate},
+		"out of order": {leaf, root, intermediate},
+	}
+
+	for name, presented := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, err := newHTTPServerWithCertificateChain(presented, leafKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer server.Close()
+			go server.ServeTLS()
+
+			_, port, err := net.SplitHostPort(server.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				ProviderFactories: testProviders,
+
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+							data "tls_certificate" "test" {
+							  url = "tls://localhost:%s"
+							  verify_chain = true
+							}
+						`, port),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", fmt.Sprint(len(presented))),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verification_error", ""),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.#", "3"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.0.subject", "CN=localhost"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.0.issuer", "CN=Test Intermediate CA"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.0.is_ca", "false"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.1.subject", "CN=Test Intermediate CA"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.1.issuer", "CN=Test Root CA"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.1.is_ca", "true"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.2.subject", "CN=Test Root CA"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.2.issuer", "CN=Test Root CA"),
+							resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.2.is_ca", "true"),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
 func TestAccDataSourceCertificate_TLSScheme(t *testing.T) {
 	server, err := newHTTPServer()
 	if err != nil {
@@ -133,6 +422,219 @@ func TestAccDataSourceCertificate_TLSScheme(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceCertificate_AllowVerificationFailure(t *testing.T) {
+	server, err := newHTTPServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile("unable to verify the certificates presented by"),
+			},
+			{
+				// The chain presented by the local server is both untrusted and expired
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  allow_verification_failure = true
+					}
+				`, server.Address()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					localTestCertificateChainCheckFunc(),
+					resource.TestMatchResourceAttr("data.tls_certificate.test", "verification_error", regexp.MustCompile(`^x509: `)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  allow_verification_failure = true
+					}
+				`, server.Address()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					localTestCertificateChainCheckFunc(),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "verification_error", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_ClientCertificate(t *testing.T) {
+	server, err := newHTTPServerRequiringClientCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "https://%s"
+					  verify_chain = false
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile("failed to fetch certificates from URL"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "https://%s"
+					  verify_chain = false
+					  client_cert_pem = file("testdata/tls_certs/certificate.pem")
+					  client_key_pem = file("testdata/tls_certs/private.pem")
+					}
+				`, server.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  client_cert_pem = file("testdata/tls_certs/certificate.pem")
+					  client_key_pem = file("testdata/tls_certs/private.pem")
+					}
+				`, server.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  client_cert_pem = file("testdata/tls_certs/certificate.pem")
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile(`"client_cert_pem": all of\s+` + "`client_cert_pem,client_key_pem`" + `\s+must be specified`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_ClientCertificateChain(t *testing.T) {
+	server, err := newHTTPServerRequiringClientCertChain(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "https://%s"
+					  verify_chain = false
+					  client_cert_pem = file("testdata/tls_certs/certificate.pem")
+					  client_key_pem = file("testdata/tls_certs/private.pem")
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile("failed to fetch certificates from URL"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  client_cert_pem = file("testdata/tls_certs/public.pem")
+					  client_key_pem = file("testdata/tls_certs/private.pem")
+					}
+				`, server.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  client_cert_pem = join("", [file("testdata/tls_certs/public.pem"), file("testdata/tls_certs/private.pem")])
+					  client_key_pem = file("testdata/tls_certs/private.pem")
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile("unable to load client certificate chain: invalid PEM type"),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCertificate_ALPNProtocols(t *testing.T) {
+	server, err := newHTTPServerWithALPNCertificateChain("h2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					}
+				`, server.Address()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.0.subject", "CN=Child Cert,O=Child Co.,L=Everywhere"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  verify_chain = false
+					  alpn_protocols = ["h2", "http/1.1"]
+					}
+				`, server.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "https://%s"
+					  verify_chain = false
+					  alpn_protocols = ["h2"]
+					}
+				`, server.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: `
+					data "tls_certificate" "test" {
+					  content = file("testdata/tls_certs/certificate.pem")
+					  alpn_protocols = ["h2"]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`"alpn_protocols": conflicts with content`),
+			},
+		},
+	})
+}
+
 func TestAccDataSourceCertificate_HTTPSSchemeViaProxy(t *testing.T) {
 	server, err := newHTTPServer()
 	if err != nil {
@@ -363,6 +865,92 @@ func TestAccDataSourceCertificate_HTTPSSchemeViaProxyButNoProxyAvailable(t *test
 	})
 }
 
+func TestAccDataSourceCertificate_ProxyURL(t *testing.T) {
+	server, err := newHTTPServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	proxyUsername := "proxyUser"
+	proxyPassword := "proxyPwd"
+	proxy, err := newHTTPProxyServerWithBasicAuth(proxyUsername, proxyPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+	go proxy.Serve()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				// Credentials in the proxy URL
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  proxy_url = "http://%s:%s@%s"
+					  verify_chain = false
+					}
+				`, server.Address(), proxyUsername, proxyPassword, proxy.Address()),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				// Credentials via `proxy_auth`, taking precedence over the provider proxy
+				Config: fmt.Sprintf(`
+					provider "tls" {
+						proxy {
+							url = "http://localhost:65535"
+						}
+					}
+
+					data "tls_certificate" "test" {
+					  url = "https://%s"
+					  proxy_url = "http://%s"
+					  proxy_auth = "%s:%s"
+					  verify_chain = false
+					}
+				`, server.Address(), proxy.Address(), proxyUsername, proxyPassword),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  proxy_url = "http://%s"
+					  proxy_auth = "%s:wrong-password"
+					  verify_chain = false
+					}
+				`, server.Address(), proxy.Address(), proxyUsername),
+				ExpectError: regexp.MustCompile(`proxy refused to establish a tunnel: 407 Proxy Authentication Required`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  proxy_url = "http://%s:%s@%s"
+					  proxy_auth = "%s:%s"
+					  verify_chain = false
+					}
+				`, server.Address(), proxyUsername, proxyPassword, proxy.Address(), proxyUsername, proxyPassword),
+				ExpectError: regexp.MustCompile(`proxy_auth cannot be used when proxy_url contains credentials`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://%s"
+					  proxy_url = "http://localhost:65535"
+					  verify_chain = false
+					}
+				`, server.Address()),
+				ExpectError: regexp.MustCompile(`unable to connect to proxy: dial tcp`),
+			},
+		},
+	})
+}
+
 func localTestCertificateChainCheckFunc() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.#", "2"),
@@ -392,6 +980,9 @@ func localTestCertificateChainCheckFunc() resource.TestCheckFunc {
 		resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.1.sha1_fingerprint", "61b65624427d75b61169100836904e44364df817"),
 		testCheckPEMFormat("data.tls_certificate.test", "certificates.1.cert_pem", PreambleCertificate),
 		resource.TestCheckResourceAttr("data.tls_certificate.test", "certificates.1.cert_pem", strings.TrimSpace(testTlsDataSourceCertFromURL01)+"\n"),
+
+		// Chain is not verified, so no verified chain is reported
+		resource.TestCheckResourceAttr("data.tls_certificate.test", "verified_chain.#", "0"),
 	)
 }
 
@@ -463,3 +1054,54 @@ func TestAccDataSourceCertificate_MalformedURL(t *testing.T) {
 		},
 	})
 }
+
+func TestAccDataSourceCertificate_ResolveOverride(t *testing.T) {
+	server, err := newHTTPServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go server.ServeTLS()
+
+	_, port, err := net.SplitHostPort(server.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "https://backend.example.invalid:%s"
+					  verify_chain = false
+					  resolve_override = "127.0.0.1"
+					}
+				`, port),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://backend.example.invalid:%s"
+					  verify_chain = false
+					  resolve_override = "127.0.0.1"
+					}
+				`, port),
+				Check: localTestCertificateChainCheckFunc(),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_certificate" "test" {
+					  url = "tls://backend.example.invalid:%s"
+					  verify_chain = false
+					  resolve_override = "backend.example.com"
+					}
+				`, port),
+				ExpectError: regexp.MustCompile("expected resolve_override to contain a valid IP"),
+			},
+		},
+	})
+}
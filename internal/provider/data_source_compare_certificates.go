@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// comparedCertificateFields are the names of the fields compared by tls_compare_certificates,
+// in the order they are reported in `differences`: each has a corresponding `same_<field>` attribute.
+var comparedCertificateFields = []string{"subject", "issuer", "sans", "key", "key_usages", "is_ca"}
+
+func dataSourceCompareCertificates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceCompareCertificates,
+
+		Description: "Compare two certificates, for example to confirm that a newly issued certificate " +
+			"matches the one it replaces.\n\n" +
+			"The serial number and the validity period are deliberately not compared, " +
+			"as they are expected to change every time a certificate is issued; neither are the other extensions. " +
+			"If a PEM contains more than one certificate, only the first one is compared.",
+
+		Schema: map[string]*schema.Schema{
+			"first_certificate_pem": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The first certificate to compare, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"second_certificate_pem": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The second certificate to compare, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"same_subject": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if the two certificates have the same subject distinguished name, including the order of its attributes.",
+			},
+
+			"same_issuer": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if the two certificates have the same issuer distinguished name, including the order of its attributes.",
+			},
+
+			"same_sans": {
+				Type:     schema.TypeBool,
+				Computed: true,
+				Description: "`true` if the two certificates have the same subject alternative names " +
+					"(DNS names, IP addresses, email addresses and URIs), regardless of their order.",
+			},
+
+			"same_key": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if the two certificates have the same public key.",
+			},
+
+			"same_key_usages": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if the two certificates have the same key usages and extended key usages, regardless of their order.",
+			},
+
+			"same_is_ca": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if the two certificates are either both CA certificates, or both not.",
+			},
+
+			"differences": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The names of the fields that differ between the two certificates, among " +
+					"`subject`, `issuer`, `sans`, `key`, `key_usages` and `is_ca`: " +
+					"each corresponds to one of the `same_*` attributes. " +
+					"Empty if the certificates match on all of them.",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of the two certificates.",
+			},
+		},
+	}
+}
+
+func readDataSourceCompareCertificates(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	firstPEM := d.Get("first_certificate_pem").(string)
+	secondPEM := d.Get("second_certificate_pem").(string)
+
+	firstCerts, err := parseCertificatesPEM([]byte(firstPEM))
+	if err != nil {
+		return diag.Errorf("unable to parse first_certificate_pem: %v", err)
+	}
+	secondCerts, err := parseCertificatesPEM([]byte(secondPEM))
+	if err != nil {
+		return diag.Errorf("unable to parse second_certificate_pem: %v", err)
+	}
+
+	same := compareCertificates(firstCerts[0], secondCerts[0])
+
+	differences := make([]string, 0)
+	for _, field := range comparedCertificateFields {
+		if err := d.Set("same_"+field, same[field]); err != nil {
+			return diag.Errorf("error setting value on key 'same_%s': %s", field, err)
+		}
+		if !same[field] {
+			differences = append(differences, field)
+		}
+	}
+
+	if err := d.Set("differences", differences); err != nil {
+		return diag.Errorf("error setting value on key 'differences': %s", err)
+	}
+
+	d.SetId(hashForState(firstPEM + secondPEM))
+
+	return nil
+}
+
+// compareCertificates returns, for each of the comparedCertificateFields,
+// whether the two given certificates have the same value for it.
+func compareCertificates(a, b *x509.Certificate) map[string]bool {
+	return map[string]bool{
+		"subject":    bytes.Equal(a.RawSubject, b.RawSubject),
+		"issuer":     bytes.Equal(a.RawIssuer, b.RawIssuer),
+		"sans":       stringSetsEqual(certificateSANs(a), certificateSANs(b)),
+		"key":        publicKeysEqual(a.PublicKey, b.PublicKey),
+		"key_usages": stringSetsEqual(certificateKeyUsages(a), certificateKeyUsages(b)),
+		"is_ca":      a.IsCA == b.IsCA,
+	}
+}
+
+// certificateSANs returns the subject alternative names of the given certificate,
+// each prefixed by its type so that, for example, a DNS name and a URI can't be mistaken for each other.
+func certificateSANs(cert *x509.Certificate) []string {
+	var res []string
+
+	for _, dnsName := range cert.DNSNames {
+		res = append(res, "dns:"+strings.ToLower(dnsName))
+	}
+	for _, ip := range cert.IPAddresses {
+		res = append(res, "ip:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		res = append(res, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		res = append(res, "uri:"+uri.String())
+	}
+
+	return res
+}
+
+// certificateKeyUsages returns the names of the key usages of the given certificate, as in certificateUsages,
+// together with the OIDs of the extended key usages that don't have a name.
+func certificateKeyUsages(cert *x509.Certificate) []string {
+	res := certificateUsages(cert)
+	for _, oid := range cert.UnknownExtKeyUsage {
+		res = append(res, fmt.Sprintf("oid:%s", oid))
+	}
+
+	return res
+}
+
+// stringSetsEqual returns true if the two given slices contain the same strings, regardless of their order.
+func stringSetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
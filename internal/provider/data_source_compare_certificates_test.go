@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const configDataSourceCompareCertificates = `
+resource "tls_self_signed_cert" "old" {
+	private_key_pem       = <<EOF
+%[1]s
+EOF
+	subject {
+		common_name  = "example.com"
+		organization = "Example, Inc"
+	}
+	dns_names             = ["example.com", "www.example.com"]
+	validity_period_hours = 1
+	allowed_uses          = ["digital_signature", "server_auth"]
+}
+
+resource "tls_self_signed_cert" "new" {
+	private_key_pem       = <<EOF
+%[2]s
+EOF
+	subject {
+		common_name  = "example.com"
+		organization = "Example, Inc"
+	}
+	dns_names             = %[3]s
+	validity_period_hours = 2
+	allowed_uses          = ["server_auth", "digital_signature"]
+}
+
+data "tls_compare_certificates" "test" {
+	first_certificate_pem  = tls_self_signed_cert.old.cert_pem
+	second_certificate_pem = tls_self_signed_cert.new.cert_pem
+}
+`
+
+func TestAccDataSourceCompareCertificates(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configDataSourceCompareCertificates, testPrivateKeyPEM, testPrivateKeyPEM, `["www.example.com", "example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_subject", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_issuer", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_sans", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_key", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_key_usages", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_is_ca", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "differences.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceCompareCertificates, testPrivateKeyPEM, testCAPrivateKey, `["example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_subject", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_sans", "false"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_key", "false"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "same_key_usages", "true"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "differences.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "differences.0", "sans"),
+					resource.TestCheckResourceAttr("data.tls_compare_certificates.test", "differences.1", "key"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_compare_certificates" "test" {
+						first_certificate_pem  = <<EOF
+%s
+EOF
+						second_certificate_pem = "not a certificate"
+					}
+				`, testCACert),
+				ExpectError: regexp.MustCompile("unable to parse second_certificate_pem: failed to decode PEM block"),
+			},
+		},
+	})
+}
@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/terraform-providers/terraform-provider-tls/internal/openssh"
+)
+
+func dataSourceConvertKey() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceConvertKey,
+
+		Description: "Convert a key between PEM and OpenSSH formats.\n\n" +
+			"Use this data source to convert a private or public key from " +
+			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) to " +
+			"[OpenSSH](https://datatracker.ietf.org/doc/html/rfc4716) format, or vice versa.",
+
+		Schema: map[string]*schema.Schema{
+			"input_key": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+				Description: "The key to convert. This can be a private key in " +
+					"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or " +
+					"[OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format, " +
+					"or a public key in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or " +
+					"['Authorized Keys'](https://www.ssh.com/academy/ssh/authorized_keys/openssh#format-of-the-authorized-keys-file) format. " +
+					"Currently-supported algorithms for keys are `RSA`, `ECDSA` and `ED25519`.",
+			},
+
+			"target_format": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedKeyFormatsStr(), false)),
+				Description: "The format to convert the key to. " +
+					"Accepted values are `PEM` and `OpenSSH`. " +
+					"`ECDSA` keys with curve `P224` [cannot be converted](../../docs#limitations) to `OpenSSH`.",
+			},
+
+			"algorithm": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The name of the algorithm used by the given key. " +
+					"Possible values are: `RSA`, `ECDSA` and `ED25519`.",
+			},
+
+			"private_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The private key, converted to `target_format`. " +
+					"This is empty if `input_key` is a public key.",
+			},
+
+			"public_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The public key, converted to `target_format`. " +
+					"If `input_key` is a private key, this is the corresponding public key. " +
+					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
+					"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
+					"value append a `\\n` at the end of the key. " +
+					"In case this disrupts your use case, we recommend using " +
+					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of the data source.",
+			},
+		},
+	}
+}
+
+func readDataSourceConvertKey(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	prvKey, pubKey, err := parseKey([]byte(d.Get("input_key").(string)), m.(*providerConfig).strictPEM)
+	if err != nil {
+		return diag.Errorf("invalid input_key: %s", err)
+	}
+
+	algorithm, err := publicKeyToAlgorithm(pubKey)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("algorithm", algorithm); err != nil {
+		return diag.Errorf("error setting value on key 'algorithm': %s", err)
+	}
+
+	var convertedPrvKey, convertedPubKey string
+	switch KeyFormat(d.Get("target_format").(string)) {
+	case KeyFormatPEM:
+		if prvKey != nil {
+			prvKeyPemBlock, err := privateKeyToPEMBlock(prvKey)
+			if err != nil {
+				return diag.Errorf("unable to marshal private key into PEM format: %v", err)
+			}
+			convertedPrvKey = string(pem.EncodeToMemory(prvKeyPemBlock))
+		}
+
+		_, convertedPubKey, err = publicKeyToPEM(pubKey)
+		if err != nil {
+			return diag.Errorf("unable to marshal public key into PEM format: %v", err)
+		}
+	case KeyFormatOpenSSH:
+		// GOTCHA: `x/crypto/ssh` doesn't handle elliptic curve P-224
+		if !publicKeySupportsOpenSSH(pubKey) {
+			_, ecdsaCurve := publicKeyParameters(pubKey)
+			return diag.Errorf("ECDSA keys with curve %s cannot be converted to OpenSSH format", ecdsaCurve)
+		}
+
+		if prvKey != nil {
+			prvKeyOpenSSHPemBlock, err := openssh.MarshalPrivateKey(prvKey, "")
+			if err != nil {
+				return diag.Errorf("unable to marshal private key into OpenSSH format: %v", err)
+			}
+			convertedPrvKey = string(pem.EncodeToMemory(prvKeyOpenSSHPemBlock))
+		}
+
+		convertedPubKey, _, _ = publicKeyToOpenSSH(pubKey)
+	}
+
+	d.SetId(hashForState(convertedPubKey))
+
+	if err := d.Set("private_key", convertedPrvKey); err != nil {
+		return diag.Errorf("error setting value on key 'private_key': %s", err)
+	}
+
+	if err := d.Set("public_key", convertedPubKey); err != nil {
+		return diag.Errorf("error setting value on key 'public_key': %s", err)
+	}
+
+	return nil
+}
+
+// parseKey takes a slice of bytes containing either a private key, encoded in
+// [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or [OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format,
+// or a public key, encoded in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or OpenSSH 'Authorized Keys' format.
+// It returns the public key and, if the input is a private key, the private key as well.
+// See parsePrivateKeyPEM for the meaning of strict.
+func parseKey(keyBytes []byte, strict bool) (crypto.PrivateKey, crypto.PublicKey, error) {
+	pemBlock, _ := pem.Decode(keyBytes)
+
+	// If it's not PEM, it can only be an OpenSSH public key
+	if pemBlock == nil {
+		sshPubKey, _, _, _, err := ssh.ParseAuthorizedKey(keyBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse key: it's neither in PEM nor in OpenSSH format")
+		}
+
+		cryptoPubKey, ok := sshPubKey.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported OpenSSH public key type: %s", sshPubKey.Type())
+		}
+
+		return nil, cryptoPubKey.CryptoPublicKey(), nil
+	}
+
+	var prvKey crypto.PrivateKey
+	var err error
+	switch pemBlock.Type {
+	case PreamblePublicKey.String():
+		pubKey, err := x509.ParsePKIXPublicKey(pemBlock.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return nil, pubKey, nil
+	case PreamblePrivateKeyOpenSSH.String():
+		prvKey, _, err = parsePrivateKeyOpenSSHPEM(keyBytes, nil)
+	default:
+		prvKey, _, err = parsePrivateKeyPEM(keyBytes, strict)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return prvKey, pubKey, nil
+}
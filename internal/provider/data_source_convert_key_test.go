@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const configDataSourceConvertKey = `
+data "tls_convert_key" "test" {
+	input_key = <<EOF
+%s
+EOF
+	target_format = "%s"
+}
+`
+
+func TestAccDataSourceConvertKey_PrivateKey(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configDataSourceConvertKey, testPrivateKeyPEM, "OpenSSH"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "algorithm", "RSA"),
+					testCheckPEMFormat("data.tls_convert_key.test", "private_key", PreamblePrivateKeyOpenSSH),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "public_key", strings.TrimSpace(testPublicKeyOpenSSH)+"\n"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceConvertKey, testPrivateKeyOpenSSHPEM, "PEM"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "algorithm", "RSA"),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "private_key", strings.TrimSpace(testPrivateKeyPEM)+"\n"),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "public_key", strings.TrimSpace(testPublicKeyPEM)+"\n"),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					data "tls_convert_key" "openssh" {
+						input_key     = tls_private_key.test.private_key_pem
+						target_format = "OpenSSH"
+					}
+					data "tls_convert_key" "test" {
+						input_key     = data.tls_convert_key.openssh.private_key
+						target_format = "PEM"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "algorithm", "ED25519"),
+					resource.TestCheckResourceAttrPair(
+						"data.tls_convert_key.openssh", "public_key",
+						"tls_private_key.test", "public_key_openssh",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.tls_convert_key.test", "private_key",
+						"tls_private_key.test", "private_key_pem",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.tls_convert_key.test", "public_key",
+						"tls_private_key.test", "public_key_pem",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceConvertKey_PublicKey(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configDataSourceConvertKey, testPublicKeyPEM, "OpenSSH"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "algorithm", "RSA"),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "private_key", ""),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "public_key", strings.TrimSpace(testPublicKeyOpenSSH)+"\n"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceConvertKey, testPublicKeyOpenSSH, "PEM"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "algorithm", "RSA"),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "private_key", ""),
+					resource.TestCheckResourceAttr("data.tls_convert_key.test", "public_key", strings.TrimSpace(testPublicKeyPEM)+"\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceConvertKey_Errors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P224"
+					}
+					data "tls_convert_key" "test" {
+						input_key     = tls_private_key.test.private_key_pem
+						target_format = "OpenSSH"
+					}
+				`,
+				ExpectError: regexp.MustCompile("ECDSA keys with curve P224 cannot be converted to OpenSSH format"),
+			},
+			{
+				Config:      fmt.Sprintf(configDataSourceConvertKey, "not a key", "PEM"),
+				ExpectError: regexp.MustCompile("invalid input_key: failed to parse key"),
+			},
+			{
+				Config:      fmt.Sprintf(configDataSourceConvertKey, testPublicKeyPEM, "DER"),
+				ExpectError: regexp.MustCompile(`expected target_format to be one of \[PEM OpenSSH\], got DER`),
+			},
+		},
+	})
+}
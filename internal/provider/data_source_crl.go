@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxCRLSize is the maximum size of a CRL fetched via `url`:
+// large public CAs publish CRLs of a few tens of MiB, so this leaves plenty of room.
+const maxCRLSize = 128 << 20
+
+func dataSourceCRL() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceCRL,
+
+		Description: "Get information about a [Certificate Revocation List (CRL)](https://datatracker.ietf.org/doc/html/rfc5280#section-5), " +
+			"fetching it from a URL or parsing it from its content, in either PEM or DER format.\n\n" +
+			"The signature of the CRL is not verified.",
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{
+					HTTPScheme.String(),
+					HTTPSScheme.String(),
+				})),
+				ExactlyOneOf: []string{"url", "content", "content_base64"},
+				Description: "URL to fetch the CRL from, as found in the CRL distribution points of a certificate. " +
+					"Accepted schemes are: `http`, `https`. The `proxy` configuration of the provider is applied, if set. " +
+					"The CRL can be served in either PEM or DER format.",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"url", "content", "content_base64"},
+				Description:  "The content of the CRL in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+			"content_base64": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				ExactlyOneOf:     []string{"url", "content", "content_base64"},
+				Description: "The content of the CRL in DER format, encoded in base64 " +
+					"(e.g. via [`filebase64()`](https://www.terraform.io/language/functions/filebase64)).",
+			},
+			"timeout": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          30,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				ConflictsWith:    []string{"content", "content_base64"},
+				Description:      "Number of seconds after which fetching the CRL via `url` is aborted (default: `30`).",
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Who issued and signed the CRL, roughly following " +
+					"[RFC2253](https://tools.ietf.org/html/rfc2253).",
+			},
+			"this_update": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The time the CRL was issued at, as an " +
+					"[RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+			},
+			"next_update": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The time by which the next CRL will be issued, as an " +
+					"[RFC3339](https://tools.ietf.org/html/rfc3339) timestamp (empty if the CRL doesn't specify it).",
+			},
+			"crl_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The [CRL number](https://datatracker.ietf.org/doc/html/rfc5280#section-5.2.3), " +
+					"as a _base 10_ number (empty if the CRL doesn't include it).",
+			},
+			"revoked_certificates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"serial_number": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Description: "Serial number of the revoked certificate. " +
+								"The `format` function can be used to convert this _base 10_ number " +
+								"into other bases, such as hex.",
+						},
+						"revocation_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Description: "The time the certificate was revoked at, as an " +
+								"[RFC3339](https://tools.ietf.org/html/rfc3339) timestamp.",
+						},
+					},
+				},
+				Description: "The certificates revoked by the CRL, in the order they are listed in it.",
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA256 checksum of the CRL in DER format.",
+			},
+		},
+	}
+}
+
+func readDataSourceCRL(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var crlBytes []byte
+	if v, ok := d.GetOk("url"); ok {
+		var err error
+		timeout := time.Duration(d.Get("timeout").(int)) * time.Second
+		crlBytes, err = fetchCRL(ctx, v.(string), timeout, m.(*providerConfig))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else if v, ok := d.GetOk("content_base64"); ok {
+		var err error
+		crlBytes, err = base64.StdEncoding.DecodeString(v.(string))
+		if err != nil {
+			return diag.Errorf("unable to decode content_base64: %v", err)
+		}
+	} else {
+		crlBytes = []byte(d.Get("content").(string))
+	}
+
+	crlDER, err := crlBytesToDER(crlBytes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	crl, err := x509.ParseRevocationList(crlDER)
+	if err != nil {
+		return diag.Errorf("unable to parse CRL: %v", err)
+	}
+
+	if err := d.Set("issuer", crl.Issuer.String()); err != nil {
+		return diag.Errorf("error setting value on key 'issuer': %s", err)
+	}
+
+	if err := d.Set("this_update", crl.ThisUpdate.Format(time.RFC3339)); err != nil {
+		return diag.Errorf("error setting value on key 'this_update': %s", err)
+	}
+
+	var nextUpdate string
+	if !crl.NextUpdate.IsZero() {
+		nextUpdate = crl.NextUpdate.Format(time.RFC3339)
+	}
+	if err := d.Set("next_update", nextUpdate); err != nil {
+		return diag.Errorf("error setting value on key 'next_update': %s", err)
+	}
+
+	var crlNumber string
+	if crl.Number != nil {
+		crlNumber = crl.Number.String()
+	}
+	if err := d.Set("crl_number", crlNumber); err != nil {
+		return diag.Errorf("error setting value on key 'crl_number': %s", err)
+	}
+
+	revokedCerts := make([]interface{}, len(crl.RevokedCertificates))
+	for i, revokedCert := range crl.RevokedCertificates {
+		revokedCerts[i] = map[string]interface{}{
+			"serial_number":   revokedCert.SerialNumber.String(),
+			"revocation_time": revokedCert.RevocationTime.Format(time.RFC3339),
+		}
+	}
+	if err := d.Set("revoked_certificates", revokedCerts); err != nil {
+		return diag.Errorf("error setting value on key 'revoked_certificates': %s", err)
+	}
+
+	d.SetId(contentHash(crlDER))
+
+	return nil
+}
+
+// fetchCRL fetches the CRL at the given URL, applying the proxy configuration of the provider,
+// and giving up after the given timeout.
+func fetchCRL(ctx context.Context, crlURL string, timeout time.Duration, config *providerConfig) ([]byte, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: config.proxyForRequestFunc(),
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, crlURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for CRL '%s': %w", crlURL, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CRL from URL '%s': %w", crlURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch CRL from URL '%s': got response status %s", crlURL, resp.Status)
+	}
+
+	crlBytes, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRL from URL '%s': %w", crlURL, err)
+	}
+	if len(crlBytes) > maxCRLSize {
+		return nil, fmt.Errorf("failed to read CRL from URL '%s': larger than %d bytes", crlURL, maxCRLSize)
+	}
+
+	return crlBytes, nil
+}
+
+// crlBytesToDER returns the DER encoding of the given CRL, that can be in either PEM or DER format.
+func crlBytesToDER(crlBytes []byte) ([]byte, error) {
+	// NOTE: a DER-encoded CRL always starts with the tag of an ASN.1 SEQUENCE,
+	// so anything that looks like the start of a PEM block is treated as such
+	if !bytes.HasPrefix(bytes.TrimSpace(crlBytes), []byte("-----BEGIN")) {
+		return crlBytes, nil
+	}
+
+	block, _ := pem.Decode(crlBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block containing the CRL")
+	}
+
+	preamble, err := PEMBlockToPEMPreamble(block)
+	if err != nil {
+		return nil, err
+	}
+	if preamble != PreambleCRL {
+		return nil, fmt.Errorf("PEM must be of type '%s'", PreambleCRL)
+	}
+
+	return block.Bytes, nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCRL(t *testing.T) {
+	block, _ := pem.Decode([]byte(testCRL))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_crl" "test" {
+						content = <<EOF
+%s
+EOF
+					}
+				`, testCRL),
+				Check: testCheckCRLAttributes("data.tls_crl.test"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_crl" "test" {
+						content_base64 = "%s"
+					}
+				`, base64.StdEncoding.EncodeToString(block.Bytes)),
+				Check: testCheckCRLAttributes("data.tls_crl.test"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_crl" "test" {
+						content = <<EOF
+%s
+EOF
+					}
+				`, testCACert),
+				ExpectError: regexp.MustCompile("PEM must be of type 'X509 CRL'"),
+			},
+			{
+				Config: `
+					data "tls_crl" "test" {}
+				`,
+				ExpectError: regexp.MustCompile("\"url\": one of `content,content_base64,url` must be specified"),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceCRL_URL(t *testing.T) {
+	block, _ := pem.Decode([]byte(testCRL))
+
+	server, err := newHTTPServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/crl.der", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/pkix-crl")
+		_, _ = w.Write(block.Bytes)
+	})
+	mux.HandleFunc("/crl.pem", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testCRL))
+	})
+	mux.HandleFunc("/slow.crl", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(3 * time.Second)
+		_, _ = w.Write(block.Bytes)
+	})
+	server.server.Handler = mux
+	defer server.Close()
+	go server.Serve()
+
+	config := func(path, extra string) string {
+		return fmt.Sprintf(`
+			data "tls_crl" "test" {
+				url = "http://%s%s"
+				%s
+			}
+		`, server.Address(), path, extra)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config("/crl.der", ""),
+				Check:  testCheckCRLAttributes("data.tls_crl.test"),
+			},
+			{
+				Config: config("/crl.pem", ""),
+				Check:  testCheckCRLAttributes("data.tls_crl.test"),
+			},
+			{
+				Config:      config("/missing.crl", ""),
+				ExpectError: regexp.MustCompile("got response status 404 Not Found"),
+			},
+			{
+				Config:      config("/slow.crl", "timeout = 1"),
+				ExpectError: regexp.MustCompile("Client.Timeout exceeded"),
+			},
+		},
+	})
+}
+
+func testCheckCRLAttributes(name string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr(name, "issuer", "CN=root,OU=Department of CA Testing,O=Example\\, Inc,L=Pirate Harbor,ST=CA,C=US"),
+		resource.TestCheckResourceAttr(name, "this_update", "2026-10-16T16:47:53Z"),
+		resource.TestCheckResourceAttr(name, "next_update", "2036-10-13T16:47:53Z"),
+		resource.TestCheckResourceAttr(name, "crl_number", "4096"),
+		resource.TestCheckResourceAttr(name, "revoked_certificates.#", "2"),
+		resource.TestCheckResourceAttr(name, "revoked_certificates.0.serial_number", "10"),
+		resource.TestCheckResourceAttr(name, "revoked_certificates.0.revocation_time", "2022-06-01T12:00:00Z"),
+		resource.TestCheckResourceAttr(name, "revoked_certificates.1.serial_number", "305441741"),
+		resource.TestCheckResourceAttr(name, "revoked_certificates.1.revocation_time", "2022-06-15T08:30:00Z"),
+		resource.TestMatchResourceAttr(name, "id", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+	)
+}
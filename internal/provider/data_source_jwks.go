@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceJWKS() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceJWKS,
+
+		Description: "Assemble public keys into a JSON Web Key Set.\n\n" +
+			"Use this data source to produce the [JWK Set (RFC 7517)](https://datatracker.ietf.org/doc/html/rfc7517#section-5) " +
+			"published by a JWKS endpoint (e.g. of an OpenID Connect provider), " +
+			"identifying each key by its [JWK Thumbprint (RFC 7638)](https://datatracker.ietf.org/doc/html/rfc7638).",
+
+		Schema: map[string]*schema.Schema{
+			"public_keys": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "List of public keys to include in the set, in the order they are given. " +
+					"Each key can be in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) or " +
+					"['Authorized Keys'](https://www.ssh.com/academy/ssh/authorized_keys/openssh#format-of-the-authorized-keys-file) format " +
+					"(e.g. the `public_key_pem` of a `tls_private_key`). " +
+					"Currently-supported algorithms for keys are `RSA`, `ECDSA` (with curves `P256`, `P384` and `P521`) and `ED25519`.",
+			},
+
+			"use": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"sig", "enc"}, false)),
+				Description: "Intended use of the keys, set as the `use` member of each key: " +
+					"`sig` (signature) or `enc` (encryption). If not set, the member is omitted.",
+			},
+
+			"jwks": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The JWK Set, in JSON format. Each key has a `kid` member " +
+					"set to its JWK Thumbprint.",
+			},
+
+			"kids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The key IDs (i.e. the base64url-encoded SHA256 JWK Thumbprints) of the keys in the set, " +
+					"in the same order as `public_keys`.",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of the JWK Set.",
+			},
+		},
+	}
+}
+
+func readDataSourceJWKS(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	pubKeysI := d.Get("public_keys").([]interface{})
+
+	keys := make([]*jsonWebKey, len(pubKeysI))
+	kids := make([]string, len(pubKeysI))
+	for i, pubKeyI := range pubKeysI {
+		pubKeyStr, _ := pubKeyI.(string)
+
+		prvKey, pubKey, err := parseKey([]byte(pubKeyStr), m.(*providerConfig).strictPEM)
+		if err != nil {
+			return diag.Errorf("invalid public_keys.%d: %s", i, err)
+		}
+		if prvKey != nil {
+			return diag.Errorf("invalid public_keys.%d: expected a public key, got a private key", i)
+		}
+
+		keys[i], err = publicKeyToJWK(pubKey)
+		if err != nil {
+			return diag.Errorf("invalid public_keys.%d: %s", i, err)
+		}
+
+		kids[i], err = keys[i].thumbprint()
+		if err != nil {
+			return diag.Errorf("failed to compute thumbprint of public_keys.%d: %s", i, err)
+		}
+		keys[i].Kid = kids[i]
+		keys[i].Use = d.Get("use").(string)
+	}
+
+	jwks, err := json.Marshal(struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}{keys})
+	if err != nil {
+		return diag.Errorf("failed to marshal JWK Set: %s", err)
+	}
+
+	d.SetId(hashForState(string(jwks)))
+
+	if err := d.Set("jwks", string(jwks)); err != nil {
+		return diag.Errorf("error setting value on key 'jwks': %s", err)
+	}
+
+	if err := d.Set("kids", kids); err != nil {
+		return diag.Errorf("error setting value on key 'kids': %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// Public keys of the examples of RFC 7638 (section 3.1) and RFC 8037 (appendix A.2),
+// with their JWK Thumbprints.
+const (
+	testJWKSPublicKeyRSA = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA0vx7agoebGcQSuuPiLJX
+ZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tS
+oc/BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ/2W+5JsGY4Hc5n9yBXArwl93lqt
+7/RN5w6Cf0h4QyQ5v+65YGjQR0/FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0
+zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt+bFTWhAI4vMQFh6WeZu0f
+M4lFd2NcRwr3XPksINHaQ+G/xBniIqbw0Ls1jF44+csFCur+kEgU8awapJzKnqDK
+gwIDAQAB
+-----END PUBLIC KEY-----
+`
+	testJWKSPublicKeyRSAThumbprint = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+	testJWKSPublicKeyED25519 = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEA11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=
+-----END PUBLIC KEY-----
+`
+	testJWKSPublicKeyED25519Thumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+)
+
+func TestAccDataSourceJWKS(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_jwks" "test" {
+						public_keys = [
+							<<EOT
+%s
+EOT
+							,
+							<<EOT
+%s
+EOT
+						]
+						use = "sig"
+					}
+				`, testJWKSPublicKeyRSA, testJWKSPublicKeyED25519),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_jwks.test", "kids.#", "2"),
+					resource.TestCheckResourceAttr("data.tls_jwks.test", "kids.0", testJWKSPublicKeyRSAThumbprint),
+					resource.TestCheckResourceAttr("data.tls_jwks.test", "kids.1", testJWKSPublicKeyED25519Thumbprint),
+					resource.TestCheckResourceAttr("data.tls_jwks.test", "jwks", `{"keys":[`+
+						`{"e":"AQAB","kid":"`+testJWKSPublicKeyRSAThumbprint+`","kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","use":"sig"},`+
+						`{"crv":"Ed25519","kid":"`+testJWKSPublicKeyED25519Thumbprint+`","kty":"OKP","use":"sig","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`+
+						`]}`),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P384"
+					}
+					data "tls_jwks" "test" {
+						public_keys = [tls_private_key.test.public_key_pem]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_jwks.test", "kids.#", "1"),
+					resource.TestMatchResourceAttr("data.tls_jwks.test", "jwks", regexp.MustCompile(`^\{"keys":\[\{"crv":"P-384","kid":"[A-Za-z0-9_-]{43}","kty":"EC","x":"[A-Za-z0-9_-]{64}","y":"[A-Za-z0-9_-]{64}"\}\]\}$`)),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P224"
+					}
+					data "tls_jwks" "test" {
+						public_keys = [tls_private_key.test.public_key_pem]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`invalid public_keys.0: ECDSA keys with curve P-224 cannot be converted to JWK`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_jwks" "test" {
+						public_keys = [
+							<<EOT
+%s
+EOT
+						]
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`invalid public_keys.0: expected a public key, got a private key`),
+			},
+		},
+	})
+}
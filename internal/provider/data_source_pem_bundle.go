@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"encoding/pem"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcePEMBundle() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourcePEMBundle,
+
+		Description: "Assemble a private key, its certificate and the certificate chain into a single PEM bundle.\n\n" +
+			"Use this data source to produce the single [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
+			"file expected by software like HAProxy or some ingress controllers, " +
+			"validating that each component can be parsed and that the private key matches the certificate.",
+
+		Schema: map[string]*schema.Schema{
+			"private_key_pem": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Private key in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"cert_pem": {
+				Type:     schema.TypeString,
+				Required: true,
+				Description: "Certificate of `private_key_pem`, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"It must contain a single certificate: intermediate certificates go in `chain_pem`.",
+			},
+
+			"chain_pem": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Chain of certificates of `cert_pem`, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"The certificates are added to the bundle in the order they are given.",
+			},
+
+			"order": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          PEMBundleOrderCertFirst.String(),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedPEMBundleOrdersStr(), false)),
+				Description: "Order of the components in the bundle: " +
+					"`cert_first` places the certificate first, followed by the chain and then the private key; " +
+					"`key_first` places the private key first, followed by the certificate and then the chain " +
+					"(default: `cert_first`).",
+			},
+
+			"bundle_pem": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The bundle of private key and certificates, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"**NOTE**: the [underlying](https://pkg.go.dev/encoding/pem#Encode) " +
+					"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
+					"value append a `\\n` at the end of the PEM. " +
+					"In case this disrupts your use case, we recommend using " +
+					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of the certificates in the bundle.",
+			},
+		},
+	}
+}
+
+func readDataSourcePEMBundle(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	prvKeyPEM := []byte(d.Get("private_key_pem").(string))
+	prvKey, _, err := parsePrivateKeyPEM(prvKeyPEM, m.(*providerConfig).strictPEM)
+	if err != nil {
+		return diag.Errorf("invalid private_key_pem: %s", err)
+	}
+	// NOTE: the private key is added to the bundle as given, preserving its encoding
+	prvKeyBlock, _ := pem.Decode(prvKeyPEM)
+
+	certs, err := parseCertificatesPEM([]byte(d.Get("cert_pem").(string)))
+	if err != nil {
+		return diag.Errorf("invalid cert_pem: %s", err)
+	}
+	if len(certs) != 1 {
+		return diag.Errorf("invalid cert_pem: expected a single certificate, got %d", len(certs))
+	}
+	cert := certs[0]
+
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return diag.Errorf("failed to get public key from private_key_pem: %s", err)
+	}
+	if k, ok := pubKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(cert.PublicKey) {
+		return diag.Errorf("private_key_pem does not match the public key of the certificate in cert_pem")
+	}
+
+	var certsPEM strings.Builder
+	certsPEM.Write(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: cert.Raw}))
+	if chainPEM := d.Get("chain_pem").(string); chainPEM != "" {
+		chain, err := parseCertificatesPEM([]byte(chainPEM))
+		if err != nil {
+			return diag.Errorf("invalid chain_pem: %s", err)
+		}
+		for _, chainCert := range chain {
+			certsPEM.Write(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: chainCert.Raw}))
+		}
+	}
+
+	var bundle string
+	switch PEMBundleOrder(d.Get("order").(string)) {
+	case PEMBundleOrderKeyFirst:
+		bundle = string(pem.EncodeToMemory(prvKeyBlock)) + certsPEM.String()
+	case PEMBundleOrderCertFirst:
+		bundle = certsPEM.String() + string(pem.EncodeToMemory(prvKeyBlock))
+	default:
+		// NOTE: This should never happen, given we validate this at the schema level
+		return diag.Errorf("unsupported order: %s", d.Get("order").(string))
+	}
+
+	// NOTE: the identifier is derived from the certificates only, to avoid leaking the private key
+	d.SetId(hashForState(certsPEM.String()))
+
+	if err := d.Set("bundle_pem", bundle); err != nil {
+		return diag.Errorf("error setting value on key 'bundle_pem': %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourcePEMBundle(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_pem_bundle" "test" {
+						private_key_pem = file("testdata/tls_certs/private.pem")
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+						chain_pem       = <<EOT
+%s
+EOT
+					}
+				`, testCACert),
+				Check: resource.TestCheckResourceAttrWith("data.tls_pem_bundle.test", "bundle_pem",
+					testCheckPEMBundleTypes(PreambleCertificate, PreambleCertificate, PreamblePrivateKeyRSA),
+				),
+			},
+			{
+				Config: `
+					data "tls_pem_bundle" "test" {
+						private_key_pem = file("testdata/tls_certs/private.pem")
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+						order           = "key_first"
+					}
+				`,
+				Check: resource.TestCheckResourceAttrWith("data.tls_pem_bundle.test", "bundle_pem",
+					testCheckPEMBundleTypes(PreamblePrivateKeyRSA, PreambleCertificate),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourcePEMBundle_InvalidComponents(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "tls_pem_bundle" "test" {
+						private_key_pem = "not a private key"
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+					}
+				`,
+				ExpectError: regexp.MustCompile("invalid private_key_pem: failed to decode PEM block"),
+			},
+			{
+				Config: `
+					data "tls_pem_bundle" "test" {
+						private_key_pem = file("testdata/tls_certs/private.pem")
+						cert_pem        = file("testdata/tls_certs/public.pem")
+					}
+				`,
+				ExpectError: regexp.MustCompile("invalid cert_pem: expected a single certificate, got 2"),
+			},
+			{
+				Config: `
+					data "tls_pem_bundle" "test" {
+						private_key_pem = file("testdata/tls_certs/private.pem")
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+						chain_pem       = file("testdata/tls_certs/private.pem")
+					}
+				`,
+				ExpectError: regexp.MustCompile("invalid chain_pem: invalid PEM type"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_pem_bundle" "test" {
+						private_key_pem = <<EOT
+%s
+EOT
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile("private_key_pem does not match the public key of the certificate in cert_pem"),
+			},
+			{
+				Config: `
+					data "tls_pem_bundle" "test" {
+						private_key_pem = file("testdata/tls_certs/private.pem")
+						cert_pem        = file("testdata/tls_certs/certificate.pem")
+						order           = "chain_first"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected order to be one of \[cert_first key_first\], got chain_first`),
+			},
+		},
+	})
+}
+
+func testCheckPEMBundleTypes(expected ...PEMPreamble) resource.CheckResourceAttrWithFunc {
+	return func(value string) error {
+		var actual []PEMPreamble
+		rest := []byte(value)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			actual = append(actual, PEMPreamble(block.Type))
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect PEM blocks in bundle: expected %v, got %v", expected, actual)
+		}
+		return nil
+	}
+}
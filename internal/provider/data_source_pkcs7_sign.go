@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"go.mozilla.org/pkcs7"
+)
+
+func dataSourcePKCS7Sign() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourcePKCS7Sign,
+
+		Description: "Sign some content, producing a [PKCS #7 (RFC 2315)](https://datatracker.ietf.org/doc/html/rfc2315) / " +
+			"[CMS (RFC 5652)](https://datatracker.ietf.org/doc/html/rfc5652) `SignedData` structure, " +
+			"for example to sign firmware images or documents.\n\n" +
+			"The content is digested with `SHA256`, and only `RSA` and `ECDSA` keys are supported. " +
+			"As for any data source, the signature is produced every time the data source is read (i.e. at every plan): " +
+			"if `include_signing_time` is `true` (the default), or if the key is an `ECDSA` key, `pkcs7_base64` and `id` change " +
+			"every time, so anything depending on them shows a change at every plan: consider ignoring those changes " +
+			"with a [`lifecycle`](https://www.terraform.io/language/meta-arguments/lifecycle) `ignore_changes` argument.",
+
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"content", "content_base64"},
+				Description:  "The content to sign. Exactly one of `content` and `content_base64` must be set.",
+			},
+
+			"content_base64": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"content", "content_base64"},
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description: "The content to sign, encoded in base64: use this for binary content " +
+					"(e.g. via [`filebase64()`](https://www.terraform.io/language/functions/filebase64)). " +
+					"Exactly one of `content` and `content_base64` must be set.",
+			},
+
+			"certificate_pem": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Certificate of the signer, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"private_key_pem": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+				Description: "Private key of the signer, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+					"It must match the public key of `certificate_pem`.",
+			},
+
+			"certificate_chain_pem": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Certificates of the issuers of `certificate_pem`, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+					"starting with the one that issued `certificate_pem`. They are included in the signed data, " +
+					"to help the verifiers build a chain up to the certificate they trust.",
+			},
+
+			"detached": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "When `true`, the content is not included in the signed data, " +
+					"and has to be provided separately to verify the signature (default: `false`).",
+			},
+
+			"include_signing_time": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				Description: "When `true`, the signing time is included in the signed attributes. " +
+					"When `false`, the content is signed directly without any signed attributes " +
+					"(i.e. neither the content type nor the message digest attributes), " +
+					"like OpenSSL does with `-noattr` (default: `true`).",
+			},
+
+			"pkcs7_base64": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The `SignedData`, wrapped in a `ContentInfo` and encoded in DER format, " +
+					"then encoded in base64: this is the content of a `.p7s` file (if `detached` is `true`) " +
+					"or of a `.p7m` file (otherwise).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of `pkcs7_base64`.",
+			},
+		},
+	}
+}
+
+func readDataSourcePKCS7Sign(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	content := []byte(d.Get("content").(string))
+	if contentBase64, ok := d.GetOk("content_base64"); ok {
+		var err error
+		content, err = base64.StdEncoding.DecodeString(contentBase64.(string))
+		if err != nil {
+			return diag.Errorf("unable to decode content_base64: %v", err)
+		}
+	}
+
+	certs, err := parseCertificatesPEM([]byte(d.Get("certificate_pem").(string)))
+	if err != nil {
+		return diag.Errorf("unable to parse certificate_pem: %v", err)
+	}
+	cert := certs[0]
+
+	prvKey, algorithm, err := parsePrivateKeyPEM([]byte(d.Get("private_key_pem").(string)), m.(*providerConfig).strictPEM)
+	if err != nil {
+		return diag.Errorf("unable to parse private_key_pem: %v", err)
+	}
+	switch prvKey.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+	default:
+		return diag.Errorf("unsupported private key algorithm %s: only RSA and ECDSA keys can be used to sign PKCS #7 data", algorithm)
+	}
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return diag.Errorf("failed to get public key from private_key_pem: %v", err)
+	}
+	if !publicKeysEqual(pubKey, cert.PublicKey) {
+		return diag.Errorf("the public key of private_key_pem doesn't match the public key of certificate_pem")
+	}
+
+	signedData, err := pkcs7.NewSignedData(content)
+	if err != nil {
+		return diag.Errorf("failed to initialize signed data: %v", err)
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+	// GOTCHA: `go.mozilla.org/pkcs7` always adds the signing time to the signed attributes:
+	// the only way to leave it out is to not have signed attributes at all
+	if d.Get("include_signing_time").(bool) {
+		err = signedData.AddSigner(cert, prvKey, pkcs7.SignerInfoConfig{})
+	} else {
+		err = signedData.SignWithoutAttr(cert, prvKey, pkcs7.SignerInfoConfig{})
+	}
+	if err != nil {
+		return diag.Errorf("failed to sign content: %v", err)
+	}
+
+	if chainPEM := d.Get("certificate_chain_pem").(string); chainPEM != "" {
+		chain, err := parseCertificatesPEM([]byte(chainPEM))
+		if err != nil {
+			return diag.Errorf("unable to parse certificate_chain_pem: %v", err)
+		}
+		if err := cert.CheckSignatureFrom(chain[0]); err != nil {
+			return diag.Errorf("certificate_pem is not issued by the first certificate of certificate_chain_pem: %v", err)
+		}
+		for _, c := range chain {
+			signedData.AddCertificate(c)
+		}
+	}
+
+	if d.Get("detached").(bool) {
+		signedData.Detach()
+	}
+
+	p7DER, err := signedData.Finish()
+	if err != nil {
+		return diag.Errorf("failed to marshal signed data: %v", err)
+	}
+	p7Base64 := base64.StdEncoding.EncodeToString(p7DER)
+
+	if err := d.Set("pkcs7_base64", p7Base64); err != nil {
+		return diag.Errorf("error setting value on key 'pkcs7_base64': %s", err)
+	}
+
+	d.SetId(hashForState(p7Base64))
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"go.mozilla.org/pkcs7"
+)
+
+const configDataSourcePKCS7Sign = `
+resource "tls_locally_signed_cert" "signer" {
+	cert_request_pem      = <<EOF
+%s
+EOF
+	ca_private_key_pem    = <<EOF
+%s
+EOF
+	ca_cert_pem           = <<EOF
+%s
+EOF
+	validity_period_hours = 1
+	allowed_uses          = ["digital_signature"]
+}
+
+data "tls_pkcs7_sign" "test" {
+	certificate_pem = tls_locally_signed_cert.signer.cert_pem
+	private_key_pem = <<EOF
+%s
+EOF
+	%s
+}
+`
+
+func TestAccDataSourcePKCS7Sign(t *testing.T) {
+	config := func(arguments string) string {
+		return fmt.Sprintf(configDataSourcePKCS7Sign, testCertRequest, testCAPrivateKey, testCACert, testPrivateKeyPEM, arguments)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`content = "hello world"`),
+				Check:  testCheckPKCS7Signature("data.tls_pkcs7_sign.test", "hello world", false, true, 1),
+			},
+			{
+				Config: config(`
+					content_base64 = base64encode("hello world")
+					detached       = true
+				`),
+				Check: testCheckPKCS7Signature("data.tls_pkcs7_sign.test", "hello world", true, true, 1),
+			},
+			{
+				Config: config(fmt.Sprintf(`
+					content               = "hello world"
+					include_signing_time  = false
+					certificate_chain_pem = <<EOF
+%s
+EOF
+				`, testCACert)),
+				Check: testCheckPKCS7Signature("data.tls_pkcs7_sign.test", "hello world", false, false, 2),
+			},
+			{
+				Config:      config(`certificate_chain_pem = tls_locally_signed_cert.signer.cert_pem`),
+				ExpectError: regexp.MustCompile(`"content": one of ` + "`content,content_base64`" + ` must be specified`),
+			},
+			{
+				Config: config(`
+					content               = "hello world"
+					certificate_chain_pem = tls_locally_signed_cert.signer.cert_pem
+				`),
+				ExpectError: regexp.MustCompile("certificate_pem is not issued by the first certificate of certificate_chain_pem"),
+			},
+		},
+	})
+}
+
+func TestAccDataSourcePKCS7Sign_ECDSA(t *testing.T) {
+	config := `
+		resource "tls_private_key" "signer" {
+			algorithm   = "ECDSA"
+			ecdsa_curve = "P256"
+		}
+
+		resource "tls_self_signed_cert" "signer" {
+			private_key_pem = tls_private_key.signer.private_key_pem
+			subject {
+				common_name = "signer"
+			}
+			validity_period_hours = 1
+			allowed_uses          = ["digital_signature"]
+		}
+
+		data "tls_pkcs7_sign" "test" {
+			content         = "hello world"
+			certificate_pem = tls_self_signed_cert.signer.cert_pem
+			private_key_pem = tls_private_key.signer.private_key_pem
+			%s
+		}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, ""),
+				Check:  testCheckPKCS7Signature("data.tls_pkcs7_sign.test", "hello world", false, true, 1),
+			},
+			{
+				Config: fmt.Sprintf(config, "include_signing_time = false"),
+				Check:  testCheckPKCS7Signature("data.tls_pkcs7_sign.test", "hello world", false, false, 1),
+			},
+		},
+	})
+}
+
+func TestAccDataSourcePKCS7Sign_MismatchedPrivateKey(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "tls_pkcs7_sign" "test" {
+						content         = "hello world"
+						certificate_pem = <<EOF
+%s
+EOF
+						private_key_pem = <<EOF
+%s
+EOF
+					}
+				`, testCACert, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile("the public key of private_key_pem doesn't match the public key of certificate_pem"),
+			},
+		},
+	})
+}
+
+// testCheckPKCS7Signature checks that `pkcs7_base64` is a valid signature of the expected content,
+// and that it contains the expected number of certificates, and the signing time if expected.
+// As the signing time can only be left out together with all the signed attributes,
+// the content type and message digest attributes are expected exactly when the signing time is.
+func testCheckPKCS7Signature(name, expectedContent string, detached, expectedSigningTime bool, expectedCerts int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		p7DER, err := base64.StdEncoding.DecodeString(rs.Primary.Attributes["pkcs7_base64"])
+		if err != nil {
+			return fmt.Errorf("failed to decode pkcs7_base64: %w", err)
+		}
+		p7, err := pkcs7.Parse(p7DER)
+		if err != nil {
+			return fmt.Errorf("failed to parse pkcs7_base64: %w", err)
+		}
+
+		if detached {
+			if len(p7.Content) != 0 {
+				return fmt.Errorf("expected detached signature, but content is included")
+			}
+			p7.Content = []byte(expectedContent)
+		} else if string(p7.Content) != expectedContent {
+			return fmt.Errorf("incorrect content: expected %q, got %q", expectedContent, p7.Content)
+		}
+
+		if err := p7.Verify(); err != nil {
+			return fmt.Errorf("failed to verify signature: %w", err)
+		}
+
+		var signingTime time.Time
+		err = p7.UnmarshalSignedAttribute(pkcs7.OIDAttributeSigningTime, &signingTime)
+		if expectedSigningTime && err != nil {
+			return fmt.Errorf("expected signing time attribute: %w", err)
+		}
+		if !expectedSigningTime && err == nil {
+			return fmt.Errorf("unexpected signing time attribute: %s", signingTime)
+		}
+
+		var contentType asn1.ObjectIdentifier
+		err = p7.UnmarshalSignedAttribute(pkcs7.OIDAttributeContentType, &contentType)
+		if expectedSigningTime && err != nil {
+			return fmt.Errorf("expected content type attribute: %w", err)
+		}
+		if !expectedSigningTime && err == nil {
+			return fmt.Errorf("unexpected content type attribute: %s", contentType)
+		}
+
+		var messageDigest []byte
+		err = p7.UnmarshalSignedAttribute(pkcs7.OIDAttributeMessageDigest, &messageDigest)
+		if expectedSigningTime && err != nil {
+			return fmt.Errorf("expected message digest attribute: %w", err)
+		}
+		if !expectedSigningTime && err == nil {
+			return fmt.Errorf("unexpected message digest attribute: %x", messageDigest)
+		}
+
+		if len(p7.Certificates) != expectedCerts {
+			return fmt.Errorf("expected %d certificate(s), got %d", expectedCerts, len(p7.Certificates))
+		}
+
+		return nil
+	}
+}
@@ -38,6 +38,8 @@ func dataSourcePublicKey() *schema.Resource {
 					"This is _mutually exclusive_ with `private_key_pem`.",
 			},
 
+			"openssh_comment": opensshCommentSchema(false, ""),
+
 			"algorithm": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -56,6 +58,13 @@ func dataSourcePublicKey() *schema.Resource {
 					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
 			},
 
+			"public_key_der_base64": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The public key as a `SubjectPublicKeyInfo` structure in DER format, encoded in base64: " +
+					"this is the same data as `public_key_pem`, for consumers that can't parse PEM.",
+			},
+
 			"public_key_openssh": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -86,6 +95,16 @@ func dataSourcePublicKey() *schema.Resource {
 					"`public_key_openssh` and [ECDSA P224 limitations](../../docs#limitations).",
 			},
 
+			"public_key_fingerprint_sha256_hex": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The fingerprint of the public key data in OpenSSH SHA256 hash format, " +
+					"encoded as colon-separated hexadecimal, e.g. `aa:bb:cc:...`, " +
+					"instead of the base64 encoding of `public_key_fingerprint_sha256`. " +
+					"Only available if the selected private key format is compatible, as per the rules for " +
+					"`public_key_openssh` and [ECDSA P224 limitations](../../docs#limitations).",
+			},
+
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -96,7 +115,7 @@ func dataSourcePublicKey() *schema.Resource {
 	}
 }
 
-func readDataSourcePublicKey(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+func readDataSourcePublicKey(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var prvKey crypto.PrivateKey
 	var algorithm Algorithm
 	var err error
@@ -104,9 +123,9 @@ func readDataSourcePublicKey(_ context.Context, d *schema.ResourceData, _ interf
 	// Given the use of `ExactlyOneOf` in the Schema, we are guaranteed
 	// that either `private_key_pem` or `private_key_openssh` will be set.
 	if prvKeyArg, ok := d.GetOk("private_key_pem"); ok {
-		prvKey, algorithm, err = parsePrivateKeyPEM([]byte(prvKeyArg.(string)))
+		prvKey, algorithm, err = parsePrivateKeyPEM([]byte(prvKeyArg.(string)), m.(*providerConfig).strictPEM)
 	} else if prvKeyArg, ok := d.GetOk("private_key_openssh"); ok {
-		prvKey, algorithm, err = parsePrivateKeyOpenSSHPEM([]byte(prvKeyArg.(string)))
+		prvKey, algorithm, err = parsePrivateKeyOpenSSHPEM([]byte(prvKeyArg.(string)), nil)
 	}
 	if err != nil {
 		return diag.FromErr(err)
@@ -34,9 +34,11 @@ func TestAccPublicKey_dataSource_PEM(t *testing.T) {
 				Config: fmt.Sprintf(configDataSourcePublicKeyViaPEM, testPrivateKeyPEM),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_pem", strings.TrimSpace(testPublicKeyPEM)+"\n"),
+					testCheckAttrDERBase64Of("data.tls_public_key.test", "public_key_der_base64", "public_key_pem"),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_openssh", strings.TrimSpace(testPublicKeyOpenSSH)+"\n"),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_md5", strings.TrimSpace(testPublicKeyOpenSSHFingerprintMD5)),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_sha256", strings.TrimSpace(testPublicKeyOpenSSHFingerprintSHA256)),
+					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_sha256_hex", strings.TrimSpace(testPublicKeyOpenSSHFingerprintSHA256Hex)),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", "RSA"),
 				),
 			},
@@ -76,6 +78,24 @@ func TestAccPublicKey_dataSource_PEM(t *testing.T) {
 				Config:      fmt.Sprintf(configDataSourcePublicKeyViaPEM, "corrupt"),
 				ExpectError: regexp.MustCompile(`failed to decode PEM block: decoded bytes \d, undecoded \d`),
 			},
+			{
+				// The PEM preamble must match the encoding of the key
+				Config:      fmt.Sprintf(configDataSourcePublicKeyViaPEM, strings.ReplaceAll(testPrivateKeyPEM, "RSA PRIVATE KEY", "PRIVATE KEY")),
+				ExpectError: regexp.MustCompile(`PEM preamble 'PRIVATE KEY' doesn't match the encoding of the private key,\s+that is in pkcs1 format: expected PEM preamble 'RSA PRIVATE KEY'`),
+			},
+			{
+				Config:      fmt.Sprintf(configDataSourcePublicKeyViaPEM, strings.ReplaceAll(testPrivateKeyPEM, "RSA PRIVATE KEY", "EC PRIVATE KEY")),
+				ExpectError: regexp.MustCompile(`PEM preamble 'EC PRIVATE KEY' doesn't match the encoding of the private key,\s+that is in pkcs1 format: expected PEM preamble 'RSA PRIVATE KEY'`),
+			},
+			{
+				// Encrypted private keys are rejected, explaining how they are encrypted
+				Config:      fmt.Sprintf(configDataSourcePublicKeyViaPEM, testPrivateKeyPEMEncryptedLegacy),
+				ExpectError: regexp.MustCompile(`private key is encrypted with legacy PEM encryption\s+\('Proc-Type: 4,ENCRYPTED', AES-256-CBC\): a passphrase is required to decrypt it`),
+			},
+			{
+				Config:      fmt.Sprintf(configDataSourcePublicKeyViaPEM, testPrivateKeyPEMEncryptedPKCS8),
+				ExpectError: regexp.MustCompile(`private key is encrypted with PKCS #8 encryption\s+\(PBES2 \(PBKDF2, AES-256-CBC\)\): a passphrase is required to decrypt it`),
+			},
 		},
 	})
 }
@@ -91,6 +111,7 @@ func TestAccPublicKey_dataSource_OpenSSHPEM(t *testing.T) {
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_openssh", strings.TrimSpace(testPublicKeyOpenSSH)+"\n"),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_md5", strings.TrimSpace(testPublicKeyOpenSSHFingerprintMD5)),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_sha256", strings.TrimSpace(testPublicKeyOpenSSHFingerprintSHA256)),
+					resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_fingerprint_sha256_hex", strings.TrimSpace(testPublicKeyOpenSSHFingerprintSHA256Hex)),
 					resource.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", "RSA"),
 				),
 			},
@@ -125,6 +146,17 @@ func TestAccPublicKey_dataSource_OpenSSHPEM(t *testing.T) {
 					resource.TestCheckResourceAttr("data.tls_public_key.ed25519PubKey", "algorithm", "ED25519"),
 				),
 			},
+			{
+				Config: fmt.Sprintf(`
+					data "tls_public_key" "test" {
+						private_key_openssh = <<EOF
+%s
+EOF
+						openssh_comment     = "user@host"
+					}
+				`, testPrivateKeyOpenSSHPEM),
+				Check: resource.TestCheckResourceAttr("data.tls_public_key.test", "public_key_openssh", strings.TrimSpace(testPublicKeyOpenSSH)+" user@host\n"),
+			},
 			{
 				Config:      fmt.Sprintf(configDataSourcePublicKeyViaOpenSSHPEM, "corrupt"),
 				ExpectError: regexp.MustCompile("ssh: no key found"),
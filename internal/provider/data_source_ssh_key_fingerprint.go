@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+func dataSourceSSHKeyFingerprint() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceSSHKeyFingerprint,
+
+		Description: "Get the fingerprints of a public key in OpenSSH 'Authorized Keys' format, " +
+			"in the same forms that `ssh-keygen -l` and Git hosting platforms (e.g. GitHub, GitLab) display: " +
+			"the `SHA256:...` base64 form, and the legacy MD5 `xx:xx:...` hexadecimal form.\n\n" +
+			"Unlike the fingerprints of `tls_private_key` and `tls_public_key`, this doesn't require the private key, " +
+			"and it supports any key type known to OpenSSH, including certificates and security keys (e.g. `sk-ssh-ed25519@openssh.com`).",
+
+		Schema: map[string]*schema.Schema{
+			"public_key_openssh": {
+				Type:     schema.TypeString,
+				Required: true,
+				Description: "The public key, in " +
+					"[OpenSSH 'Authorized Keys'](https://man.openbsd.org/sshd#AUTHORIZED_KEYS_FILE_FORMAT) format " +
+					"(e.g. `ssh-ed25519 AAAA... user@host`). The comment, if any, is ignored.",
+			},
+
+			"key_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The type of the public key, as named by OpenSSH (e.g. `ssh-rsa`, `ssh-ed25519`, " +
+					"`ecdsa-sha2-nistp256`).",
+			},
+
+			"fingerprint_md5": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The fingerprint of the public key data in OpenSSH MD5 hash format, e.g. `aa:bb:cc:...`, " +
+					"as displayed by `ssh-keygen -l -E md5` (without the `MD5:` prefix).",
+			},
+
+			"fingerprint_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The fingerprint of the public key data in OpenSSH SHA256 hash format, e.g. `SHA256:...`.",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA256 checksum of the public key data.",
+			},
+		},
+	}
+}
+
+func readDataSourceSSHKeyFingerprint(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	sshPubKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(d.Get("public_key_openssh").(string)))
+	if err != nil {
+		return diag.Errorf("failed to parse public_key_openssh: %s", err)
+	}
+
+	if err := d.Set("key_type", sshPubKey.Type()); err != nil {
+		return diag.Errorf("error setting value on key 'key_type': %s", err)
+	}
+
+	if err := d.Set("fingerprint_md5", ssh.FingerprintLegacyMD5(sshPubKey)); err != nil {
+		return diag.Errorf("error setting value on key 'fingerprint_md5': %s", err)
+	}
+
+	if err := d.Set("fingerprint_sha256", ssh.FingerprintSHA256(sshPubKey)); err != nil {
+		return diag.Errorf("error setting value on key 'fingerprint_sha256': %s", err)
+	}
+
+	d.SetId(contentHash(sshPubKey.Marshal()))
+
+	return nil
+}
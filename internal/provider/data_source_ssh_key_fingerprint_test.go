@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSSHKeyFingerprint(t *testing.T) {
+	config := func(pubKeySSH string) string {
+		return fmt.Sprintf(`
+			data "tls_ssh_key_fingerprint" "test" {
+				public_key_openssh = %q
+			}
+		`, pubKeySSH)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(strings.TrimSpace(testPublicKeyOpenSSH) + " user@example.com\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_ssh_key_fingerprint.test", "key_type", "ssh-rsa"),
+					resource.TestCheckResourceAttr("data.tls_ssh_key_fingerprint.test", "fingerprint_md5", strings.TrimSpace(testPublicKeyOpenSSHFingerprintMD5)),
+					resource.TestCheckResourceAttr("data.tls_ssh_key_fingerprint.test", "fingerprint_sha256", strings.TrimSpace(testPublicKeyOpenSSHFingerprintSHA256)),
+					resource.TestMatchResourceAttr("data.tls_ssh_key_fingerprint.test", "id", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					data "tls_ssh_key_fingerprint" "test" {
+						public_key_openssh = tls_private_key.test.public_key_openssh
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_ssh_key_fingerprint.test", "key_type", "ssh-ed25519"),
+					resource.TestCheckResourceAttrPair(
+						"data.tls_ssh_key_fingerprint.test", "fingerprint_md5",
+						"tls_private_key.test", "public_key_fingerprint_md5",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.tls_ssh_key_fingerprint.test", "fingerprint_sha256",
+						"tls_private_key.test", "public_key_fingerprint_sha256",
+					),
+				),
+			},
+			{
+				Config:      config(testPublicKeyPEM),
+				ExpectError: regexp.MustCompile(`failed to parse public_key_openssh`),
+			},
+		},
+	})
+}
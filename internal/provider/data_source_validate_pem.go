@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceValidatePEM() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readDataSourceValidatePEM,
+
+		Description: "Validate that some content is a well-formed PEM document of the expected type.\n\n" +
+			"Use this data source to check user-supplied [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
+			"content before feeding it into other resources: " +
+			"differently from those, an invalid content doesn't cause an error, but is reported via `valid` and `diagnostics`.",
+
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The content to validate, expected to be in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedPEMContentTypesStr(), false)),
+				Description: "The expected type of `content`. " +
+					"Accepted values are `certificate` (one or more certificates), `private_key` " +
+					"(`RSA`, `ECDSA` or `ED25519` private key), `public_key` and `csr` (certificate signing request).",
+			},
+
+			"strict_rfc7468": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "When `true`, `content` must also follow the strict textual encoding of " +
+					"[RFC 7468](https://datatracker.ietf.org/doc/html/rfc7468#section-3), in its canonical form: " +
+					"no explanatory text around the PEM block(s), no headers, matching labels, " +
+					"base64 lines of 64 characters (except the last one), `\\n` line endings and a trailing newline. " +
+					"All the PEM values produced by this provider are encoded this way (default: `false`).",
+			},
+
+			"valid": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "`true` if `content` is a valid PEM document of the given `type`, `false` otherwise.",
+			},
+
+			"diagnostics": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "A description of the outcome of the validation: " +
+					"the reason `content` is not valid, or a summary of what was found in it.",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this data source: " +
+					"hexadecimal representation of the SHA1 checksum of `type` and `content`.",
+			},
+		},
+	}
+}
+
+func readDataSourceValidatePEM(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	content := d.Get("content").(string)
+	contentType := PEMContentType(d.Get("type").(string))
+
+	summary, err := validatePEM([]byte(content), contentType)
+	if err == nil && d.Get("strict_rfc7468").(bool) {
+		err = checkStrictRFC7468([]byte(content))
+	}
+	valid := err == nil
+	diagnostics := summary
+	if !valid {
+		diagnostics = fmt.Sprintf("invalid %s: %s", contentType, err)
+	}
+
+	d.SetId(hashForState(contentType.String() + content))
+
+	if err := d.Set("valid", valid); err != nil {
+		return diag.Errorf("error setting value on key 'valid': %s", err)
+	}
+
+	if err := d.Set("diagnostics", diagnostics); err != nil {
+		return diag.Errorf("error setting value on key 'diagnostics': %s", err)
+	}
+
+	return nil
+}
+
+// validatePEM checks that the given content is a PEM document of the given PEMContentType,
+// and that it can be parsed. It returns a summary of what was found, or an error describing the issue.
+func validatePEM(content []byte, contentType PEMContentType) (string, error) {
+	block, rest := pem.Decode(content)
+	if block == nil {
+		return "", fmt.Errorf("failed to decode PEM block: no PEM data found")
+	}
+
+	preamble, err := PEMBlockToPEMPreamble(block)
+	if err != nil {
+		return "", err
+	}
+	if !pemContentTypeAccepts(contentType, preamble) {
+		return "", fmt.Errorf("unexpected PEM preamble '%s': expected one of %v", preamble, pemContentTypePreambles[contentType])
+	}
+
+	switch contentType {
+	case PEMContentTypeCertificate:
+		certs, err := parseCertificatesPEM(content)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("found %d certificate(s), the first with subject '%s' and issuer '%s'",
+			len(certs), certs[0].Subject, certs[0].Issuer), nil
+	case PEMContentTypePrivateKey:
+		_, algorithm, err := parsePrivateKeyPEM(content, false)
+		if err != nil {
+			return "", err
+		}
+		if err := checkTrailingData(rest); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("found %s private key, with PEM preamble '%s'", algorithm, preamble), nil
+	case PEMContentTypePublicKey:
+		pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse public key: %w", err)
+		}
+		algorithm, err := publicKeyToAlgorithm(pubKey)
+		if err != nil {
+			return "", err
+		}
+		if err := checkTrailingData(rest); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("found %s public key", algorithm), nil
+	case PEMContentTypeCSR:
+		certReq, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate request: %w", err)
+		}
+		if err := certReq.CheckSignature(); err != nil {
+			return "", fmt.Errorf("invalid certificate request signature: %w", err)
+		}
+		if err := checkTrailingData(rest); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("found certificate request with subject '%s'", certReq.Subject), nil
+	default:
+		// NOTE: This should never happen, given we validate this at the schema level
+		return "", fmt.Errorf("unsupported type: %s", contentType)
+	}
+}
+
+// pemContentTypeAccepts returns true if the given PEMPreamble is accepted for the given PEMContentType.
+func pemContentTypeAccepts(contentType PEMContentType, preamble PEMPreamble) bool {
+	for _, p := range pemContentTypePreambles[contentType] {
+		if p == preamble {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTrailingData returns an error if there is anything but whitespaces after a single PEM block.
+func checkTrailingData(rest []byte) error {
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return fmt.Errorf("unexpected data after the PEM block: %d bytes", len(bytes.TrimSpace(rest)))
+	}
+	return nil
+}
+
+// checkStrictRFC7468 returns an error if the given content is not made only of PEM blocks
+// in the strict textual encoding of RFC 7468 (see https://datatracker.ietf.org/doc/html/rfc7468#section-3),
+// in the canonical form produced by pem.Encode: base64 lines of 64 characters and `\n` line endings.
+func checkStrictRFC7468(content []byte) error {
+	if len(content) == 0 {
+		return fmt.Errorf("no PEM data found")
+	}
+
+	for rest := content; len(rest) > 0; {
+		if len(bytes.TrimSpace(rest)) == 0 {
+			return fmt.Errorf("unexpected whitespaces after the last PEM block")
+		}
+		if !bytes.HasPrefix(rest, []byte("-----BEGIN ")) {
+			return fmt.Errorf("unexpected data outside of the PEM block(s): RFC 7468 doesn't allow explanatory text")
+		}
+
+		block, _ := pem.Decode(rest)
+		if block == nil {
+			return fmt.Errorf("failed to decode PEM block")
+		}
+		if len(block.Headers) > 0 {
+			return fmt.Errorf("PEM block '%s' has headers: RFC 7468 doesn't allow them", block.Type)
+		}
+		if !isRFC7468Label(block.Type) {
+			return fmt.Errorf("PEM block label '%s' is not valid as per RFC 7468", block.Type)
+		}
+
+		// NOTE: pem.Decode is lenient about line lengths and whitespaces,
+		// so the block is compared with its canonical encoding instead
+		encoded := pem.EncodeToMemory(block)
+		if !bytes.HasPrefix(rest, encoded) {
+			return fmt.Errorf("PEM block '%s' is not in canonical form: expected base64 lines of 64 characters, "+
+				"'\\n' line endings and a trailing newline", block.Type)
+		}
+		rest = rest[len(encoded):]
+	}
+
+	return nil
+}
+
+// isRFC7468Label returns true if the given label matches the `label` rule of RFC 7468:
+// printable characters, except hyphen-minus, optionally separated by a single hyphen-minus or space.
+func isRFC7468Label(label string) bool {
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c == '-' || c == ' ':
+			if i == 0 || i == len(label)-1 || label[i-1] == '-' || label[i-1] == ' ' {
+				return false
+			}
+		case c < 0x21 || c > 0x7e:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const configDataSourceValidatePEM = `
+data "tls_validate_pem" "test" {
+	content = <<EOF
+%s
+EOF
+	type = "%s"
+}
+`
+
+func TestAccDataSourceValidatePEM_Valid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testCACert, "certificate"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "true"),
+					resource.TestMatchResourceAttr("data.tls_validate_pem.test", "diagnostics", regexp.MustCompile(`^found 1 certificate\(s\)`)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testPrivateKeyPEM, "private_key"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "true"),
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "diagnostics", "found RSA private key, with PEM preamble 'RSA PRIVATE KEY'"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testPublicKeyPEM, "public_key"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "true"),
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "diagnostics", "found RSA public key"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testCertRequest, "csr"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "true"),
+					resource.TestMatchResourceAttr("data.tls_validate_pem.test", "diagnostics", regexp.MustCompile(`^found certificate request with subject`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceValidatePEM_Invalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, "not a PEM", "certificate"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "false"),
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "diagnostics", "invalid certificate: failed to decode PEM block: no PEM data found"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testPrivateKeyPEM, "certificate"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "false"),
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "diagnostics", "invalid certificate: unexpected PEM preamble 'RSA PRIVATE KEY': expected one of [CERTIFICATE]"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configDataSourceValidatePEM, testPrivateKeyOpenSSHPEM, "private_key"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "false"),
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "diagnostics", "invalid private_key: unsupported PEM preamble/type: OPENSSH PRIVATE KEY"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(configDataSourceValidatePEM, testCACert, "x509"),
+				ExpectError: regexp.MustCompile(`expected type to be one of \[certificate private_key public_key csr\], got x509`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceValidatePEM_StrictRFC7468(t *testing.T) {
+	config := func(content string) string {
+		return fmt.Sprintf(`
+			data "tls_validate_pem" "test" {
+				content        = %q
+				type           = "certificate"
+				strict_rfc7468 = true
+			}
+		`, content)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(strings.TrimLeft(testCACert, "\n") + "\n"),
+				Check:  resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "true"),
+			},
+			{
+				Config: config("Subject: CN=root\n" + strings.TrimLeft(testCACert, "\n") + "\n"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tls_validate_pem.test", "valid", "false"),
+					resource.TestMatchResourceAttr("data.tls_validate_pem.test", "diagnostics", regexp.MustCompile(`RFC 7468 doesn't allow explanatory text`)),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckStrictRFC7468(t *testing.T) {
+	block := &pem.Block{Type: PreambleCertificate.String(), Bytes: make([]byte, 100)}
+	canonical := string(pem.EncodeToMemory(block))
+	lines := strings.Split(strings.TrimSuffix(canonical, "\n"), "\n")
+
+	testCases := map[string]struct {
+		content     string
+		expectedErr string
+	}{
+		"canonical": {
+			content: canonical,
+		},
+		"canonical bundle": {
+			content: canonical + canonical,
+		},
+		"empty": {
+			content:     "",
+			expectedErr: "no PEM data found",
+		},
+		"explanatory text": {
+			content:     "Subject: CN=example.com\n" + canonical,
+			expectedErr: "RFC 7468 doesn't allow explanatory text",
+		},
+		"text between blocks": {
+			content:     canonical + "\n" + canonical,
+			expectedErr: "RFC 7468 doesn't allow explanatory text",
+		},
+		"trailing whitespaces": {
+			content:     canonical + "\n",
+			expectedErr: "unexpected whitespaces after the last PEM block",
+		},
+		"no trailing newline": {
+			content:     strings.TrimSuffix(canonical, "\n"),
+			expectedErr: "is not in canonical form",
+		},
+		"CRLF line endings": {
+			content:     strings.ReplaceAll(canonical, "\n", "\r\n"),
+			expectedErr: "is not in canonical form",
+		},
+		"short base64 lines": {
+			content:     lines[0] + "\n" + lines[1][:32] + "\n" + lines[1][32:] + "\n" + strings.Join(lines[2:], "\n") + "\n",
+			expectedErr: "is not in canonical form",
+		},
+		"headers": {
+			content:     string(pem.EncodeToMemory(&pem.Block{Type: block.Type, Headers: map[string]string{"Proc-Type": "4,ENCRYPTED"}, Bytes: block.Bytes})),
+			expectedErr: "has headers",
+		},
+		"invalid label": {
+			content:     string(pem.EncodeToMemory(&pem.Block{Type: "X509  CRL", Bytes: block.Bytes})),
+			expectedErr: "label 'X509  CRL' is not valid",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkStrictRFC7468([]byte(tc.content))
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
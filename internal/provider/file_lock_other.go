@@ -0,0 +1,20 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// lockFile fails on platforms where the provider can't lock files:
+// without a lock, concurrent processes could hand out the same serial number twice.
+func lockFile(_ *os.File) error {
+	return fmt.Errorf("file locking is not supported on %s", runtime.GOOS)
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(_ *os.File) error {
+	return nil
+}
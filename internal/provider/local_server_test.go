@@ -1,9 +1,18 @@
 package provider
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
 	"log"
+	"math/big"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/elazarl/goproxy"
 	"github.com/elazarl/goproxy/ext/auth"
@@ -30,6 +39,153 @@ func newHTTPServer() (*LocalServerTest, error) {
 	}, nil
 }
 
+// newHTTPServerRequiringClientCert creates an HTTP server that listens on a random port,
+// and requires clients to present a certificate when serving TLS.
+func newHTTPServerRequiringClientCert() (*LocalServerTest, error) {
+	lst, err := newHTTPServer()
+	if err != nil {
+		return nil, err
+	}
+
+	lst.server.TLSConfig = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+
+	return lst, nil
+}
+
+// newHTTPServerRequiringClientCertChain creates an HTTP server that listens on a random port,
+// and requires clients to present a chain of (exactly) the given number of certificates when serving TLS.
+func newHTTPServerRequiringClientCertChain(chainLength int) (*LocalServerTest, error) {
+	lst, err := newHTTPServerRequiringClientCert()
+	if err != nil {
+		return nil, err
+	}
+
+	lst.server.TLSConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) != chainLength {
+			return fmt.Errorf("expected client certificate chain of length %d, got %d", chainLength, len(rawCerts))
+		}
+		return nil
+	}
+
+	return lst, nil
+}
+
+// newHTTPServerWithALPNCertificateChain creates an HTTP server that listens on a random port,
+// and that presents the full certificate chain when serving TLS only if the client offers
+// the given ALPN protocol: otherwise, only the leaf certificate is presented.
+func newHTTPServerWithALPNCertificateChain(protocol string) (*LocalServerTest, error) {
+	lst, err := newHTTPServer()
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := tls.LoadX509KeyPair("testdata/tls_certs/public.pem", "testdata/tls_certs/private.pem")
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := tls.LoadX509KeyPair("testdata/tls_certs/certificate.pem", "testdata/tls_certs/private.pem")
+	if err != nil {
+		return nil, err
+	}
+
+	lst.server.TLSConfig = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			for _, p := range hello.SupportedProtos {
+				if p == protocol {
+					return &tls.Config{Certificates: []tls.Certificate{chain}, NextProtos: []string{protocol}}, nil
+				}
+			}
+			return &tls.Config{Certificates: []tls.Certificate{leaf}}, nil
+		},
+	}
+
+	return lst, nil
+}
+
+// newHTTPServerWithCertificateChain creates an HTTP server that listens on a random port,
+// and that presents the given certificates, in the given order, when serving TLS.
+// The first certificate must be the leaf, matching the given private key.
+func newHTTPServerWithCertificateChain(certs []*x509.Certificate, key interface{}) (*LocalServerTest, error) {
+	lst, err := newHTTPServer()
+	if err != nil {
+		return nil, err
+	}
+
+	chain := tls.Certificate{PrivateKey: key}
+	for _, cert := range certs {
+		chain.Certificate = append(chain.Certificate, cert.Raw)
+	}
+
+	// NOTE: Using GetConfigForClient, as the certificates files passed by ServeTLS would override Certificates
+	lst.server.TLSConfig = &tls.Config{
+		GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{Certificates: []tls.Certificate{chain}}, nil
+		},
+	}
+
+	return lst, nil
+}
+
+// generateTestCertificateChain generates a root CA, an intermediate CA and a leaf certificate for `localhost`,
+// each issued by the previous one, and returns them in this order along with the private key of the leaf.
+func generateTestCertificateChain() (root, intermediate, leaf *x509.Certificate, leafKey *ecdsa.PrivateKey, err error) {
+	issue := func(cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		template := &x509.Certificate{
+			SerialNumber:          serialNumber,
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+		}
+		if isCA {
+			template.KeyUsage = x509.KeyUsageCertSign
+		} else {
+			template.KeyUsage = x509.KeyUsageDigitalSignature
+			template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+			template.DNSNames = []string{"localhost"}
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+
+		certDER, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		cert, err := x509.ParseCertificate(certDER)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cert, key, nil
+	}
+
+	root, rootKey, err := issue("Test Root CA", true, nil, nil)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	intermediate, intermediateKey, err := issue("Test Intermediate CA", true, root, rootKey)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	leaf, leafKey, err = issue("localhost", false, intermediate, intermediateKey)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return root, intermediate, leaf, leafKey, nil
+}
+
 // newHTTPProxyServer creates an HTTP Proxy server that listens on a random port.
 func newHTTPProxyServer() (*LocalServerTest, error) {
 	listener, err := net.Listen("tcp", ":0")
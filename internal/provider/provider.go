@@ -16,13 +16,25 @@ func New() (*schema.Provider, error) {
 	return &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
 			"tls_private_key":         resourcePrivateKey(),
+			"tls_private_keys":        resourcePrivateKeys(),
 			"tls_locally_signed_cert": resourceLocallySignedCert(),
 			"tls_self_signed_cert":    resourceSelfSignedCert(),
 			"tls_cert_request":        resourceCertRequest(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"tls_public_key":  dataSourcePublicKey(),
-			"tls_certificate": dataSourceCertificate(),
+			"tls_public_key":            dataSourcePublicKey(),
+			"tls_certificate":           dataSourceCertificate(),
+			"tls_ca_bundle":             dataSourceCABundle(),
+			"tls_pem_bundle":            dataSourcePEMBundle(),
+			"tls_convert_key":           dataSourceConvertKey(),
+			"tls_validate_pem":          dataSourceValidatePEM(),
+			"tls_jwks":                  dataSourceJWKS(),
+			"tls_compare_certificates":  dataSourceCompareCertificates(),
+			"tls_pkcs7_sign":            dataSourcePKCS7Sign(),
+			"tls_crl":                   dataSourceCRL(),
+			"tls_assemble_certificate":  dataSourceAssembleCertificate(),
+			"tls_assemble_cert_request": dataSourceAssembleCertRequest(),
+			"tls_ssh_key_fingerprint":   dataSourceSSHKeyFingerprint(),
 		},
 		Schema: map[string]*schema.Schema{
 			"proxy": {
@@ -66,6 +78,89 @@ func New() (*schema.Provider, error) {
 				},
 				Description: "Proxy used by resources and data sources that connect to external endpoints.",
 			},
+			"default_key_algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedAlgorithmsStr(), false)),
+				Description: "Name of the algorithm used by `tls_private_key` resources that set neither `algorithm` nor `key_profile`. " +
+					fmt.Sprintf("Accepted values are: `%s`.", strings.Join(SupportedAlgorithmsStr(), "`, `")),
+			},
+			"default_rsa_bits": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "Size in bits of the RSA keys generated by `tls_private_key` resources that don't set `rsa_bits`.",
+			},
+			"default_validity_period_hours": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description: "Number of hours that certificates will remain valid for, " +
+					"used by certificate resources that set neither `validity_period_hours` nor `validity_period_days`.",
+			},
+			"fips_mode": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "When `true`, the keys generated by `tls_private_key`, `tls_private_keys` " +
+					"and the `generate_key` block of `tls_self_signed_cert` are restricted to those approved by " +
+					"[FIPS 186-4](https://csrc.nist.gov/publications/detail/fips/186/4/final): " +
+					"`RSA` keys of at least 2048 bits, and `ECDSA` keys with curve `P256`, `P384` or `P521`. " +
+					"Any other choice (e.g. `ED25519`, or `ECDSA` with curve `P224`, the default) is rejected when planning " +
+					"(default: `false`). This doesn't make the provider itself a FIPS-validated module.",
+			},
+			"strict_pem": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "When `true`, private keys in PEM format are rejected if anything but whitespaces " +
+					"follows their PEM block, instead of silently ignoring it (default: `false`). " +
+					"This catches private keys that were concatenated by mistake with other content.",
+			},
+			"enforce_usage_policy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allowed_usages": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"is_ca_certificate": {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+										Description: "Does this combination apply to Certificate Authority (CA) certificates, " +
+											"instead of leaf certificates (default: `false`).",
+									},
+									"usages": {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem: &schema.Schema{
+											Type:             schema.TypeString,
+											ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(supportedKeyUsages(), false)),
+										},
+										Description: "Key usages and extended key usages that certificates are allowed to combine, " +
+											"using the same values as `allowed_uses`. " +
+											fmt.Sprintf("Accepted values: `%s`.", strings.Join(supportedKeyUsages(), "`, `")),
+									},
+								},
+							},
+							Description: "Combinations of usages certificates can be issued with: " +
+								"the usages of each certificate must all be part of at least one combination " +
+								"that applies to its kind (i.e. CA or leaf).",
+						},
+					},
+				},
+				Description: "Policy enforced by certificate resources when signing a certificate: " +
+					"the creation of certificates whose usages (resolved from `allowed_uses`, `key_usages` and `extended_key_usages`) " +
+					"don't match any of the `allowed_usages` fails. " +
+					"This centralizes the policy of a hardened Certificate Authority (CA), for example " +
+					"to refuse leaf certificates that combine `digital_signature` and `cert_signing`.",
+			},
 		},
 		ConfigureContextFunc: configureProvider,
 	}, nil
@@ -75,6 +170,22 @@ func New() (*schema.Provider, error) {
 type providerConfig struct {
 	proxyURL     *url.URL
 	proxyFromEnv bool
+
+	defaultKeyAlgorithm        Algorithm
+	defaultRSABits             int
+	defaultValidityPeriodHours int
+
+	enforceUsagePolicy bool
+	allowedUsages      []allowedUsages
+
+	strictPEM bool
+	fipsMode  bool
+}
+
+// allowedUsages is a combination of usages allowed by the `enforce_usage_policy` of the provider.
+type allowedUsages struct {
+	isCA   bool
+	usages map[string]bool
 }
 
 func configureProvider(_ context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -106,6 +217,48 @@ func configureProvider(_ context.Context, data *schema.ResourceData) (interface{
 		config.proxyFromEnv = proxyFromEnv.(bool)
 	}
 
+	if defaultKeyAlgorithm, ok := data.GetOk("default_key_algorithm"); ok {
+		config.defaultKeyAlgorithm = Algorithm(defaultKeyAlgorithm.(string))
+	}
+
+	if defaultRSABits, ok := data.GetOk("default_rsa_bits"); ok {
+		config.defaultRSABits = defaultRSABits.(int)
+	}
+
+	if defaultValidityPeriodHours, ok := data.GetOk("default_validity_period_hours"); ok {
+		config.defaultValidityPeriodHours = defaultValidityPeriodHours.(int)
+	}
+
+	if strictPEM, ok := data.GetOk("strict_pem"); ok {
+		config.strictPEM = strictPEM.(bool)
+	}
+
+	if fipsMode, ok := data.GetOk("fips_mode"); ok {
+		config.fipsMode = fipsMode.(bool)
+	}
+
+	if policiesI := data.Get("enforce_usage_policy").([]interface{}); len(policiesI) > 0 {
+		config.enforceUsagePolicy = true
+
+		if policy, ok := policiesI[0].(map[string]interface{}); ok {
+			for _, allowedI := range policy["allowed_usages"].([]interface{}) {
+				allowed, ok := allowedI.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				combination := allowedUsages{
+					isCA:   allowed["is_ca_certificate"].(bool),
+					usages: map[string]bool{},
+				}
+				for _, usageI := range allowed["usages"].([]interface{}) {
+					combination.usages[usageI.(string)] = true
+				}
+				config.allowedUsages = append(config.allowedUsages, combination)
+			}
+		}
+	}
+
 	return config, diags
 }
 
@@ -126,3 +279,34 @@ func (pc *providerConfig) proxyForRequestFunc() func(_ *http.Request) (*url.URL,
 func (pc *providerConfig) isProxyConfigured() bool {
 	return pc.proxyURL != nil || pc.proxyFromEnv
 }
+
+// checkUsagePolicy returns an error if the given usages of a certificate, CA or not,
+// are not allowed by the `enforce_usage_policy` of the provider (when configured).
+func (pc *providerConfig) checkUsagePolicy(isCA bool, usages []string) error {
+	if !pc.enforceUsagePolicy {
+		return nil
+	}
+
+	for _, allowed := range pc.allowedUsages {
+		if allowed.isCA != isCA {
+			continue
+		}
+
+		allAllowed := true
+		for _, usage := range usages {
+			if !allowed.usages[usage] {
+				allAllowed = false
+				break
+			}
+		}
+		if allAllowed {
+			return nil
+		}
+	}
+
+	kind := "leaf"
+	if isCA {
+		kind = "CA"
+	}
+	return fmt.Errorf("usages [%s] of %s certificate are not allowed by the provider enforce_usage_policy", strings.Join(usages, ", "), kind)
+}
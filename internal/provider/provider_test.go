@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 	"time"
@@ -109,3 +110,67 @@ func TestProvider_InvalidProxyConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestProvider_StrictPEM(t *testing.T) {
+	config := func(strictPEM bool, prvKeyPEM string) string {
+		return fmt.Sprintf(`
+			provider "tls" {
+				strict_pem = %t
+			}
+			data "tls_public_key" "test" {
+				private_key_pem = %q
+			}
+		`, strictPEM, prvKeyPEM)
+	}
+	withTrailingData := testPrivateKeyPEM + "\n" + testCACert
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true, testPrivateKeyPEM),
+				Check:  resource.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", "RSA"),
+			},
+			{
+				Config: config(true, testPrivateKeyPEM+"\n  \t\n"),
+				Check:  resource.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", "RSA"),
+			},
+			{
+				Config: config(false, withTrailingData),
+				Check:  resource.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", "RSA"),
+			},
+			{
+				Config:      config(true, withTrailingData),
+				ExpectError: regexp.MustCompile(`unexpected data after the PEM block: \d+ bytes \(rejected as the provider is\s+configured with .strict_pem.\)`),
+			},
+		},
+	})
+}
+
+func TestParsePrivateKeyPEM_Strict(t *testing.T) {
+	testCases := map[string]struct {
+		keyPEM    string
+		expectErr bool
+	}{
+		"clean":               {keyPEM: testPrivateKeyPEM},
+		"trailing whitespace": {keyPEM: testPrivateKeyPEM + "\n\n \t\r\n"},
+		"trailing data":       {keyPEM: testPrivateKeyPEM + "garbage", expectErr: true},
+		"trailing PEM block":  {keyPEM: testPrivateKeyPEM + testCACert, expectErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parsePrivateKeyPEM([]byte(tc.keyPEM), false); err != nil {
+				t.Errorf("unexpected error in lenient mode: %v", err)
+			}
+
+			_, _, err := parsePrivateKeyPEM([]byte(tc.keyPEM), true)
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error in strict mode, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("unexpected error in strict mode: %v", err)
+			}
+		})
+	}
+}
@@ -2,9 +2,15 @@ package provider
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
 	"encoding/pem"
+	"fmt"
 	"net"
 	"net/url"
 
@@ -23,7 +29,32 @@ func resourceCertRequest() *schema.Resource {
 				"[libraries](https://pkg.go.dev/golang.org/x/crypto/ssh#MarshalAuthorizedKey) that generate this " +
 				"value append a `\\n` at the end of the PEM. " +
 				"In case this disrupts your use case, we recommend using " +
-				"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
+				"[`trimspace()`](https://www.terraform.io/language/functions/trimspace). " +
+				"Empty when `subject_public_key_pem` is set, as the certificate request is then signed externally.",
+		},
+
+		"cert_request_tbs_der_base64": {
+			Type:     schema.TypeString,
+			Computed: true,
+			Description: "The `CertificationRequestInfo` structure " +
+				"(see [RFC 2986](https://datatracker.ietf.org/doc/html/rfc2986#section-4.1)) in DER format, " +
+				"encoded in base64: this is the data to sign. When `subject_public_key_pem` is set, " +
+				"it can be signed externally with the private key, and assembled into a certificate request " +
+				"via the `tls_assemble_cert_request` data source.",
+		},
+
+		"subject_public_key_pem": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ExactlyOneOf:  []string{"private_key_pem", "private_key_pem_file", "subject_public_key_pem"},
+			ConflictsWith: []string{"signature_algorithm"},
+			Description: "Public key of the certificate request, in " +
+				"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+				"for when the private key is not available to Terraform, for example because it is held " +
+				"by a Hardware Security Module (HSM) or a cloud Key Management Service (KMS). " +
+				"The certificate request is then not signed: only `cert_request_tbs_der_base64` is set. " +
+				"This is _mutually exclusive_ with `private_key_pem` and `private_key_pem_file`.",
 		},
 
 		"id": {
@@ -34,26 +65,82 @@ func resourceCertRequest() *schema.Resource {
 		},
 	}
 	setCertificateSubjectSchema(s)
+	s["private_key_pem"].ExactlyOneOf = []string{"private_key_pem", "private_key_pem_file", "subject_public_key_pem"}
+	s["private_key_pem_file"].ExactlyOneOf = []string{"private_key_pem", "private_key_pem_file", "subject_public_key_pem"}
+
+	s["signature_algorithm"] = signatureAlgorithmSchema("certificate request")
 
 	return &schema.Resource{
 		CreateContext: createCertRequest,
 		DeleteContext: deleteCertRequest,
 		ReadContext:   readCertRequest,
+		CustomizeDiff: customizeSubjectAlternativeNamesDiff,
 
 		Description: "Creates a Certificate Signing Request (CSR) in " +
 			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.\n\n" +
 			"PEM is the typical format used to request a certificate from a Certificate Authority (CA).\n\n" +
 			"This resource is intended to be used in conjunction with a Terraform provider " +
-			"for a particular certificate authority in order to provision a new certificate.",
+			"for a particular certificate authority in order to provision a new certificate.\n\n" +
+			"The subject alternative names (`dns_names`, `ip_addresses` and `uris`) are requested via a " +
+			"Subject Alternative Name extension, carried in the " +
+			"[`extensionRequest` attribute (PKCS #9)](https://datatracker.ietf.org/doc/html/rfc2985#section-5.4.2) " +
+			"of the CSR: this is where certificate authorities read the requested extensions from.",
 
 		Schema: s,
 	}
 }
 
-func createCertRequest(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	key, algorithm, err := parsePrivateKeyPEM([]byte(d.Get("private_key_pem").(string)))
-	if err != nil {
-		return diag.FromErr(err)
+func createCertRequest(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var key crypto.PrivateKey
+	var pubKey crypto.PublicKey
+	var algorithm Algorithm
+	var pubKeyDER []byte
+	var err error
+	if v, ok := d.GetOk("subject_public_key_pem"); ok {
+		pemBlock, _ := pem.Decode([]byte(v.(string)))
+		if pemBlock == nil || pemBlock.Type != PreamblePublicKey.String() {
+			return diag.Errorf("invalid subject_public_key_pem: expected a %q PEM block", PreamblePublicKey)
+		}
+
+		pubKey, err = x509.ParsePKIXPublicKey(pemBlock.Bytes)
+		if err != nil {
+			return diag.Errorf("invalid subject_public_key_pem: %s", err)
+		}
+
+		algorithm, err = publicKeyToAlgorithm(pubKey)
+		if err != nil {
+			return diag.Errorf("invalid subject_public_key_pem: %s", err)
+		}
+
+		if diags := setPublicKeyParametersAttributes(d, "", pubKey); diags.HasError() {
+			return diags
+		}
+
+		pubKeyDER, _, err = publicKeyToPEM(pubKey)
+		if err != nil {
+			return diag.Errorf("failed to marshal subject_public_key_pem: %s", err)
+		}
+
+		// NOTE: The CertificationRequestInfo doesn't depend on the key that signs it, other than for
+		// the public key it carries: so it's created with a throwaway key, and its public key is replaced after
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return diag.Errorf("failed to generate throwaway key: %s", err)
+		}
+	} else {
+		key, algorithm, err = parsePrivateKeyPEMAttribute(d, "private_key_pem", m.(*providerConfig).strictPEM)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		pubKey, err = privateKeyToPublicKey(key)
+		if err != nil {
+			return diag.Errorf("failed to get public key from private key: %v", err)
+		}
+
+		if diags := setKeyParametersAttributes(d, "", key); diags.HasError() {
+			return diags
+		}
 	}
 
 	if err := d.Set("key_algorithm", algorithm); err != nil {
@@ -74,6 +161,21 @@ func createCertRequest(_ context.Context, d *schema.ResourceData, _ interface{})
 		Subject: *subject,
 	}
 
+	if d.Get("subject_serial_from_key").(bool) {
+		certReq.Subject.SerialNumber, err = publicKeySPKISHA256(pubKey)
+		if err != nil {
+			return diag.Errorf("error computing fingerprint of public key for subject serial number: %s", err)
+		}
+	}
+
+	// Encode the subject in the configured order, if any
+	if orderI := d.Get("subject_rdn_order").([]interface{}); len(orderI) > 0 {
+		certReq.RawSubject, err = marshalOrderedSubject(certReq.Subject, toStringSlice(orderI))
+		if err != nil {
+			return diag.Errorf("error marshaling subject: %s", err)
+		}
+	}
+
 	dnsNamesI := d.Get("dns_names").([]interface{})
 	for _, nameI := range dnsNamesI {
 		certReq.DNSNames = append(certReq.DNSNames, nameI.(string))
@@ -95,14 +197,68 @@ func createCertRequest(_ context.Context, d *schema.ResourceData, _ interface{})
 		certReq.URIs = append(certReq.URIs, uri)
 	}
 
+	// Encode the SANs in the configured order, if given via `san`
+	if sansI := d.Get("san").([]interface{}); len(sansI) > 0 {
+		sans, err := parseOrderedSANs(sansI)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		certReq.DNSNames, certReq.EmailAddresses, certReq.IPAddresses, certReq.URIs = sans.dnsNames, sans.emailAddresses, sans.ipAddresses, sans.uris
+
+		sanExt, err := marshalSANExtension(sans.generalNames, certReq.Subject)
+		if err != nil {
+			return diag.Errorf("failed to marshal subject alternative names extension: %s", err)
+		}
+		certReq.ExtraExtensions = append(certReq.ExtraExtensions, sanExt)
+	}
+
+	if sigAlg, ok := d.GetOk("signature_algorithm"); ok {
+		if err := checkSignatureAlgorithmForKey(sigAlg.(string), key); err != nil {
+			return diag.FromErr(err)
+		}
+		certReq.SignatureAlgorithm = signatureAlgorithms[sigAlg.(string)]
+	}
+
+	// NOTE: A CSR has no dedicated field for the subject alternative names:
+	// x509.CreateCertificateRequest encodes them in a Subject Alternative Name extension,
+	// carried in the PKCS #9 `extensionRequest` attribute, as long as the template
+	// doesn't set an `extensionRequest` attribute of its own in `Attributes`
 	certReqBytes, err := x509.CreateCertificateRequest(rand.Reader, &certReq, key)
 	if err != nil {
 		return diag.Errorf("error creating certificate request: %s", err)
 	}
+	parsedCertReq, err := x509.ParseCertificateRequest(certReqBytes)
+	if err != nil {
+		return diag.Errorf("error parsing certificate request: %s", err)
+	}
+
+	if pubKeyDER != nil {
+		tbs, err := replaceCertRequestInfoPublicKey(parsedCertReq.RawTBSCertificateRequest, pubKeyDER)
+		if err != nil {
+			return diag.Errorf("error creating certificate request info: %s", err)
+		}
+
+		d.SetId(hashForState(string(tbs)))
+
+		if err := d.Set("cert_request_tbs_der_base64", base64.StdEncoding.EncodeToString(tbs)); err != nil {
+			return diag.Errorf("error setting value on key 'cert_request_tbs_der_base64': %s", err)
+		}
+
+		if err := d.Set("cert_request_pem", ""); err != nil {
+			return diag.Errorf("error setting value on key 'cert_request_pem': %s", err)
+		}
+
+		return nil
+	}
+
 	certReqPem := string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificateRequest.String(), Bytes: certReqBytes}))
 
 	d.SetId(hashForState(string(certReqBytes)))
 
+	if err := d.Set("cert_request_tbs_der_base64", base64.StdEncoding.EncodeToString(parsedCertReq.RawTBSCertificateRequest)); err != nil {
+		return diag.Errorf("error setting value on key 'cert_request_tbs_der_base64': %s", err)
+	}
+
 	if err := d.Set("cert_request_pem", certReqPem); err != nil {
 		return diag.Errorf("error setting value on key 'cert_request_pem': %s", err)
 	}
@@ -110,6 +266,29 @@ func createCertRequest(_ context.Context, d *schema.ResourceData, _ interface{})
 	return nil
 }
 
+// replaceCertRequestInfoPublicKey returns the given DER CertificationRequestInfo
+// (see https://datatracker.ietf.org/doc/html/rfc2986#section-4.1), with its `subjectPKInfo`
+// replaced by the given DER SubjectPublicKeyInfo.
+func replaceCertRequestInfoPublicKey(tbsBytes, pubKeyDER []byte) ([]byte, error) {
+	var tbs struct {
+		Version       int
+		Subject       asn1.RawValue
+		SubjectPKInfo asn1.RawValue
+		Attributes    asn1.RawValue
+	}
+	rest, err := asn1.Unmarshal(tbsBytes, &tbs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after CertificationRequestInfo")
+	}
+
+	tbs.SubjectPKInfo = asn1.RawValue{FullBytes: pubKeyDER}
+
+	return asn1.Marshal(tbs)
+}
+
 func deleteCertRequest(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	d.SetId("")
 	return nil
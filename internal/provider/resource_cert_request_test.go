@@ -1,10 +1,14 @@
 package provider
 
 import (
+	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"testing"
 
 	r "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -110,6 +114,86 @@ EOT
 	})
 }
 
+func TestCertRequest_InvalidConfigs(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						subject {
+							common_name = "example.net"
+						}
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`(Too many subject blocks|Too many list items)`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						dns_names = [""]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected dns_names to not contain empty values`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						ip_addresses = ["::1", "0:0:0:0:0:0:0:1"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`ip_addresses.1: duplicate value "0:0:0:0:0:0:0:1", already present at ip_addresses.0`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						ip_addresses = ["10.0.0.0/8"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected ip_addresses to contain single IP addresses, got IP range\s+"10.0.0.0/8" in CIDR notation`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						uris = ["spiffe://example-trust-domain/workload", "spiffe://example-trust-domain/workload"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`uris.1: duplicate value "spiffe://example-trust-domain/workload", already present at uris.0`),
+			},
+		},
+	})
+}
+
 // TODO Remove this as part of https://github.com/hashicorp/terraform-provider-tls/issues/174
 func TestCertRequest_HandleKeyAlgorithmDeprecation(t *testing.T) {
 	r.UnitTest(t, r.TestCase{
@@ -128,7 +212,10 @@ func TestCertRequest_HandleKeyAlgorithmDeprecation(t *testing.T) {
 						private_key_pem = tls_private_key.test.private_key_pem
 					}
 				`,
-				Check: r.TestCheckResourceAttr("tls_cert_request.test", "key_algorithm", "RSA"),
+				Check: r.ComposeTestCheckFunc(
+					r.TestCheckResourceAttr("tls_cert_request.test", "key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_cert_request.test", "rsa_bits", "2048"),
+				),
 			},
 			{
 				Config: `
@@ -142,9 +229,312 @@ func TestCertRequest_HandleKeyAlgorithmDeprecation(t *testing.T) {
 						private_key_pem = tls_private_key.test.private_key_pem
 					}
 				`,
-				Check: r.TestCheckResourceAttr("tls_cert_request.test", "key_algorithm", "RSA"),
+				Check: r.ComposeTestCheckFunc(
+					r.TestCheckResourceAttr("tls_cert_request.test", "key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_cert_request.test", "rsa_bits", "2048"),
+				),
 			},
 		},
 	},
 	)
 }
+
+func TestCertRequest_SignatureAlgorithm(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						signature_algorithm = "SHA256-RSAPSS"
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateRequestWith("tls_cert_request.test", "cert_request_pem", func(csr *x509.CertificateRequest) error {
+					if csr.SignatureAlgorithm != x509.SHA256WithRSAPSS {
+						return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.SHA256WithRSAPSS, csr.SignatureAlgorithm)
+					}
+					return csr.CheckSignature()
+				}),
+			},
+		},
+	})
+}
+
+func TestCertRequest_SignatureAlgorithmIndependentOfCurve(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						signature_algorithm = "ECDSA-SHA512"
+						private_key_pem = tls_private_key.test.private_key_pem
+					}
+				`,
+				Check: testCheckPEMCertificateRequestWith("tls_cert_request.test", "cert_request_pem", func(csr *x509.CertificateRequest) error {
+					if csr.SignatureAlgorithm != x509.ECDSAWithSHA512 {
+						return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.ECDSAWithSHA512, csr.SignatureAlgorithm)
+					}
+					return csr.CheckSignature()
+				}),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						signature_algorithm = "SHA256-RSA"
+						private_key_pem = tls_private_key.test.private_key_pem
+					}
+				`,
+				ExpectError: regexp.MustCompile("signature_algorithm SHA256-RSA cannot be used with ECDSA keys"),
+			},
+		},
+	})
+}
+
+func TestCertRequest_ExtensionRequestSANs(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						dns_names    = ["example.com", "www.example.com"]
+						ip_addresses = ["127.0.0.1", "::1"]
+						uris         = ["spiffe://example.com/service"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateRequestExtensionRequestSANs("tls_cert_request.test", "cert_request_pem", []string{
+					"dns:example.com",
+					"dns:www.example.com",
+					"ip:127.0.0.1",
+					"ip:::1",
+					"uri:spiffe://example.com/service",
+				}),
+			},
+		},
+	})
+}
+
+func TestCertRequest_SANOrder(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						san {
+							type  = "uri"
+							value = "spiffe://example.com/service"
+						}
+						san {
+							type  = "dns"
+							value = "www.example.com"
+						}
+						san {
+							type  = "ip"
+							value = "::1"
+						}
+						san {
+							type  = "email"
+							value = "admin@example.com"
+						}
+						san {
+							type  = "dns"
+							value = "example.com"
+						}
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateRequestExtensionRequestSANs("tls_cert_request.test", "cert_request_pem", []string{
+					"uri:spiffe://example.com/service",
+					"dns:www.example.com",
+					"ip:::1",
+					"email:admin@example.com",
+					"dns:example.com",
+				}),
+			},
+		},
+	})
+}
+
+func TestCertRequest_SubjectRDNOrder(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name  = "example.com"
+							organization = "Example, Inc"
+							country      = "GB"
+						}
+						subject_rdn_order = ["common_name", "organization"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateRequestWith("tls_cert_request.test", "cert_request_pem", func(csr *x509.CertificateRequest) error {
+					if err := compareCertSubjects(&pkix.Name{CommonName: "example.com", Organization: []string{"Example, Inc"}, Country: []string{"GB"}}, &csr.Subject); err != nil {
+						return err
+					}
+					return compareSubjectRDNOrder([]asn1.ObjectIdentifier{
+						subjectAttributeTypes["common_name"],
+						subjectAttributeTypes["organization"],
+						subjectAttributeTypes["country"],
+					}, csr.RawSubject)
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						subject_rdn_order = ["email_address"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected subject_rdn_order to be one of`),
+			},
+		},
+	})
+}
+
+func TestCertRequest_SubjectPublicKey(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "public_key" {
+						subject {
+							common_name = "example.com"
+						}
+						dns_names              = ["example.com"]
+						subject_public_key_pem = <<EOT
+%s
+EOT
+					}
+
+					resource "tls_cert_request" "private_key" {
+						subject {
+							common_name = "example.com"
+						}
+						dns_names       = ["example.com"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPublicKeyPEM, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_cert_request.public_key", "cert_request_pem", ""),
+					r.TestCheckResourceAttr("tls_cert_request.public_key", "key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_cert_request.public_key", "rsa_bits", "1024"),
+					r.TestMatchResourceAttr("tls_cert_request.public_key", "cert_request_tbs_der_base64", regexp.MustCompile(`^[A-Za-z0-9+/]+=*$`)),
+					// The data to sign is the same as the one signed with the private key
+					r.TestCheckResourceAttrPair(
+						"tls_cert_request.public_key", "cert_request_tbs_der_base64",
+						"tls_cert_request.private_key", "cert_request_tbs_der_base64",
+					),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						signature_algorithm    = "SHA256-RSA"
+						subject_public_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPublicKeyPEM),
+				ExpectError: regexp.MustCompile(`"signature_algorithm": conflicts with subject_public_key_pem`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						subject_public_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest),
+				ExpectError: regexp.MustCompile(`invalid subject_public_key_pem: expected a "PUBLIC KEY" PEM block`),
+			},
+		},
+	})
+}
+
+func TestCertRequest_SubjectSerialFromKey(t *testing.T) {
+	block, _ := pem.Decode([]byte(testPublicKeyPEM))
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := publicKeySPKISHA256(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_cert_request" "test" {
+						subject {
+							common_name = "device.example.com"
+						}
+						subject_serial_from_key = true
+						private_key_pem         = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateRequestSubject("tls_cert_request.test", "cert_request_pem", &pkix.Name{
+					CommonName:   "device.example.com",
+					SerialNumber: fingerprint,
+				}),
+			},
+		},
+	})
+}
@@ -2,26 +2,60 @@ package provider
 
 import (
 	"context"
+	"crypto"
 	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceLocallySignedCert() *schema.Resource {
 	s := map[string]*schema.Schema{}
 
 	setCertificateCommonSchema(s)
+	setCertificateSubjectNamesSchema(s)
+
+	// When `cert_request_pem` is provided, subject and SANs are sourced from the Certificate Request instead
+	s["subject"].Required = false
+	s["subject"].Optional = true
+	s["subject_rdn_order"].RequiredWith = []string{"subject"}
+	s["subject_serial_from_key"].ConflictsWith = []string{"subject.0.serial_number", "cert_request_pem"}
 
 	s["cert_request_pem"] = &schema.Schema{
-		Type:     schema.TypeString,
-		Required: true,
-		ForceNew: true,
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ExactlyOneOf:  []string{"cert_request_pem", "subject_public_key_pem"},
+		ConflictsWith: []string{"subject", "dns_names", "ip_addresses", "uris", "san"},
 		StateFunc: func(v interface{}) string {
 			return hashForState(v.(string))
 		},
 		Description: "Certificate request data in " +
-			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"The subject and the Subject Alternative Names (DNS names, email addresses, IP addresses and URIs) " +
+			"of the certificate are sourced from the certificate request. " +
+			"This is _mutually exclusive_ with `subject_public_key_pem`.",
+	}
+
+	s["subject_public_key_pem"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"cert_request_pem", "subject_public_key_pem"},
+		Description: "Public key to certify, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+			"for when the subject of the certificate shared only a public key instead of a certificate request. " +
+			"The `subject`, `dns_names`, `ip_addresses` and `uris` (or `san`) of the certificate are then taken from the configuration. " +
+			"This is _mutually exclusive_ with `cert_request_pem`.",
 	}
 
 	s["ca_key_algorithm"] = &schema.Schema{
@@ -34,16 +68,216 @@ func resourceLocallySignedCert() *schema.Resource {
 			"**NOTE**: this is deprecated and ignored, as the key algorithm is now inferred from the key. ",
 	}
 
+	s["ca_rsa_bits"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+		Description: "Size in bits of the private key provided in `ca_private_key_pem`, " +
+			"when the key algorithm is `RSA` (`0` otherwise).",
+	}
+
+	s["ca_ecdsa_curve"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Elliptic curve of the private key provided in `ca_private_key_pem`, " +
+			"when the key algorithm is `ECDSA` (empty otherwise).",
+	}
+
 	s["ca_private_key_pem"] = &schema.Schema{
-		Type:      schema.TypeString,
-		Required:  true,
-		ForceNew:  true,
-		Sensitive: true,
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		Sensitive:    true,
+		ExactlyOneOf: []string{"ca_private_key_pem", "ca_private_key_pem_file"},
 		StateFunc: func(v interface{}) string {
 			return hashForState(v.(string))
 		},
 		Description: "Private key of the Certificate Authority (CA) used to sign the certificate, " +
-			"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"It must match the public key of the certificate in `ca_cert_pem`. " +
+			"This is _mutually exclusive_ with `ca_private_key_pem_file`.",
+	}
+
+	s["ca_private_key_pem_file"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"ca_private_key_pem", "ca_private_key_pem_file"},
+		Description: "Path of a file containing the private key of the Certificate Authority (CA) " +
+			"used to sign the certificate, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"The file is read on the machine running `terraform apply`, only when the resource is created: " +
+			"only its path is stored in the Terraform state, and changes to its content are not detected. " +
+			"This is _mutually exclusive_ with `ca_private_key_pem`.",
+	}
+
+	s["user_principal_names"] = &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"san"},
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateUserPrincipalName,
+		},
+		Description: "List of User Principal Names (e.g. `user@example.com`) to add to the Subject Alternative Names " +
+			"of the certificate, as `otherName` of type UPN (`1.3.6.1.4.1.311.20.2.3`), " +
+			"alongside the DNS names, email addresses, IP addresses and URIs of the certificate request. " +
+			"This is required for Active Directory smartcard logon. " +
+			"This is _mutually exclusive_ with `san`.",
+	}
+
+	s["set_authority_cert_issuer_and_serial"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Default:  false,
+		Description: "Should the [authority key identifier](https://datatracker.ietf.org/doc/html/rfc5280#section-4.2.1.1) " +
+			"of the generated certificate include, besides the key identifier, the issuer and the serial number " +
+			"of the Certificate Authority (CA) certificate (i.e. `authorityCertIssuer` and `authorityCertSerialNumber`), " +
+			"as expected by some legacy systems (default: `false`).",
+	}
+
+	s["admission"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"admission_authority_url": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateURI,
+					Description:      "URI identifying the authority that granted the admissions (e.g. a professional chamber).",
+				},
+				"profession_info": {
+					Type:     schema.TypeList,
+					Required: true,
+					ForceNew: true,
+					MinItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"profession_items": {
+								Type:     schema.TypeList,
+								Required: true,
+								ForceNew: true,
+								MinItems: 1,
+								Elem: &schema.Schema{
+									Type:             schema.TypeString,
+									ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 128)),
+								},
+								Description: "List of professions (e.g. `Ärztin/Arzt`), as free text of at most 128 characters each.",
+							},
+							"profession_oids": {
+								Type:     schema.TypeList,
+								Optional: true,
+								ForceNew: true,
+								Elem: &schema.Schema{
+									Type:             schema.TypeString,
+									ValidateDiagFunc: validation.ToDiagFunc(validateOID),
+								},
+								Description: "List of object identifiers of the professions, in dotted decimal notation " +
+									"(e.g. `1.2.276.0.76.4.30` for `oid_arzt` of the German telematics infrastructure).",
+							},
+							"registration_number": {
+								Type:     schema.TypeString,
+								Optional: true,
+								ForceNew: true,
+								ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(
+									regexp.MustCompile(`^[A-Za-z0-9 '()+,\-./:=?]{1,128}$`),
+									"must be a PrintableString of at most 128 characters",
+								)),
+								Description: "Registration number of the professional (e.g. a lifelong physician number), " +
+									"made only of letters, digits, spaces and the characters `'()+,-./:=?`.",
+							},
+						},
+					},
+					Description: "List of professions the subject of the certificate is admitted to.",
+				},
+			},
+		},
+		Description: "The Common PKI (formerly ISIS-MTT) Admission extension (`1.3.36.8.3.3`), " +
+			"describing the professions the subject of the certificate is admitted to, " +
+			"as used for example by the German health and government PKIs. " +
+			"Naming authorities and additional profession information are not supported.",
+	}
+
+	s["logotype"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					Default:          "subject",
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"community", "issuer", "subject"}, false)),
+					Description: "Type of the logotype: `community`, `issuer` or `subject` " +
+						"(default: `subject`).",
+				},
+				"media_type": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					Description:      "Media type of the image (e.g. `image/svg+xml` or `image/png`).",
+				},
+				"image_url": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateURI,
+					Description:      "URL the image can be retrieved from.",
+				},
+				"image_hash_sha256": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(
+						regexp.MustCompile(`^[0-9A-Fa-f]{64}$`),
+						"must be a SHA256 hash, in hexadecimal",
+					)),
+					Description: "SHA256 hash of the image, in hexadecimal, " +
+						"that clients use to verify the image retrieved from `image_url`.",
+				},
+			},
+		},
+		Description: "The [Logotype](https://datatracker.ietf.org/doc/html/rfc3709) extension (`1.3.6.1.5.5.7.1.12`), " +
+			"referencing an image to display for the certificate, as used for example by branded certificates. " +
+			"Only a single image, referenced directly by URL, is supported.",
+	}
+
+	s["smime_capabilities"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"capability_id": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validateOID),
+					Description: "Object identifier of the capability, in dotted decimal notation " +
+						"(e.g. `2.16.840.1.101.3.4.1.42` for `AES-256-CBC`).",
+				},
+				"parameters_base64": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+					Description: "Parameters of the capability, as a single ASN.1 value in DER format, encoded in base64 " +
+						"(e.g. `AgEo`, the INTEGER `40`, for the key size of `RC2-CBC`). " +
+						"If not set, the capability has no parameters, as is the case for most algorithms.",
+				},
+			},
+		},
+		Description: "The [S/MIME Capabilities](https://datatracker.ietf.org/doc/html/rfc4262) extension " +
+			"(`1.2.840.113549.1.9.15`), listing the algorithms supported by the subject of the certificate " +
+			"to encrypt and sign emails, in order of preference. Can be repeated.",
 	}
 
 	s["ca_cert_pem"] = &schema.Schema{
@@ -54,7 +288,60 @@ func resourceLocallySignedCert() *schema.Resource {
 			return hashForState(v.(string))
 		},
 		Description: "Certificate data of the Certificate Authority (CA) " +
-			"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			"in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"This can contain multiple certificates: the first is the CA that signs the certificate, " +
+			"and the others are included in `cert_chain_pem` (e.g. when the signing CA is an intermediate, " +
+			"followed by its issuers up to the root).",
+	}
+
+	s["cert_chain_pem"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Certificate chain in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format: " +
+			"the issued certificate (i.e. `cert_pem`), followed by all the certificates in `ca_cert_pem`.",
+	}
+
+	s["within_ca_validity"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Computed: true,
+		Description: "Is the validity period of the certificate entirely within the validity period " +
+			"of the Certificate Authority (CA) certificate in `ca_cert_pem`? " +
+			"When `false`, the certificate can be valid at times when it doesn't chain to the CA.",
+	}
+
+	s["ca_validity_overhang_hours"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+		Description: "Number of hours, rounded up, of the validity period of the certificate that fall outside " +
+			"the validity period of the Certificate Authority (CA) certificate in `ca_cert_pem`, " +
+			"either before it starts or after it ends: `0` when `within_ca_validity` is `true`.",
+	}
+
+	s["serial_number_file"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"certificate_serial_hex"},
+		Description: "Path of a file holding the last serial number issued by the Certificate Authority (CA), " +
+			"in hexadecimal (like the `-CAserial` file of `openssl x509`). " +
+			"When set, the certificate is assigned the serial number following the one in the file, " +
+			"which is then updated: this way serial numbers increase monotonically across applies, " +
+			"as long as all the certificates issued by the same CA use the same file. " +
+			"If the file doesn't exist, it is created and the first serial number is `1`. " +
+			"While a serial number is assigned, an exclusive lock is held on the file with the same path plus `.lock` " +
+			"(created if needed), so that concurrent applies using the same file never assign the same serial number. " +
+			"The file is read and written on the machine running `terraform apply`, only when the certificate is created, " +
+			"right before signing it: a serial number is skipped only if the apply fails after that. " +
+			"Cannot be used with `certificate_serial_hex`.",
+	}
+
+	s["skip_ca_validity_check"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		Description: "By default, the certificate is not created if the Certificate Authority (CA) certificate " +
+			"provided in `ca_cert_pem` is expired or not yet valid, as the resulting certificate would not chain. " +
+			"When `true`, a warning is raised instead (default: `false`).",
 	}
 
 	return &schema.Resource{
@@ -62,21 +349,52 @@ func resourceLocallySignedCert() *schema.Resource {
 		DeleteContext: deleteCertificate,
 		ReadContext:   readCertificate,
 		UpdateContext: updateCertificate,
-		CustomizeDiff: customizeCertificateDiff,
+		CustomizeDiff: customdiff.All(customizeCertificateDiff, customizeSubjectAlternativeNamesDiff, customizeNameConstraintsDiff, customizeMaxPathLengthDiff, customizeValidityPeriodDiff),
 		Schema:        s,
 		Description: "Creates a TLS certificate in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) " +
-			"format using a Certificate Signing Request (CSR) and signs it with a provided " +
+			"format using a Certificate Signing Request (CSR), or a bare public key, and signs it with a provided " +
 			"(local) Certificate Authority (CA).",
 	}
 }
 
-func createLocallySignedCert(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	certReq, err := parseCertificateRequest(d, "cert_request_pem")
-	if err != nil {
-		return diag.FromErr(err)
+func createLocallySignedCert(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	issuanceStart := time.Now()
+
+	cert := x509.Certificate{
+		BasicConstraintsValid: true,
 	}
 
-	caKey, algorithm, err := parsePrivateKeyPEM([]byte(d.Get("ca_private_key_pem").(string)))
+	var publicKey crypto.PublicKey
+	if _, ok := d.GetOk("cert_request_pem"); ok {
+		certReq, err := parseCertificateRequest(d, "cert_request_pem")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		cert.Subject = certReq.Subject
+		cert.DNSNames = certReq.DNSNames
+		cert.EmailAddresses = certReq.EmailAddresses
+		cert.IPAddresses = certReq.IPAddresses
+		cert.URIs = certReq.URIs
+		publicKey = certReq.PublicKey
+	} else {
+		pemBlock, _ := pem.Decode([]byte(d.Get("subject_public_key_pem").(string)))
+		if pemBlock == nil || pemBlock.Type != PreamblePublicKey.String() {
+			return diag.Errorf("invalid subject_public_key_pem: expected a %q PEM block", PreamblePublicKey)
+		}
+
+		var err error
+		publicKey, err = x509.ParsePKIXPublicKey(pemBlock.Bytes)
+		if err != nil {
+			return diag.Errorf("invalid subject_public_key_pem: %s", err)
+		}
+
+		if diags := setCertificateNamesFromConfig(d, &cert, publicKey); diags.HasError() {
+			return diags
+		}
+	}
+
+	caKey, algorithm, err := parsePrivateKeyPEMAttribute(d, "ca_private_key_pem", m.(*providerConfig).strictPEM)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -85,18 +403,174 @@ func createLocallySignedCert(_ context.Context, d *schema.ResourceData, _ interf
 		return diag.Errorf("error setting value on key 'ca_key_algorithm': %s", err)
 	}
 
-	caCert, err := parseCertificate(d, "ca_cert_pem")
+	if diags := setKeyParametersAttributes(d, "ca_", caKey); diags.HasError() {
+		return diags
+	}
+
+	caCerts, err := parseCertificatesPEM([]byte(d.Get("ca_cert_pem").(string)))
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.Errorf("invalid ca_cert_pem: %s", err)
 	}
+	caCert := caCerts[0]
 
-	cert := x509.Certificate{
-		Subject:               certReq.Subject,
-		DNSNames:              certReq.DNSNames,
-		IPAddresses:           certReq.IPAddresses,
-		URIs:                  certReq.URIs,
-		BasicConstraintsValid: true,
+	// A certificate signed with a key other than the CA one would not verify against the CA certificate
+	caPublicKey, err := privateKeyToPublicKey(caKey)
+	if err != nil {
+		return diag.Errorf("failed to get public key from ca_private_key_pem: %v", err)
+	}
+	if !publicKeysEqual(caPublicKey, caCert.PublicKey) {
+		return diag.Errorf("the public key of ca_private_key_pem doesn't match the public key of ca_cert_pem")
+	}
+
+	var diags diag.Diagnostics
+	if now := overridableTimeFunc(); now.Before(caCert.NotBefore) || now.After(caCert.NotAfter) {
+		caValidityDiag := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "CA certificate is not valid at the current time",
+			Detail: fmt.Sprintf("The certificate provided in 'ca_cert_pem' is valid from %s to %s, "+
+				"but the current time is %s: the issued certificate would not chain to it. "+
+				"Set 'skip_ca_validity_check' to issue the certificate anyway.",
+				caCert.NotBefore.Format(time.RFC3339), caCert.NotAfter.Format(time.RFC3339), now.Format(time.RFC3339)),
+		}
+
+		if !d.Get("skip_ca_validity_check").(bool) {
+			return append(diags, caValidityDiag)
+		}
+
+		caValidityDiag.Severity = diag.Warning
+		diags = append(diags, caValidityDiag)
+	}
+
+	if upnsI := d.Get("user_principal_names").([]interface{}); len(upnsI) > 0 {
+		upns := make([]string, len(upnsI))
+		for i, upnI := range upnsI {
+			upns[i] = upnI.(string)
+		}
+
+		sanExt, err := marshalSANExtensionWithUPNs(&cert, upns)
+		if err != nil {
+			return append(diags, diag.Errorf("failed to marshal subject alternative names extension: %s", err)...)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, sanExt)
 	}
 
-	return createCertificate(d, &cert, caCert, certReq.PublicKey, caKey)
+	if admissionI := d.Get("admission").([]interface{}); len(admissionI) > 0 && admissionI[0] != nil {
+		admission := admissionI[0].(map[string]interface{})
+
+		infosI := admission["profession_info"].([]interface{})
+		infos := make([]professionInfo, len(infosI))
+		for i, infoI := range infosI {
+			info := infoI.(map[string]interface{})
+
+			infos[i].ProfessionItems = toStringSlice(info["profession_items"].([]interface{}))
+			infos[i].RegistrationNumber = info["registration_number"].(string)
+			for _, oidI := range info["profession_oids"].([]interface{}) {
+				oid, err := parseOID(oidI.(string))
+				if err != nil {
+					return append(diags, diag.Errorf("invalid admission.0.profession_info.%d.profession_oids: %s", i, err)...)
+				}
+				infos[i].ProfessionOIDs = append(infos[i].ProfessionOIDs, oid)
+			}
+		}
+
+		admissionExt, err := marshalAdmissionExtension(admission["admission_authority_url"].(string), infos)
+		if err != nil {
+			return append(diags, diag.Errorf("failed to marshal admission extension: %s", err)...)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, admissionExt)
+	}
+
+	if logotypeI := d.Get("logotype").([]interface{}); len(logotypeI) > 0 && logotypeI[0] != nil {
+		logotype := logotypeI[0].(map[string]interface{})
+
+		imageSHA256, err := hex.DecodeString(logotype["image_hash_sha256"].(string))
+		if err != nil {
+			return append(diags, diag.Errorf("invalid logotype.0.image_hash_sha256: %s", err)...)
+		}
+
+		logotypeExt, err := marshalLogotypeExtension(logotype["type"].(string), logotype["media_type"].(string), logotype["image_url"].(string), imageSHA256)
+		if err != nil {
+			return append(diags, diag.Errorf("failed to marshal logotype extension: %s", err)...)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, logotypeExt)
+	}
+
+	if capabilitiesI := d.Get("smime_capabilities").([]interface{}); len(capabilitiesI) > 0 {
+		capabilities := make([]smimeCapability, len(capabilitiesI))
+		for i, capabilityI := range capabilitiesI {
+			capability := capabilityI.(map[string]interface{})
+
+			capabilities[i].CapabilityID, err = parseOID(capability["capability_id"].(string))
+			if err != nil {
+				return append(diags, diag.Errorf("invalid smime_capabilities.%d.capability_id: %s", i, err)...)
+			}
+
+			if parametersBase64 := capability["parameters_base64"].(string); parametersBase64 != "" {
+				parameters, err := base64.StdEncoding.DecodeString(parametersBase64)
+				if err == nil {
+					var rest []byte
+					rest, err = asn1.Unmarshal(parameters, &capabilities[i].Parameters)
+					if err == nil && len(rest) > 0 {
+						err = fmt.Errorf("trailing data after the ASN.1 value")
+					}
+				}
+				if err != nil {
+					return append(diags, diag.Errorf("invalid smime_capabilities.%d.parameters_base64: %s", i, err)...)
+				}
+			}
+		}
+
+		capabilitiesExt, err := marshalSMIMECapabilitiesExtension(capabilities)
+		if err != nil {
+			return append(diags, diag.Errorf("failed to marshal S/MIME capabilities extension: %s", err)...)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, capabilitiesExt)
+	}
+
+	if d.Get("set_authority_cert_issuer_and_serial").(bool) {
+		akiExt, err := marshalAuthorityKeyIDExtensionWithIssuerAndSerial(caCert)
+		if err != nil {
+			return append(diags, diag.Errorf("failed to marshal authority key identifier extension: %s", err)...)
+		}
+		cert.ExtraExtensions = append(cert.ExtraExtensions, akiExt)
+	}
+
+	diags = append(diags, createCertificate(d, m.(*providerConfig), &cert, caCert, publicKey, caKey)...)
+	if diags.HasError() {
+		return diags
+	}
+	if setDiags := setIssuanceDuration(d, issuanceStart); setDiags.HasError() {
+		return append(diags, setDiags...)
+	}
+
+	certChainPem := d.Get("cert_pem").(string)
+	for _, c := range caCerts {
+		certChainPem += string(pem.EncodeToMemory(&pem.Block{Type: PreambleCertificate.String(), Bytes: c.Raw}))
+	}
+	if err := d.Set("cert_chain_pem", certChainPem); err != nil {
+		return append(diags, diag.Errorf("error setting value on key 'cert_chain_pem': %s", err)...)
+	}
+
+	overhang := caValidityOverhang(&cert, caCert)
+	if err := d.Set("within_ca_validity", overhang == 0); err != nil {
+		return append(diags, diag.Errorf("error setting value on key 'within_ca_validity': %s", err)...)
+	}
+	if err := d.Set("ca_validity_overhang_hours", int(math.Ceil(overhang.Hours()))); err != nil {
+		return append(diags, diag.Errorf("error setting value on key 'ca_validity_overhang_hours': %s", err)...)
+	}
+
+	return diags
+}
+
+// caValidityOverhang returns how long the validity period of the given certificate extends
+// beyond the validity period of the given CA certificate, adding up both ends.
+func caValidityOverhang(cert, caCert *x509.Certificate) time.Duration {
+	var overhang time.Duration
+	if cert.NotBefore.Before(caCert.NotBefore) {
+		overhang += caCert.NotBefore.Sub(cert.NotBefore)
+	}
+	if cert.NotAfter.After(caCert.NotAfter) {
+		overhang += cert.NotAfter.Sub(caCert.NotAfter)
+	}
+	return overhang
 }
@@ -1,15 +1,25 @@
 package provider
 
 import (
+	"bytes"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	r "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestLocallySignedCert(t *testing.T) {
@@ -166,6 +176,30 @@ func TestAccLocallySignedCertNotRecreatedForEarlyRenewalUpdateInFuture(t *testin
 	overridableTimeFunc = oldNow
 }
 
+func TestAccLocallySignedCert_RenewalPreservesCertificate(t *testing.T) {
+	var previousCert string
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: locallySignedCertConfig(10, 2),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDuration("tls_locally_signed_cert.test", "cert_pem", 10*time.Hour),
+					testCheckAttrSaveValue("tls_locally_signed_cert.test", "cert_pem", &previousCert),
+				),
+			},
+			{
+				Config: locallySignedCertConfig(20, 2),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDuration("tls_locally_signed_cert.test", "cert_pem", 20*time.Hour),
+					testCheckPEMCertificateRenewalOf("tls_locally_signed_cert.test", "cert_pem", &previousCert),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
 // TODO Remove this as part of https://github.com/hashicorp/terraform-provider-tls/issues/174
 func TestAccLocallySignedCert_HandleKeyAlgorithmDeprecation(t *testing.T) {
 	r.UnitTest(t, r.TestCase{
@@ -309,6 +343,10 @@ func TestAccResourceLocallySignedCert_FromED25519PrivateKeyResource(t *testing.T
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_key_algorithm", "ED25519"),
+					testCheckAttrBase64Of("tls_locally_signed_cert.test", "cert_pem_base64", "cert_pem"),
+					testCheckAttrBase64URLOf("tls_locally_signed_cert.test", "cert_pem_base64url", "cert_pem_base64"),
+					testCheckAttrContentHashOf("tls_locally_signed_cert.test", "content_hash", "cert_pem"),
+					testCheckPEMCertificateThumbprints("tls_locally_signed_cert.test", "cert_pem"),
 					testCheckPEMFormat("tls_locally_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
@@ -359,6 +397,7 @@ func TestAccResourceLocallySignedCert_FromECDSAPrivateKeyResource(t *testing.T)
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_key_algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_ecdsa_curve", "P224"),
 					testCheckPEMFormat("tls_locally_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
@@ -409,9 +448,1471 @@ func TestAccResourceLocallySignedCert_FromRSAPrivateKeyResource(t *testing.T) {
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_rsa_bits", "2048"),
 					testCheckPEMFormat("tls_locally_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
 		},
 	})
 }
+
+func TestAccResourceLocallySignedCert_CertificateSerialHex(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+						certificate_serial_hex = "0A1B2C3D4E5F"
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "id", "11111822610015"),
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						if got, want := fmt.Sprintf("%x", cert.SerialNumber), "a1b2c3d4e5f"; got != want {
+							return fmt.Errorf("incorrect serial number: expected %s, got %s", want, got)
+						}
+						return nil
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_CAValidityCheck(t *testing.T) {
+	oldNow := overridableTimeFunc
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		PreCheck:          setTimeForTest("2118-01-01T00:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config:      locallySignedCertConfig(1, 0),
+				ExpectError: regexp.MustCompile(`CA certificate is not valid at the current time`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+						skip_ca_validity_check = true
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: testCheckPEMFormat("tls_locally_signed_cert.test", "cert_pem", PreambleCertificate),
+			},
+		},
+	})
+	overridableTimeFunc = oldNow
+}
+
+func TestAccResourceLocallySignedCert_SignatureAlgorithm(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+						signature_algorithm = "SHA256-RSAPSS"
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						if cert.SignatureAlgorithm != x509.SHA256WithRSAPSS {
+							return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.SHA256WithRSAPSS, cert.SignatureAlgorithm)
+						}
+						return nil
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_UserPrincipalNames(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"client_auth",
+						]
+						user_principal_names = [
+							"user@example.com",
+							"other.user@example.net",
+						]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDNSNames("tls_locally_signed_cert.test", "cert_pem", []string{
+						"example.com",
+						"example.net",
+					}),
+					testCheckPEMCertificateIPAddresses("tls_locally_signed_cert.test", "cert_pem", []net.IP{
+						net.ParseIP("127.0.0.1"),
+						net.ParseIP("127.0.0.2"),
+					}),
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						var upns []string
+						for _, ext := range cert.Extensions {
+							if !ext.Id.Equal(oidExtensionSubjectAltName) {
+								continue
+							}
+
+							var names []asn1.RawValue
+							if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+								return fmt.Errorf("failed to unmarshal subject alternative names: %s", err)
+							}
+							for _, name := range names {
+								if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+									continue
+								}
+
+								var otherName upnOtherName
+								if _, err := asn1.UnmarshalWithParams(name.FullBytes, &otherName, "tag:0"); err != nil {
+									return fmt.Errorf("failed to unmarshal otherName: %s", err)
+								}
+								if !otherName.TypeID.Equal(oidOtherNameUPN) {
+									return fmt.Errorf("unexpected otherName type: %s", otherName.TypeID)
+								}
+								upns = append(upns, otherName.Value)
+							}
+						}
+
+						if !reflect.DeepEqual(upns, []string{"user@example.com", "other.user@example.net"}) {
+							return fmt.Errorf("incorrect user principal names: %v", upns)
+						}
+						return nil
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"client_auth",
+						]
+						user_principal_names = [
+							"user",
+						]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`expected user_principal_names to contain User Principal Names of the form 'user@domain', got "user"`),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_FromCertRequestEmailAddresses(t *testing.T) {
+	config := `
+		resource "tls_private_key" "test" {
+			algorithm = "ED25519"
+		}
+		resource "tls_cert_request" "test" {
+			private_key_pem = tls_private_key.test.private_key_pem
+			subject {
+				common_name = "example.com"
+			}
+			san {
+				type  = "dns"
+				value = "example.com"
+			}
+			san {
+				type  = "email"
+				value = "admin@example.com"
+			}
+		}
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem      = tls_cert_request.test.cert_request_pem
+			validity_period_hours = 1
+			allowed_uses = [
+				"client_auth",
+			]
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCACert, testCAPrivateKey, ""),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDNSNames("tls_locally_signed_cert.test", "cert_pem", []string{
+						"example.com",
+					}),
+					testCheckPEMCertificateEmailAddresses("tls_locally_signed_cert.test", "cert_pem", []string{
+						"admin@example.com",
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testCACert, testCAPrivateKey, `user_principal_names = ["user@example.com"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDNSNames("tls_locally_signed_cert.test", "cert_pem", []string{
+						"example.com",
+					}),
+					testCheckPEMCertificateEmailAddresses("tls_locally_signed_cert.test", "cert_pem", []string{
+						"admin@example.com",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_IntermediateCAChain(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "root" {
+						algorithm = "ED25519"
+					}
+					resource "tls_self_signed_cert" "root" {
+						private_key_pem = tls_private_key.root.private_key_pem
+						subject {
+							common_name = "root"
+						}
+						is_ca_certificate     = true
+						validity_period_hours = 8760
+						allowed_uses = [
+							"cert_signing",
+						]
+					}
+					resource "tls_private_key" "intermediate" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "intermediate" {
+						private_key_pem = tls_private_key.intermediate.private_key_pem
+						subject {
+							common_name = "intermediate"
+						}
+					}
+					resource "tls_locally_signed_cert" "intermediate" {
+						cert_request_pem      = tls_cert_request.intermediate.cert_request_pem
+						ca_cert_pem           = tls_self_signed_cert.root.cert_pem
+						ca_private_key_pem    = tls_private_key.root.private_key_pem
+						is_ca_certificate     = true
+						validity_period_hours = 8760
+						allowed_uses = [
+							"cert_signing",
+						]
+					}
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "test" {
+						private_key_pem = tls_private_key.test.private_key_pem
+						subject {
+							common_name = "leaf"
+						}
+					}
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem      = tls_cert_request.test.cert_request_pem
+						ca_cert_pem           = "${tls_locally_signed_cert.intermediate.cert_pem}${tls_self_signed_cert.root.cert_pem}"
+						ca_private_key_pem    = tls_private_key.intermediate.private_key_pem
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttrWith("tls_locally_signed_cert.test", "cert_chain_pem", func(value string) error {
+						chain, err := parseCertificatesPEM([]byte(value))
+						if err != nil {
+							return err
+						}
+
+						if len(chain) != 3 {
+							return fmt.Errorf("incorrect number of certificates in chain: expected 3, got %d", len(chain))
+						}
+						for i, cn := range []string{"leaf", "intermediate", "root"} {
+							if chain[i].Subject.CommonName != cn {
+								return fmt.Errorf("incorrect certificate at position %d: expected common name %q, got %q", i, cn, chain[i].Subject.CommonName)
+							}
+						}
+						for i := 0; i < len(chain)-1; i++ {
+							if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+								return fmt.Errorf("certificate at position %d is not signed by the next one: %s", i, err)
+							}
+						}
+						if !bytes.Equal(chain[0].AuthorityKeyId, chain[1].SubjectKeyId) {
+							return fmt.Errorf("authority key identifier doesn't match the intermediate CA subject key identifier")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_InvalidCACertPEM(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = []
+						ca_cert_pem = <<EOT
+%s
+not a certificate
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile("invalid ca_cert_pem: failed to decode PEM block"),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_MismatchedCAPrivateKey(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = []
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile("the public key of ca_private_key_pem doesn't match the public key of\\s+ca_cert_pem"),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_SubjectInfoAccess(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+						subject_info_access {
+							access_method = "1.3.6.1.5.5.7.48.5"
+							url           = "ldap://ldap.example.com/cn=ca,dc=example,dc=com"
+						}
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateSubjectInfoAccess("tls_locally_signed_cert.test", "cert_pem", map[string]string{
+						"1.3.6.1.5.5.7.48.5": "ldap://ldap.example.com/cn=ca,dc=example,dc=com",
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_AuthorityCertIssuerAndSerial(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses = [
+							"server_auth",
+						]
+						set_authority_cert_issuer_and_serial = true
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						caCerts, err := parseCertificatesPEM([]byte(testCACert))
+						if err != nil {
+							return err
+						}
+						caCert := caCerts[0]
+
+						for _, ext := range cert.Extensions {
+							if !ext.Id.Equal(oidExtensionAuthorityKeyID) {
+								continue
+							}
+
+							var aki authorityKeyIdentifier
+							if _, err := asn1.Unmarshal(ext.Value, &aki); err != nil {
+								return fmt.Errorf("failed to unmarshal authority key identifier: %s", err)
+							}
+							if !bytes.Equal(aki.KeyIdentifier, caCert.SubjectKeyId) {
+								return fmt.Errorf("incorrect key identifier: expected %x, got %x", caCert.SubjectKeyId, aki.KeyIdentifier)
+							}
+							if len(aki.AuthorityCertIssuer) != 1 || aki.AuthorityCertIssuer[0].Tag != 4 || !bytes.Equal(aki.AuthorityCertIssuer[0].Bytes, caCert.RawIssuer) {
+								return fmt.Errorf("incorrect authority certificate issuer: %v", aki.AuthorityCertIssuer)
+							}
+							if aki.AuthorityCertSerialNumber == nil || aki.AuthorityCertSerialNumber.Cmp(caCert.SerialNumber) != 0 {
+								return fmt.Errorf("incorrect authority certificate serial number: expected %s, got %s", caCert.SerialNumber, aki.AuthorityCertSerialNumber)
+							}
+							return nil
+						}
+
+						return fmt.Errorf("authority key identifier extension not found")
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_BasicConstraintsCritical(t *testing.T) {
+	config := `
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			allowed_uses          = ["server_auth"]
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCertRequest, testCACert, testCAPrivateKey, ""),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateBasicConstraints("tls_locally_signed_cert.test", "cert_pem", false, false),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, testCACert, testCAPrivateKey, "basic_constraints_critical = true"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateBasicConstraints("tls_locally_signed_cert.test", "cert_pem", false, true),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, testCACert, testCAPrivateKey, "is_ca_certificate = true"),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_locally_signed_cert.test", "cert_pem", true, true),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, testCACert, testCAPrivateKey, "is_ca_certificate = true\nbasic_constraints_critical = false"),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_locally_signed_cert.test", "cert_pem", true, false),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_SerialNumberFile(t *testing.T) {
+	serialNumberFile := filepath.Join(t.TempDir(), "ca.srl")
+	if err := os.WriteFile(serialNumberFile, []byte("0F\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidSerialNumberFile := filepath.Join(t.TempDir(), "invalid.srl")
+	if err := os.WriteFile(invalidSerialNumberFile, []byte("not a serial\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+		resource "tls_locally_signed_cert" "%s" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			allowed_uses          = ["server_auth"]
+			serial_number_file    = %q
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+		}
+	`
+	testCheckSerialNumberFile := func(expected string) r.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			content, err := os.ReadFile(serialNumberFile)
+			if err != nil {
+				return err
+			}
+			if string(content) != expected {
+				return fmt.Errorf("incorrect serial number file content: expected %q, got %q", expected, content)
+			}
+			return nil
+		}
+	}
+
+	configFirst := fmt.Sprintf(config, "first", testCertRequest, serialNumberFile, testCACert, testCAPrivateKey)
+	configSecond := fmt.Sprintf(config, "second", testCertRequest, serialNumberFile, testCACert, testCAPrivateKey)
+	configThird := fmt.Sprintf(config, "third", testCertRequest, serialNumberFile, testCACert, testCAPrivateKey)
+
+	// Failing at apply time, before signing: rounding the validity down to a whole day leaves none
+	configFailing := fmt.Sprintf(`
+		resource "tls_locally_signed_cert" "failing" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			not_before            = "2020-01-01T00:30:00Z"
+			round_validity_to_day = true
+			allowed_uses          = ["server_auth"]
+			serial_number_file    = %q
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+		}
+	`, testCertRequest, serialNumberFile, testCACert, testCAPrivateKey)
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: configFirst,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.first", "id", "16"),
+					testCheckSerialNumberFile("10\n"),
+				),
+			},
+			{
+				Config: configFirst + configSecond,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.first", "id", "16"),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.second", "id", "17"),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.second", "cert_pem", []byte(testCACert)),
+					testCheckSerialNumberFile("11\n"),
+				),
+			},
+			{
+				Config:      configFirst + configSecond + configFailing,
+				ExpectError: regexp.MustCompile(`round_validity_to_day: the validity period doesn't include the end of any day`),
+			},
+			{
+				// The failed apply didn't consume a serial number
+				Config: configFirst + configSecond + configThird,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.third", "id", "18"),
+					testCheckSerialNumberFile("12\n"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, "invalid", testCertRequest, invalidSerialNumberFile, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`failed to assign serial number from serial_number_file: invalid serial number\s+file`),
+			},
+		},
+	})
+}
+
+// TestNextSerialNumberFromFile_ConcurrentProcesses runs the test binary itself in multiple processes,
+// all assigning serial numbers from the same file: none can be assigned twice.
+func TestNextSerialNumberFromFile_ConcurrentProcesses(t *testing.T) {
+	const processes, serialsPerProcess = 4, 25
+
+	if path := os.Getenv("TLS_TEST_SERIAL_NUMBER_FILE"); path != "" {
+		for i := 0; i < serialsPerProcess; i++ {
+			if _, err := nextSerialNumberFromFile(path); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return
+	}
+
+	serialNumberFile := filepath.Join(t.TempDir(), "ca.srl")
+	cmds := make([]*exec.Cmd, processes)
+	for i := range cmds {
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^TestNextSerialNumberFromFile_ConcurrentProcesses$")
+		cmds[i].Env = append(os.Environ(), "TLS_TEST_SERIAL_NUMBER_FILE="+serialNumberFile)
+		if err := cmds[i].Start(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(serialNumberFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := fmt.Sprintf("%X\n", processes*serialsPerProcess); string(content) != expected {
+		t.Errorf("incorrect serial number file content: expected %q, got %q", expected, content)
+	}
+}
+
+func TestAccResourceLocallySignedCert_CrossSignedIntermediateWithNameConstraints(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "intermediate" {
+						algorithm = "ECDSA"
+					}
+					resource "tls_cert_request" "intermediate" {
+						private_key_pem = tls_private_key.intermediate.private_key_pem
+						subject {
+							common_name  = "Cross-signed Intermediate CA"
+							organization = "Example, Inc"
+						}
+						dns_names = ["ca.example.com"]
+						uris      = ["spiffe://example.com/ca"]
+					}
+					resource "tls_locally_signed_cert" "intermediate" {
+						cert_request_pem      = tls_cert_request.intermediate.cert_request_pem
+						is_ca_certificate     = true
+						validity_period_hours = 1
+						allowed_uses          = ["cert_signing", "crl_signing"]
+						user_principal_names  = ["ca@example.com"]
+						name_constraints {
+							permitted_dns_domains     = ["example.com"]
+							excluded_dns_domains      = ["internal.example.com"]
+							permitted_ip_ranges       = ["10.0.0.0/8"]
+							excluded_ip_ranges        = ["10.1.0.0/16"]
+							permitted_email_addresses = ["example.com"]
+							permitted_uri_domains     = ["example.com"]
+						}
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateBasicConstraints("tls_locally_signed_cert.intermediate", "cert_pem", true, true),
+					testCheckPEMCertificateDNSNames("tls_locally_signed_cert.intermediate", "cert_pem", []string{"ca.example.com"}),
+					testCheckPEMCertificateURIs("tls_locally_signed_cert.intermediate", "cert_pem", []*url.URL{
+						{Scheme: "spiffe", Host: "example.com", Path: "/ca"},
+					}),
+					testCheckPEMCertificateWith("tls_locally_signed_cert.intermediate", "cert_pem", func(cert *x509.Certificate) error {
+						if !cert.PermittedDNSDomainsCritical {
+							return fmt.Errorf("name constraints extension is not critical")
+						}
+						if !reflect.DeepEqual(cert.PermittedDNSDomains, []string{"example.com"}) ||
+							!reflect.DeepEqual(cert.ExcludedDNSDomains, []string{"internal.example.com"}) {
+							return fmt.Errorf("incorrect DNS name constraints: permitted %v, excluded %v", cert.PermittedDNSDomains, cert.ExcludedDNSDomains)
+						}
+						if len(cert.PermittedIPRanges) != 1 || cert.PermittedIPRanges[0].String() != "10.0.0.0/8" ||
+							len(cert.ExcludedIPRanges) != 1 || cert.ExcludedIPRanges[0].String() != "10.1.0.0/16" {
+							return fmt.Errorf("incorrect IP name constraints: permitted %v, excluded %v", cert.PermittedIPRanges, cert.ExcludedIPRanges)
+						}
+						if !reflect.DeepEqual(cert.PermittedEmailAddresses, []string{"example.com"}) {
+							return fmt.Errorf("incorrect email name constraints: permitted %v", cert.PermittedEmailAddresses)
+						}
+						if !reflect.DeepEqual(cert.PermittedURIDomains, []string{"example.com"}) {
+							return fmt.Errorf("incorrect URI name constraints: permitted %v", cert.PermittedURIDomains)
+						}
+						if len(cert.SubjectKeyId) == 0 || len(cert.AuthorityKeyId) == 0 {
+							return fmt.Errorf("expected subject and authority key identifiers, got %x and %x", cert.SubjectKeyId, cert.AuthorityKeyId)
+						}
+						return nil
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.intermediate", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						name_constraints {
+							permitted_dns_domains = ["example.com"]
+						}
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile("name_constraints can only be set when is_ca_certificate is true"),
+			},
+		},
+	})
+}
+
+func TestNameConstraintsDNSViolations(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cert     *x509.Certificate
+		expected []string
+	}{
+		{
+			name: "no constraints",
+			cert: &x509.Certificate{DNSNames: []string{"ca.example.com"}},
+		},
+		{
+			name: "permitted",
+			cert: &x509.Certificate{
+				DNSNames:            []string{"example.com", "ca.EXAMPLE.com"},
+				PermittedDNSDomains: []string{"example.com"},
+				ExcludedDNSDomains:  []string{"internal.example.com"},
+			},
+		},
+		{
+			name: "not permitted",
+			cert: &x509.Certificate{
+				DNSNames:            []string{"ca.example.com", "ca.example.net", "notexample.com"},
+				PermittedDNSDomains: []string{"example.com"},
+			},
+			expected: []string{
+				`"ca.example.net" is not permitted by any of ["example.com"]`,
+				`"notexample.com" is not permitted by any of ["example.com"]`,
+			},
+		},
+		{
+			name: "subdomains only",
+			cert: &x509.Certificate{
+				DNSNames:            []string{"example.com", "ca.example.com"},
+				PermittedDNSDomains: []string{".example.com"},
+			},
+			expected: []string{`"example.com" is not permitted by any of [".example.com"]`},
+		},
+		{
+			name: "excluded",
+			cert: &x509.Certificate{
+				DNSNames:            []string{"ca.example.com", "ca.internal.example.com"},
+				PermittedDNSDomains: []string{"example.com"},
+				ExcludedDNSDomains:  []string{"internal.example.com"},
+			},
+			expected: []string{`"ca.internal.example.com" is excluded by "internal.example.com"`},
+		},
+	} {
+		if actual := nameConstraintsDNSViolations(tc.cert); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected violations %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccResourceLocallySignedCert_Admission(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["client_auth"]
+						admission {
+							admission_authority_url = "https://chamber.example.com"
+							profession_info {
+								profession_items    = ["Ärztin/Arzt"]
+								profession_oids     = ["1.2.276.0.76.4.30"]
+								registration_number = "1-2-ARZT-1234"
+							}
+							profession_info {
+								profession_items = ["Zahnärztin/Zahnarzt", "Dentist"]
+							}
+						}
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						for _, ext := range cert.Extensions {
+							if !ext.Id.Equal(oidExtensionAdmission) {
+								continue
+							}
+
+							var actual admissionSyntax
+							if _, err := asn1.Unmarshal(ext.Value, &actual); err != nil {
+								return fmt.Errorf("failed to unmarshal admission: %s", err)
+							}
+
+							if string(actual.AdmissionAuthority.Bytes) != "https://chamber.example.com" {
+								return fmt.Errorf("incorrect admission authority: %q", actual.AdmissionAuthority.Bytes)
+							}
+
+							expected := []admissions{{ProfessionInfos: []professionInfo{
+								{
+									ProfessionItems:    []string{"Ärztin/Arzt"},
+									ProfessionOIDs:     []asn1.ObjectIdentifier{{1, 2, 276, 0, 76, 4, 30}},
+									RegistrationNumber: "1-2-ARZT-1234",
+								},
+								{
+									ProfessionItems: []string{"Zahnärztin/Zahnarzt", "Dentist"},
+								},
+							}}}
+							if !reflect.DeepEqual(expected, actual.ContentsOfAdmissions) {
+								return fmt.Errorf("incorrect admissions: expected %v, got %v", expected, actual.ContentsOfAdmissions)
+							}
+							return nil
+						}
+						return fmt.Errorf("admission extension not found")
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["client_auth"]
+						admission {
+							profession_info {
+								profession_items    = ["Ärztin/Arzt"]
+								registration_number = "Nr. #1234"
+							}
+						}
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile("must be a PrintableString of at most 128 characters"),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_CAPrivateKeyPEMFile(t *testing.T) {
+	caPrivateKeyFile := filepath.Join(t.TempDir(), "ca_private_key.pem")
+	if err := os.WriteFile(caPrivateKeyFile, []byte(testCAPrivateKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours   = 1
+			allowed_uses            = ["server_auth"]
+			ca_private_key_pem_file = %q
+			ca_cert_pem = <<EOT
+%s
+EOT
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCertRequest, caPrivateKeyFile, testCACert),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_private_key_pem_file", caPrivateKeyFile),
+					r.TestCheckNoResourceAttr("tls_locally_signed_cert.test", "ca_private_key_pem"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, testCertRequest, filepath.Join(t.TempDir(), "missing.pem"), testCACert),
+				ExpectError: regexp.MustCompile(`failed to read ca_private_key_pem_file`),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_UniqueIDs(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						issuer_unique_id      = "0102"
+						subject_unique_id     = "0304"
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateUniqueIDs("tls_locally_signed_cert.test", "cert_pem", []byte{0x01, 0x02}, []byte{0x03, 0x04}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_MinimalProfile(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["digital_signature", "server_auth"]
+						minimal_profile       = true
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateExtensions("tls_locally_signed_cert.test", "cert_pem", []string{
+						"key_usage",
+						"extended_key_usage",
+						"subject_alt_name",
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_Logotype(t *testing.T) {
+	config := `
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			allowed_uses          = ["server_auth"]
+			logotype {
+				%s
+				media_type        = "image/svg+xml"
+				image_url         = "https://example.com/logo.svg"
+				image_hash_sha256 = %q
+			}
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+		}
+	`
+	imageHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	testCheckLogotype := func(expectedTag int) r.TestCheckFunc {
+		return testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+			for _, ext := range cert.Extensions {
+				if !ext.Id.Equal(oidExtensionLogotype) {
+					continue
+				}
+
+				var logotypeExtn struct {
+					Logo asn1.RawValue
+				}
+				if _, err := asn1.Unmarshal(ext.Value, &logotypeExtn); err != nil {
+					return fmt.Errorf("failed to unmarshal logotype: %s", err)
+				}
+				if logotypeExtn.Logo.Tag != expectedTag {
+					return fmt.Errorf("incorrect logotype tag: expected %d, got %d", expectedTag, logotypeExtn.Logo.Tag)
+				}
+
+				var data logotypeData
+				if _, err := asn1.UnmarshalWithParams(logotypeExtn.Logo.Bytes, &data, "tag:0"); err != nil {
+					return fmt.Errorf("failed to unmarshal logotype data: %s", err)
+				}
+				if len(data.Image) != 1 {
+					return fmt.Errorf("incorrect number of logotype images: %d", len(data.Image))
+				}
+
+				details := data.Image[0].ImageDetails
+				if details.MediaType != "image/svg+xml" {
+					return fmt.Errorf("incorrect logotype media type: %q", details.MediaType)
+				}
+				if len(details.LogotypeHash) != 1 || !details.LogotypeHash[0].HashAlg.Algorithm.Equal(oidHashSHA256) || hex.EncodeToString(details.LogotypeHash[0].HashValue) != imageHash {
+					return fmt.Errorf("incorrect logotype hash: %v", details.LogotypeHash)
+				}
+				if len(details.LogotypeURI) != 1 || details.LogotypeURI[0].Tag != asn1.TagIA5String || string(details.LogotypeURI[0].Bytes) != "https://example.com/logo.svg" {
+					return fmt.Errorf("incorrect logotype URI: %v", details.LogotypeURI)
+				}
+				return nil
+			}
+			return fmt.Errorf("logotype extension not found")
+		})
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCertRequest, "", imageHash, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckLogotype(2),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, `type = "issuer"`, imageHash, testCACert, testCAPrivateKey),
+				Check:  testCheckLogotype(1),
+			},
+			{
+				Config:      fmt.Sprintf(config, testCertRequest, "", "not-a-hash", testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile("must be a SHA256 hash, in hexadecimal"),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_SMIMECapabilities(t *testing.T) {
+	config := `
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			allowed_uses          = ["digital_signature", "key_encipherment", "email_protection"]
+			%s
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCertRequest, `
+					smime_capabilities {
+						capability_id = "2.16.840.1.101.3.4.1.42"
+					}
+					smime_capabilities {
+						capability_id     = "1.2.840.113549.3.2"
+						parameters_base64 = "AgEo"
+					}
+				`, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						for _, ext := range cert.Extensions {
+							if !ext.Id.Equal(oidExtensionSMIMECapabilities) {
+								continue
+							}
+
+							// SEQUENCE { SEQUENCE { aes256-CBC }, SEQUENCE { rc2-CBC, INTEGER 40 } }
+							if expected := "301c300b060960864801650304012a300d06082a864886f70d0302020128"; hex.EncodeToString(ext.Value) != expected {
+								return fmt.Errorf("incorrect S/MIME capabilities: expected %s, got %x", expected, ext.Value)
+							}
+							return nil
+						}
+						return fmt.Errorf("S/MIME capabilities extension not found")
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, `
+					smime_capabilities {
+						capability_id = "not-an-oid"
+					}
+				`, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`invalid capability_id: "not-an-oid" is not a valid object identifier`),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, `
+					smime_capabilities {
+						capability_id     = "1.2.840.113549.3.2"
+						parameters_base64 = "AgEoAA=="
+					}
+				`, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile("invalid smime_capabilities.0.parameters_base64: trailing data after the ASN.1 value"),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_WithinCAValidity(t *testing.T) {
+	config := func(extra string) string {
+		return fmt.Sprintf(`
+			resource "tls_locally_signed_cert" "test" {
+				cert_request_pem = <<EOT
+%s
+EOT
+				allowed_uses = ["server_auth"]
+				%s
+				ca_cert_pem = <<EOT
+%s
+EOT
+				ca_private_key_pem = <<EOT
+%s
+EOT
+			}
+		`, testCertRequest, extra, testCACert, testCAPrivateKey)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(`validity_period_hours = 1`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "within_ca_validity", "true"),
+					r.TestMatchResourceAttr("tls_locally_signed_cert.test", "issuance_duration_ms", regexp.MustCompile(`^\d+$`)),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_validity_overhang_hours", "0"),
+				),
+			},
+			{
+				// The CA certificate is valid from 2017-10-16T19:22:09Z
+				Config: config(`
+					not_before            = "2017-10-16T18:22:09Z"
+					validity_period_hours = 2
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "within_ca_validity", "false"),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_validity_overhang_hours", "1"),
+				),
+			},
+			{
+				// The CA certificate is valid until 2117-10-16T19:22:09Z
+				Config: config(`
+					not_before            = "2117-10-16T18:00:00Z"
+					validity_period_hours = 2
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "within_ca_validity", "false"),
+					r.TestCheckResourceAttr("tls_locally_signed_cert.test", "ca_validity_overhang_hours", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_SubjectPublicKeyPEM(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						subject_public_key_pem = <<EOT
+%s
+EOT
+						subject {
+							common_name  = "example.com"
+							organization = "Example, Inc"
+						}
+						dns_names             = ["example.com", "www.example.com"]
+						ip_addresses          = ["127.0.0.1"]
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPublicKeyPEM, testCACert, testCAPrivateKey),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateSubject("tls_locally_signed_cert.test", "cert_pem", &pkix.Name{
+						CommonName:   "example.com",
+						Organization: []string{"Example, Inc"},
+					}),
+					testCheckPEMCertificateDNSNames("tls_locally_signed_cert.test", "cert_pem", []string{"example.com", "www.example.com"}),
+					testCheckPEMCertificateIPAddresses("tls_locally_signed_cert.test", "cert_pem", []net.IP{net.ParseIP("127.0.0.1")}),
+					testCheckPEMCertificateWith("tls_locally_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						_, pubKeyPEM, err := publicKeyToPEM(cert.PublicKey)
+						if err != nil {
+							return err
+						}
+						if pubKeyPEM != strings.TrimSpace(testPublicKeyPEM)+"\n" {
+							return fmt.Errorf("certified public key doesn't match subject_public_key_pem: got %s", pubKeyPEM)
+						}
+						return nil
+					}),
+					testCheckPEMCertificateAgainstPEMRootCA("tls_locally_signed_cert.test", "cert_pem", []byte(testCACert)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						subject_public_key_pem = <<EOT
+%s
+EOT
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPublicKeyPEM, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`"subject_public_key_pem": only one of\s+` + "`cert_request_pem,subject_public_key_pem`" + `\s+can be specified`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						dns_names             = ["example.com"]
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`"cert_request_pem": conflicts with dns_names`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_locally_signed_cert" "test" {
+						subject_public_key_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						ca_cert_pem = <<EOT
+%s
+EOT
+						ca_private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`invalid subject_public_key_pem: expected a "PUBLIC KEY" PEM block`),
+			},
+		},
+	})
+}
+
+func TestAccResourceLocallySignedCert_EnforceUsagePolicy(t *testing.T) {
+	config := `
+		provider "tls" {
+			enforce_usage_policy {
+				allowed_usages {
+					usages = ["digital_signature", "key_encipherment", "server_auth", "client_auth"]
+				}
+				allowed_usages {
+					is_ca_certificate = true
+					usages            = ["cert_signing", "crl_signing"]
+				}
+			}
+		}
+		resource "tls_locally_signed_cert" "test" {
+			cert_request_pem = <<EOT
+%s
+EOT
+			validity_period_hours = 1
+			is_ca_certificate     = %t
+			allowed_uses          = %s
+			ca_cert_pem = <<EOT
+%s
+EOT
+			ca_private_key_pem = <<EOT
+%s
+EOT
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testCertRequest, false, `["digital_signature", "server_auth"]`, testCACert, testCAPrivateKey),
+				Check:  testCheckPEMCertificateKeyUsage("tls_locally_signed_cert.test", "cert_pem", x509.KeyUsageDigitalSignature),
+			},
+			{
+				Config:      fmt.Sprintf(config, testCertRequest, false, `["digital_signature", "cert_signing"]`, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`usages \[cert_signing, digital_signature\] of leaf certificate are not allowed\s+by the provider enforce_usage_policy`),
+			},
+			{
+				Config: fmt.Sprintf(config, testCertRequest, true, `["cert_signing", "crl_signing"]`, testCACert, testCAPrivateKey),
+				Check:  testCheckPEMCertificateKeyUsage("tls_locally_signed_cert.test", "cert_pem", x509.KeyUsageCertSign|x509.KeyUsageCRLSign),
+			},
+			{
+				Config:      fmt.Sprintf(config, testCertRequest, true, `["cert_signing", "server_auth"]`, testCACert, testCAPrivateKey),
+				ExpectError: regexp.MustCompile(`usages \[cert_signing, server_auth\] of CA certificate are not allowed\s+by the provider enforce_usage_policy`),
+			},
+		},
+	})
+}
+
+func TestParseOID(t *testing.T) {
+	testCases := map[string]struct {
+		oid       string
+		expected  asn1.ObjectIdentifier
+		expectErr bool
+	}{
+		"OCSP":              {oid: "1.3.6.1.5.5.7.48.1", expected: asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1}},
+		"first arc 0":       {oid: "0.39", expected: asn1.ObjectIdentifier{0, 39}},
+		"first arc 2":       {oid: "2.999.1", expected: asn1.ObjectIdentifier{2, 999, 1}},
+		"single arc":        {oid: "1", expectErr: true},
+		"not a number":      {oid: "1.3.x", expectErr: true},
+		"negative":          {oid: "1.3.-6", expectErr: true},
+		"first arc above 2": {oid: "5.1", expectErr: true},
+		"second arc 40":     {oid: "1.40", expectErr: true},
+		"second arc 0.40":   {oid: "0.40.1", expectErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			oid, err := parseOID(tc.oid)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", oid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !oid.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, oid)
+			}
+			if _, err := asn1.Marshal(oid); err != nil {
+				t.Errorf("unexpected error marshalling %v: %v", oid, err)
+			}
+		})
+	}
+}
@@ -2,25 +2,34 @@ package provider
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"crypto/ed25519"
-	"crypto/rsa"
-	"crypto/x509"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/pem"
+	"fmt"
+	"os"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/terraform-providers/terraform-provider-tls/internal/openssh"
 )
 
+// defaultRSABits is the size of the generated RSA keys, when neither
+// the resource nor the provider configuration specify one.
+const defaultRSABits = 2048
+
 func resourcePrivateKey() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: createResourcePrivateKey,
 		DeleteContext: deleteResourcePrivateKey,
 		ReadContext:   readResourcePrivateKey,
 
+		CustomizeDiff: customdiff.All(customizePrivateKeyDiff, customizeKeyAlgorithmDiff(""), customizeFIPSKeyDiff(""), customizeEntropySourceDiff),
+
 		Description: "Creates a PEM (and OpenSSH) formatted private key.\n\n" +
 			"Generates a secure private key and encodes it in " +
 			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) and " +
@@ -30,31 +39,112 @@ func resourcePrivateKey() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"algorithm": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedAlgorithmsStr(), false)),
 				Description: "Name of the algorithm to use when generating the private key. " +
-					"Currently-supported values are `RSA`, `ECDSA` and `ED25519`.",
+					"Currently-supported values are `RSA`, `ECDSA` and `ED25519`. " +
+					"If not set, the algorithm of `key_profile` is used, and otherwise the provider `default_key_algorithm`: " +
+					"one of the three must be set.",
+			},
+
+			"key_profile": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedKeyProfilesStr(), false)),
+				Description: "Intended use of the private key, selecting a sensible algorithm and parameters " +
+					"instead of setting `algorithm`: " +
+					"`modern` generates an `ED25519` key, " +
+					"`fips` generates an `ECDSA` key with curve `P384`, approved by [FIPS 186-4](https://csrc.nist.gov/publications/detail/fips/186/4/final), " +
+					"and `legacy-compat` generates an `RSA` key of `2048` bits, for clients that support nothing else. " +
+					"If `algorithm` is set, it overrides the whole profile; otherwise, " +
+					"`rsa_bits` and `ecdsa_curve` override the matching parameter of the profile.",
 			},
 
 			"rsa_bits": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				ForceNew:    true,
-				Default:     2048,
-				Description: "When `algorithm` is `RSA`, the size of the generated RSA key, in bits (default: `2048`).",
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Description: "When `algorithm` is `RSA`, the size of the generated RSA key, in bits. " +
+					"If not set, the size of the `key_profile` is used, if any, " +
+					"and otherwise the provider `default_rsa_bits` (default: `2048`).",
 			},
 
 			"ecdsa_curve": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
-				Default:          P224,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedECDSACurvesStr(), false)),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedECDSACurvesAndAliasesStr(), false)),
+				StateFunc: func(v interface{}) string {
+					return NormalizeECDSACurve(v.(string)).String()
+				},
 				Description: "When `algorithm` is `ECDSA`, the name of the elliptic curve to use. " +
-					"Currently-supported values are `P224`, `P256`, `P384` or `P521` (default: `P224`).",
+					"Currently-supported values are `P224`, `P256`, `P384` or `P521`. " +
+					"If not set, the curve of the `key_profile` is used, if any (default: `P224`). " +
+					"The key sizes `224`, `256`, `384` and `521` are also accepted as aliases, and normalized to the curve name.",
+			},
+
+			"private_key_format": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedPrivateKeyFormatsStr(), false)),
+				Description: "Structure of the private key encoded in `private_key_pem`: " +
+					"`pkcs1` ([PKCS #1 (RFC 8017)](https://datatracker.ietf.org/doc/html/rfc8017#appendix-A.1.2), only for `RSA` keys), " +
+					"`sec1` ([SEC 1 (RFC 5915)](https://datatracker.ietf.org/doc/html/rfc5915#section-3), only for `ECDSA` keys) or " +
+					"`pkcs8` ([PKCS #8 (RFC 5208)](https://datatracker.ietf.org/doc/html/rfc5208#section-5), for all keys). " +
+					"If not set, `pkcs1` is used for `RSA` keys, `sec1` for `ECDSA` keys and `pkcs8` for `ED25519` keys. " +
+					"The PEM preamble of `private_key_pem` matches the format: " +
+					"`RSA PRIVATE KEY` for `pkcs1`, `EC PRIVATE KEY` for `sec1` and `PRIVATE KEY` for `pkcs8`.",
+			},
+
+			"entropy_source": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotWhiteSpace),
+				Description: "Path of a character device or named pipe to read the randomness used to generate the key from, " +
+					"instead of the operating system's cryptographically secure random number generator, " +
+					"for example a hardware random number generator exposed as `/dev/hwrng`. " +
+					"Only `ED25519` keys are supported, generated from the first 32 bytes read from the source: " +
+					"the `RSA` and `ECDSA` implementations of the Go standard library do not (reliably) generate keys " +
+					"from a given source of randomness, so setting this with those algorithms is an error. " +
+					"The source is read on the machine running `terraform apply`, only when the key is generated, " +
+					"and only its path is stored in the state. " +
+					"**NOTE**: the security of the generated key depends entirely on the quality of this source: " +
+					"a predictable or biased source produces keys that can be recovered by an attacker. " +
+					"Regular files are rejected, as they would provide the same randomness every time they are read. " +
+					"Only set this when required by a policy, and to a source that is at least as trustworthy as the default.",
+			},
+
+			"ephemeral": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+				Description: "**Experimental**: when `true`, the private key is written once to `private_key_file` " +
+					"and never stored in the Terraform state: only the public key and its fingerprints are. " +
+					"The `private_key_*` attributes are left empty, so the private key cannot be referenced " +
+					"by other resources, and it cannot be recovered if the file is lost (default: `false`).",
+			},
+
+			"private_key_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Description: "Path of the file the private key is written to, in " +
+					"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format and with `0600` permissions, " +
+					"when `ephemeral` is `true`. The file is written on the machine running `terraform apply`, " +
+					"only when the key is generated: it is neither recreated if removed, nor deleted when the resource is destroyed.",
 			},
 
+			"write_to": writeToSchema("private_key_pem", "0600"),
+
 			"private_key_pem": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -62,6 +152,41 @@ func resourcePrivateKey() *schema.Resource {
 				Description: "Private key data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
 			},
 
+			"private_key_pem_base64": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The whole `private_key_pem` (i.e. the PEM text, not the DER data) encoded in base64, on a single line. " +
+					"This is useful to inject the private key into environment variables or Kubernetes secrets.",
+			},
+
+			"private_key_pem_base64url": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The whole `private_key_pem` encoded in base64url without padding " +
+					"([RFC 4648 §5](https://datatracker.ietf.org/doc/html/rfc4648#section-5)), on a single line: " +
+					"this is the same as `private_key_pem_base64`, but safe to embed in URLs and JWTs.",
+			},
+
+			"private_key_raw_base64": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The raw private key material encoded in base64: the 32 bytes seed for `ED25519` keys, " +
+					"and the private scalar (big-endian, padded to the size of the curve) for `ECDSA` keys. " +
+					"This is empty for `RSA` keys, as they have no such raw form.",
+			},
+
+			"private_key_raw_base64url": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "The raw private key material encoded in base64url without padding " +
+					"([RFC 4648 §5](https://datatracker.ietf.org/doc/html/rfc4648#section-5)): " +
+					"this is the same as `private_key_raw_base64`, in the encoding used by JSON Web Keys (e.g. for the `d` parameter).",
+			},
+
 			"private_key_openssh": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -69,6 +194,32 @@ func resourcePrivateKey() *schema.Resource {
 				Description: "Private key data in [OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format.",
 			},
 
+			"openssh_passphrase": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+				StateFunc: func(v interface{}) string {
+					return hashForState(v.(string))
+				},
+				Description: "Passphrase to encrypt the private key with, in `private_key_openssh_encrypted`. " +
+					"Only an irreversible secure hash of the passphrase will be stored in the Terraform state.",
+			},
+
+			"openssh_comment": opensshCommentSchema(true,
+				"It is also embedded in `private_key_openssh` and `private_key_openssh_encrypted`, as `ssh-keygen -C` does."),
+
+			"private_key_openssh_encrypted": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: "Private key data in [OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format, " +
+					"encrypted with `openssh_passphrase` (using `bcrypt` as key derivation function and `aes256-ctr` as cipher, " +
+					"like `ssh-keygen` does). Only available if `openssh_passphrase` is set, and if the selected private key format " +
+					"is compatible, similarly to `public_key_openssh` and the [ECDSA P224 limitations](../../docs#limitations).",
+			},
+
 			"public_key_pem": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -80,6 +231,29 @@ func resourcePrivateKey() *schema.Resource {
 					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
 			},
 
+			"public_key_der_base64": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The public key as a `SubjectPublicKeyInfo` structure in DER format, encoded in base64: " +
+					"this is the same data as `public_key_pem`, for consumers that can't parse PEM.",
+			},
+
+			"public_key_raw_base64": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The raw public key material encoded in base64: the 32 bytes public key for `ED25519` keys, " +
+					"and the uncompressed elliptic curve point (i.e. `0x04 || X || Y`) for `ECDSA` keys. " +
+					"This is empty for `RSA` keys, as they have no such raw form.",
+			},
+
+			"public_key_raw_base64url": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The raw public key material encoded in base64url without padding " +
+					"([RFC 4648 §5](https://datatracker.ietf.org/doc/html/rfc4648#section-5)): " +
+					"this is the same as `public_key_raw_base64`, in the encoding used by JSON Web Keys (e.g. for the `x` parameter of `ED25519` keys).",
+			},
+
 			"public_key_openssh": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -95,6 +269,16 @@ func resourcePrivateKey() *schema.Resource {
 					"[`trimspace()`](https://www.terraform.io/language/functions/trimspace).",
 			},
 
+			"content_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Hexadecimal representation of the SHA256 checksum of the public key, " +
+					"in PKIX `SubjectPublicKeyInfo` DER format: no digest of the private key is stored in state. " +
+					"It only changes when a new key is generated, so it can be used to trigger " +
+					"other resources (e.g. via `replace_triggered_by`) only when the key actually changes. " +
+					"It is set also when `ephemeral` is `true`.",
+			},
+
 			"public_key_fingerprint_md5": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -111,6 +295,16 @@ func resourcePrivateKey() *schema.Resource {
 					"`public_key_openssh` and the [ECDSA P224 limitations](../../docs#limitations).",
 			},
 
+			"public_key_fingerprint_sha256_hex": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "The fingerprint of the public key data in OpenSSH SHA256 hash format, " +
+					"encoded as colon-separated hexadecimal, e.g. `aa:bb:cc:...`, " +
+					"instead of the base64 encoding of `public_key_fingerprint_sha256`. " +
+					"Only available if the selected private key format is compatible, similarly to " +
+					"`public_key_openssh` and the [ECDSA P224 limitations](../../docs#limitations).",
+			},
+
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -121,68 +315,276 @@ func resourcePrivateKey() *schema.Resource {
 	}
 }
 
-func createResourcePrivateKey(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	keyAlgoName := Algorithm(d.Get("algorithm").(string))
+// resolveKeyGenerator resolves the `algorithm`, `rsa_bits` and `ecdsa_curve` of the keys to generate,
+// falling back to the `key_profile` and then to the provider defaults, sets them on the given *schema.ResourceData
+// and returns the matching keyGenerator.
+func resolveKeyGenerator(d *schema.ResourceData, config *providerConfig) (Algorithm, keyGenerator, diag.Diagnostics) {
+	keyAlgoName, rsaBits, ecdsaCurve, _ := resolveKeyParameters(d.GetRawConfig(), config)
+	if keyAlgoName == "" {
+		return "", nil, diag.FromErr(errMissingKeyAlgorithm(""))
+	}
+	if config.fipsMode {
+		if err := checkFIPSKeyParameters(keyAlgoName, rsaBits, ecdsaCurve); err != nil {
+			return "", nil, diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("algorithm", keyAlgoName); err != nil {
+		return "", nil, diag.Errorf("error setting value on key 'algorithm': %s", err)
+	}
+	if err := d.Set("rsa_bits", rsaBits); err != nil {
+		return "", nil, diag.Errorf("error setting value on key 'rsa_bits': %s", err)
+	}
+	if err := d.Set("ecdsa_curve", ecdsaCurve.String()); err != nil {
+		return "", nil, diag.Errorf("error setting value on key 'ecdsa_curve': %s", err)
+	}
 
 	// Identify the correct (Private) Key Generator
-	var keyGen keyGenerator
-	var ok bool
-	if keyGen, ok = keyGenerators[keyAlgoName]; !ok {
-		return diag.Errorf("invalid key_algorithm %#v", keyAlgoName)
+	keyGen, ok := keyGenerators[keyAlgoName]
+	if !ok {
+		return "", nil, diag.Errorf("invalid key_algorithm %#v", keyAlgoName)
 	}
 
-	// Generate the new Key
-	key, err := keyGen(d)
-	if err != nil {
-		return diag.FromErr(err)
+	return keyAlgoName, keyGen, nil
+}
+
+// resolveKeyParameters resolves the algorithm, the RSA key size and the ECDSA curve of the keys to generate
+// from the given raw configuration, holding `algorithm`, `rsa_bits`, `ecdsa_curve` and optionally `key_profile`.
+// Each falls back to the key profile, then to the provider defaults and finally to the hardcoded defaults:
+// the algorithm is empty if it cannot be resolved at all.
+//
+// It returns false if any of the values needed to resolve them is not known yet (i.e. at plan time).
+func resolveKeyParameters(rawConfig cty.Value, config *providerConfig) (Algorithm, int, ECDSACurve, bool) {
+	getAttr := func(name string) cty.Value {
+		if rawConfig.IsNull() || !rawConfig.Type().HasAttribute(name) {
+			return cty.NullVal(cty.DynamicPseudoType)
+		}
+		return rawConfig.GetAttr(name)
 	}
+	algorithmV, profileV, rsaBitsV, ecdsaCurveV := getAttr("algorithm"), getAttr("key_profile"), getAttr("rsa_bits"), getAttr("ecdsa_curve")
+	known := algorithmV.IsKnown() && profileV.IsKnown() && rsaBitsV.IsKnown() && ecdsaCurveV.IsKnown()
 
-	// Marshal the Key in PEM block
-	var keyPemBlock *pem.Block
-	doMarshalOpenSSHKeyPemBlock := true
-	switch k := key.(type) {
-	case *rsa.PrivateKey:
-		keyPemBlock = &pem.Block{
-			Type:  PreamblePrivateKeyRSA.String(),
-			Bytes: x509.MarshalPKCS1PrivateKey(k),
+	// Resolve the key algorithm, falling back to the key profile and then to the provider default:
+	// an explicit algorithm overrides the whole profile
+	var profile keyProfile
+	var keyAlgoName Algorithm
+	if !algorithmV.IsNull() && algorithmV.IsKnown() {
+		keyAlgoName = Algorithm(algorithmV.AsString())
+	} else if !profileV.IsNull() && profileV.IsKnown() {
+		profile = keyProfiles[KeyProfile(profileV.AsString())]
+		keyAlgoName = profile.algorithm
+	}
+	if keyAlgoName == "" {
+		keyAlgoName = config.defaultKeyAlgorithm
+	}
+
+	// Resolve the RSA key size, falling back to the key profile, then to the provider default
+	// and finally to the hardcoded default
+	rsaBits := defaultRSABits
+	if !rsaBitsV.IsNull() && rsaBitsV.IsKnown() {
+		bits, _ := rsaBitsV.AsBigFloat().Int64()
+		rsaBits = int(bits)
+	} else if profile.rsaBits > 0 {
+		rsaBits = profile.rsaBits
+	} else if config.defaultRSABits > 0 {
+		rsaBits = config.defaultRSABits
+	}
+
+	// Resolve the ECDSA curve, falling back to the key profile and then to the hardcoded default
+	ecdsaCurve := P224
+	if !ecdsaCurveV.IsNull() && ecdsaCurveV.IsKnown() {
+		ecdsaCurve = NormalizeECDSACurve(ecdsaCurveV.AsString())
+	} else if profile.ecdsaCurve != "" {
+		ecdsaCurve = profile.ecdsaCurve
+	}
+
+	return keyAlgoName, rsaBits, ecdsaCurve, known
+}
+
+// customizeFIPSKeyDiff checks at plan time that the keys to generate are approved by FIPS 186-4,
+// when the provider `fips_mode` is enabled. The parameters are resolved from the raw configuration
+// of the resource, or of its block with the given key (e.g. `generate_key`), if not empty.
+func customizeFIPSKeyDiff(blockKey string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+		config, ok := m.(*providerConfig)
+		if !ok || !config.fipsMode {
+			return nil
 		}
-	case *ecdsa.PrivateKey:
-		keyBytes, err := x509.MarshalECPrivateKey(k)
-		if err != nil {
-			return diag.Errorf("error encoding key to PEM: %s", err)
+
+		rawConfig, ok := keyParametersRawConfig(d, blockKey)
+		if !ok {
+			return nil
 		}
 
-		keyPemBlock = &pem.Block{
-			Type:  PreamblePrivateKeyEC.String(),
-			Bytes: keyBytes,
+		// Values not yet known at plan time are checked at apply time
+		keyAlgoName, rsaBits, ecdsaCurve, known := resolveKeyParameters(rawConfig, config)
+		if !known || keyAlgoName == "" {
+			return nil
+		}
+
+		return checkFIPSKeyParameters(keyAlgoName, rsaBits, ecdsaCurve)
+	}
+}
+
+// customizeKeyAlgorithmDiff checks at plan time that the algorithm of the keys to generate can be resolved,
+// from the raw configuration of the resource (or of its block with the given key, if not empty)
+// or from the provider `default_key_algorithm`, instead of failing only at apply time.
+// The check is only done when the keys are about to be generated, as the algorithm is then stored in the state.
+func customizeKeyAlgorithmDiff(blockKey string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+		config, ok := m.(*providerConfig)
+		if !ok || d.Id() != "" {
+			return nil
 		}
 
-		// GOTCHA: `x/crypto/ssh` doesn't handle elliptic curve P-224
-		if k.Curve.Params().Name == "P-224" {
-			doMarshalOpenSSHKeyPemBlock = false
+		rawConfig, ok := keyParametersRawConfig(d, blockKey)
+		if !ok {
+			return nil
 		}
-	case ed25519.PrivateKey:
-		keyBytes, err := x509.MarshalPKCS8PrivateKey(k)
+
+		// Values not yet known at plan time are checked at apply time
+		keyAlgoName, _, _, known := resolveKeyParameters(rawConfig, config)
+		if known && keyAlgoName == "" {
+			return errMissingKeyAlgorithm(blockKey)
+		}
+		return nil
+	}
+}
+
+// keyParametersRawConfig returns the raw configuration holding the parameters of the keys to generate:
+// the one of the resource, or the one of its block with the given key, if not empty.
+// It returns false if that is not known yet, or if the block is not set.
+func keyParametersRawConfig(d *schema.ResourceDiff, blockKey string) (cty.Value, bool) {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return cty.NilVal, false
+	}
+	if blockKey != "" {
+		blocks := rawConfig.GetAttr(blockKey)
+		if blocks.IsNull() || !blocks.IsKnown() || blocks.LengthInt() == 0 {
+			return cty.NilVal, false
+		}
+		rawConfig = blocks.Index(cty.NumberIntVal(0))
+	}
+	return rawConfig, true
+}
+
+// errMissingKeyAlgorithm returns the error reported when the algorithm of the keys to generate
+// is set neither in the configuration of the resource (or of its block with the given key, if not empty)
+// nor in the one of the provider.
+func errMissingKeyAlgorithm(blockKey string) error {
+	if blockKey != "" {
+		return fmt.Errorf("missing key algorithm: either set '%s.0.algorithm' or the provider 'default_key_algorithm'", blockKey)
+	}
+	return fmt.Errorf("missing key algorithm: either set 'algorithm', 'key_profile' or the provider 'default_key_algorithm'")
+}
+
+func createResourcePrivateKey(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	keyAlgoName, keyGen, diags := resolveKeyGenerator(d, m.(*providerConfig))
+	if diags.HasError() {
+		return diags
+	}
+
+	// Generate the new Key, reading randomness from the entropy source if one is configured
+	random := rand.Reader
+	if entropySource := d.Get("entropy_source").(string); entropySource != "" {
+		if err := checkEntropySourceAlgorithm(keyAlgoName); err != nil {
+			return diag.FromErr(err)
+		}
+
+		f, err := openEntropySource(entropySource)
 		if err != nil {
-			return diag.Errorf("error encoding key to PEM: %s", err)
+			return diag.Errorf("invalid entropy_source: %s", err)
 		}
+		defer f.Close()
+		random = f
+	}
+
+	key, err := keyGen(random, d.Get("rsa_bits").(int), d.Get("ecdsa_curve").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Resolve the private key format, falling back to the default for the key algorithm
+	keyFormat := PrivateKeyFormat(d.Get("private_key_format").(string))
+	if keyFormat == "" {
+		keyFormat = algorithmPrivateKeyFormats[keyAlgoName][0]
+	}
+	if err := checkPrivateKeyFormat(keyAlgoName, keyFormat); err != nil {
+		return diag.Errorf("invalid private_key_format: %s", err)
+	}
+	if err := d.Set("private_key_format", keyFormat); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_format': %s", err)
+	}
+
+	// Marshal the Key in PEM block
+	keyPemBlock, err := privateKeyToFormattedPEMBlock(key, keyFormat)
+	if err != nil {
+		return diag.Errorf("error encoding key to PEM: %s", err)
+	}
+
+	keyPem := pem.EncodeToMemory(keyPemBlock)
+
+	pubKey, err := privateKeyToPublicKey(key)
+	if err != nil {
+		return diag.Errorf("failed to get public key from private key: %v", err)
+	}
 
-		keyPemBlock = &pem.Block{
-			Type:  PreamblePrivateKeyPKCS8.String(),
-			Bytes: keyBytes,
+	// GOTCHA: `x/crypto/ssh` doesn't handle elliptic curve P-224
+	doMarshalOpenSSHKeyPemBlock := publicKeySupportsOpenSSH(pubKey)
+	if err := d.Set("public_key_raw_base64", base64.StdEncoding.EncodeToString(publicKeyToRaw(pubKey))); err != nil {
+		return diag.Errorf("error setting value on key 'public_key_raw_base64': %s", err)
+	}
+
+	if err := d.Set("public_key_raw_base64url", base64.RawURLEncoding.EncodeToString(publicKeyToRaw(pubKey))); err != nil {
+		return diag.Errorf("error setting value on key 'public_key_raw_base64url': %s", err)
+	}
+
+	pubKeySPKISHA256, err := publicKeySPKISHA256(pubKey)
+	if err != nil {
+		return diag.Errorf("failed to compute the hash of the public key: %v", err)
+	}
+	if err := d.Set("content_hash", pubKeySPKISHA256); err != nil {
+		return diag.Errorf("error setting value on key 'content_hash': %s", err)
+	}
+
+	if err := writeToFile(d, keyPem); err != nil {
+		return diag.Errorf("error writing private key to write_to: %s", err)
+	}
+
+	// In ephemeral mode, the private key is handed over via the file and never stored in state
+	if d.Get("ephemeral").(bool) {
+		if err := writeFileAtomically(d.Get("private_key_file").(string), keyPem, 0600); err != nil {
+			return diag.Errorf("error writing private key to private_key_file: %s", err)
 		}
-	default:
-		return diag.Errorf("unsupported private key type")
+
+		return setPublicKeyAttributes(d, key)
 	}
 
-	if err := d.Set("private_key_pem", string(pem.EncodeToMemory(keyPemBlock))); err != nil {
+	if err := d.Set("private_key_pem", string(keyPem)); err != nil {
 		return diag.Errorf("error setting value on key 'private_key_pem': %s", err)
 	}
 
+	if err := d.Set("private_key_pem_base64", base64.StdEncoding.EncodeToString(keyPem)); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_pem_base64': %s", err)
+	}
+
+	if err := d.Set("private_key_pem_base64url", base64.RawURLEncoding.EncodeToString(keyPem)); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_pem_base64url': %s", err)
+	}
+
+	if err := d.Set("private_key_raw_base64", base64.StdEncoding.EncodeToString(privateKeyToRaw(key))); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_raw_base64': %s", err)
+	}
+
+	if err := d.Set("private_key_raw_base64url", base64.RawURLEncoding.EncodeToString(privateKeyToRaw(key))); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_raw_base64url': %s", err)
+	}
+
 	// Marshal the Key in OpenSSH PEM block, if enabled
 	prvKeyOpenSSH := ""
 	if doMarshalOpenSSHKeyPemBlock {
-		openSSHKeyPemBlock, err := openssh.MarshalPrivateKey(key, "")
+		openSSHKeyPemBlock, err := openssh.MarshalPrivateKey(key, d.Get("openssh_comment").(string))
 		if err != nil {
 			return diag.Errorf("unable to marshal private key into OpenSSH format: %v", err)
 		}
@@ -193,6 +595,20 @@ func createResourcePrivateKey(_ context.Context, d *schema.ResourceData, _ inter
 		return diag.Errorf("error setting value on key 'private_key_openssh': %s", err)
 	}
 
+	// Marshal the Key in OpenSSH PEM block, encrypted with the passphrase, if enabled and set
+	prvKeyOpenSSHEncrypted := ""
+	if passphrase := d.Get("openssh_passphrase").(string); doMarshalOpenSSHKeyPemBlock && passphrase != "" {
+		openSSHKeyPemBlock, err := ssh.MarshalPrivateKeyWithPassphrase(key, d.Get("openssh_comment").(string), []byte(passphrase))
+		if err != nil {
+			return diag.Errorf("unable to marshal private key into encrypted OpenSSH format: %v", err)
+		}
+
+		prvKeyOpenSSHEncrypted = string(pem.EncodeToMemory(openSSHKeyPemBlock))
+	}
+	if err := d.Set("private_key_openssh_encrypted", prvKeyOpenSSHEncrypted); err != nil {
+		return diag.Errorf("error setting value on key 'private_key_openssh_encrypted': %s", err)
+	}
+
 	return setPublicKeyAttributes(d, key)
 }
 
@@ -204,3 +620,88 @@ func deleteResourcePrivateKey(_ context.Context, d *schema.ResourceData, _ inter
 func readResourcePrivateKey(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	return nil
 }
+
+func customizePrivateKeyDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	// The private key format can be checked as soon as the key algorithm is known:
+	// when falling back to the provider default, that happens at apply time
+	if d.NewValueKnown("algorithm") && d.NewValueKnown("private_key_format") {
+		keyAlgoName := Algorithm(d.Get("algorithm").(string))
+		keyFormat := PrivateKeyFormat(d.Get("private_key_format").(string))
+
+		if keyAlgoName != "" && keyFormat != "" {
+			if err := checkPrivateKeyFormat(keyAlgoName, keyFormat); err != nil {
+				return fmt.Errorf("invalid private_key_format: %s", err)
+			}
+		}
+	}
+
+	// Values not yet known at plan time are validated at the next plan
+	if !d.NewValueKnown("ephemeral") || !d.NewValueKnown("private_key_file") {
+		return nil
+	}
+
+	ephemeral := d.Get("ephemeral").(bool)
+	privateKeyFile := d.Get("private_key_file").(string)
+
+	if ephemeral && privateKeyFile == "" {
+		return fmt.Errorf("private_key_file must be set when ephemeral is true")
+	}
+	if !ephemeral && privateKeyFile != "" {
+		return fmt.Errorf("private_key_file can only be set when ephemeral is true")
+	}
+
+	return nil
+}
+
+// customizeEntropySourceDiff checks at plan time that `entropy_source` is only set for keys of an algorithm
+// that can be generated from it: see checkEntropySourceAlgorithm.
+func customizeEntropySourceDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config, ok := m.(*providerConfig)
+	if !ok {
+		return nil
+	}
+
+	rawConfig, ok := keyParametersRawConfig(d, "")
+	if !ok {
+		return nil
+	}
+	if entropySource := rawConfig.GetAttr("entropy_source"); entropySource.IsNull() {
+		return nil
+	}
+
+	// Values not yet known at plan time are checked at apply time
+	keyAlgoName, _, _, known := resolveKeyParameters(rawConfig, config)
+	if !known || keyAlgoName == "" {
+		return nil
+	}
+
+	return checkEntropySourceAlgorithm(keyAlgoName)
+}
+
+// checkEntropySourceAlgorithm returns an error if keys of the given Algorithm can't be generated from `entropy_source`.
+//
+// GOTCHA: crypto/rsa and crypto/ecdsa read an extra byte from a custom io.Reader at random (see `randutil.MaybeReadByte`)
+// and, since Go 1.26, ignore it altogether: only crypto/ed25519 derives the key from exactly the bytes it reads.
+func checkEntropySourceAlgorithm(keyAlgoName Algorithm) error {
+	if keyAlgoName != ED25519 {
+		return fmt.Errorf("entropy_source is only supported for %s keys, not %s", ED25519, keyAlgoName)
+	}
+	return nil
+}
+
+// openEntropySource opens the character device or named pipe at the given path, to read randomness from it.
+// Nothing is read from it here: all the bytes it provides are left for the key generation.
+//
+// GOTCHA: Regular files (and directories) are rejected: reading the same randomness again
+// would generate the same key again.
+func openEntropySource(path string) (*os.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if mode := info.Mode(); mode&(os.ModeCharDevice|os.ModeNamedPipe) == 0 {
+		return nil, fmt.Errorf("%q is not a character device or a named pipe (mode: %s)", path, mode)
+	}
+
+	return os.Open(path)
+}
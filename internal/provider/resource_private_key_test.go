@@ -1,11 +1,16 @@
 package provider
 
 import (
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
 	r "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestPrivateKeyRSA(t *testing.T) {
@@ -26,11 +31,18 @@ func TestPrivateKeyRSA(t *testing.T) {
 						}
 						return nil
 					}),
+					testCheckAttrBase64Of("tls_private_key.test", "private_key_pem_base64", "private_key_pem"),
+					testCheckAttrBase64URLOf("tls_private_key.test", "private_key_pem_base64url", "private_key_pem_base64"),
+					testCheckAttrContentHashOf("tls_private_key.test", "content_hash", "public_key_pem"),
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_raw_base64", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "public_key_raw_base64", ""),
 					testCheckPEMFormat("tls_private_key.test", "public_key_pem", PreamblePublicKey),
+					testCheckAttrDERBase64Of("tls_private_key.test", "public_key_der_base64", "public_key_pem"),
 					testCheckPEMFormat("tls_private_key.test", "private_key_openssh", PreamblePrivateKeyOpenSSH),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ssh-rsa `)),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_md5", regexp.MustCompile(`^([abcdef\d]{2}:){15}[abcdef\d]{2}`)),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256", regexp.MustCompile(`^SHA256:`)),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256_hex", regexp.MustCompile(`^([abcdef\d]{2}:){31}[abcdef\d]{2}$`)),
 				),
 			},
 			{
@@ -54,6 +66,101 @@ func TestPrivateKeyRSA(t *testing.T) {
 	})
 }
 
+func TestPrivateKey_ForceNewOnKeyParametersChange(t *testing.T) {
+	var privateKeyPEM, publicKeyPEM, publicKeyOpenSSH, keyContentHash string
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "RSA"
+						rsa_bits  = 2048
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "2048"),
+					testCheckAttrSaveValue("tls_private_key.test", "private_key_pem", &privateKeyPEM),
+					testCheckAttrSaveValue("tls_private_key.test", "public_key_pem", &publicKeyPEM),
+					testCheckAttrSaveValue("tls_private_key.test", "public_key_openssh", &publicKeyOpenSSH),
+					testCheckAttrSaveValue("tls_private_key.test", "content_hash", &keyContentHash),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "RSA"
+						rsa_bits  = 4096
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "4096"),
+					testCheckAttrValueChanged("tls_private_key.test", "private_key_pem", &privateKeyPEM),
+					testCheckAttrValueChanged("tls_private_key.test", "public_key_pem", &publicKeyPEM),
+					testCheckAttrValueChanged("tls_private_key.test", "public_key_openssh", &publicKeyOpenSSH),
+					testCheckAttrValueChanged("tls_private_key.test", "content_hash", &keyContentHash),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "RSA"
+						rsa_bits  = 4096
+					}
+				`,
+				PlanOnly: true,
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "RSA"
+						rsa_bits  = 4096
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "4096"),
+					testCheckAttrValueUnchanged("tls_private_key.test", "private_key_pem", &privateKeyPEM),
+					testCheckAttrValueUnchanged("tls_private_key.test", "content_hash", &keyContentHash),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyEC),
+					testCheckAttrValueChanged("tls_private_key.test", "private_key_pem", &privateKeyPEM),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P384"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp384 `)),
+					testCheckAttrValueChanged("tls_private_key.test", "private_key_pem", &privateKeyPEM),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P384"
+					}
+				`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestPrivateKeyECDSA(t *testing.T) {
 	r.UnitTest(t, r.TestCase{
 		ProviderFactories: testProviders,
@@ -71,6 +178,8 @@ func TestPrivateKeyECDSA(t *testing.T) {
 					r.TestCheckResourceAttr("tls_private_key.test", "public_key_openssh", ""),
 					r.TestCheckResourceAttr("tls_private_key.test", "public_key_fingerprint_md5", ""),
 					r.TestCheckResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256_hex", ""),
+					testCheckRawKeyAttributes("tls_private_key.test", 28, 57),
 				),
 			},
 			{
@@ -87,12 +196,89 @@ func TestPrivateKeyECDSA(t *testing.T) {
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp256 `)),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_md5", regexp.MustCompile(`^([abcdef\d]{2}:){15}[abcdef\d]{2}`)),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256", regexp.MustCompile(`^SHA256:`)),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256_hex", regexp.MustCompile(`^([abcdef\d]{2}:){31}[abcdef\d]{2}$`)),
+					testCheckRawKeyAttributes("tls_private_key.test", 32, 65),
 				),
 			},
 		},
 	})
 }
 
+func TestPrivateKeyECDSA_CurveAliases(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "224"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P224"),
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyEC),
+					r.TestCheckResourceAttr("tls_private_key.test", "public_key_openssh", ""),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "256"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P256"),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp256 `)),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+				`,
+				PlanOnly: true,
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "384"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P384"),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp384 `)),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "521"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P521"),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp521 `)),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ecdsa_curve = "512"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected ecdsa_curve to be one of \[.*\], got 512`),
+			},
+		},
+	})
+}
+
 func TestPrivateKeyED25519(t *testing.T) {
 	r.UnitTest(t, r.TestCase{
 		ProviderFactories: testProviders,
@@ -108,10 +294,717 @@ func TestPrivateKeyED25519(t *testing.T) {
 					testCheckPEMFormat("tls_private_key.test", "public_key_pem", PreamblePublicKey),
 					testCheckPEMFormat("tls_private_key.test", "private_key_openssh", PreamblePrivateKeyOpenSSH),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ssh-ed25519 `)),
+					testCheckRawKeyAttributes("tls_private_key.test", 32, 32),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_md5", regexp.MustCompile(`^([abcdef\d]{2}:){15}[abcdef\d]{2}`)),
 					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256", regexp.MustCompile(`^SHA256:`)),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256_hex", regexp.MustCompile(`^([abcdef\d]{2}:){31}[abcdef\d]{2}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestPrivateKey_OpenSSHPassphrase(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+				`,
+				Check: r.TestCheckResourceAttr("tls_private_key.test", "private_key_openssh_encrypted", ""),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm          = "ED25519"
+						openssh_passphrase = "correct horse battery staple"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMFormat("tls_private_key.test", "private_key_openssh_encrypted", PreamblePrivateKeyOpenSSH),
+					r.TestCheckResourceAttr("tls_private_key.test", "openssh_passphrase", hashForState("correct horse battery staple")),
+					testCheckOpenSSHEncryptedPrivateKey("tls_private_key.test", "correct horse battery staple"),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm          = "RSA"
+						openssh_passphrase = "correct horse battery staple"
+					}
+				`,
+				Check: testCheckOpenSSHEncryptedPrivateKey("tls_private_key.test", "correct horse battery staple"),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm          = "ECDSA"
+						ecdsa_curve        = "P224"
+						openssh_passphrase = "correct horse battery staple"
+					}
+				`,
+				Check: r.TestCheckResourceAttr("tls_private_key.test", "private_key_openssh_encrypted", ""),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm          = "ED25519"
+						openssh_passphrase = ""
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected "openssh_passphrase" not to be an empty string`),
+			},
+		},
+	})
+}
+
+// testCheckOpenSSHEncryptedPrivateKey checks that `private_key_openssh_encrypted` can be decrypted
+// with the given passphrase, and that it matches `public_key_openssh`.
+func testCheckOpenSSHEncryptedPrivateKey(name, passphrase string) r.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		attrs := rs.Primary.Attributes
+
+		if _, _, err := parsePrivateKeyOpenSSHPEM([]byte(attrs["private_key_openssh_encrypted"]), nil); err == nil {
+			return fmt.Errorf("expected private_key_openssh_encrypted to require a passphrase")
+		}
+
+		prvKey, _, err := parsePrivateKeyOpenSSHPEM([]byte(attrs["private_key_openssh_encrypted"]), []byte(passphrase))
+		if err != nil {
+			return fmt.Errorf("unable to parse private_key_openssh_encrypted with passphrase: %w", err)
+		}
+		pubKey, err := privateKeyToPublicKey(prvKey)
+		if err != nil {
+			return err
+		}
+		sshPubKey, err := ssh.NewPublicKey(pubKey)
+		if err != nil {
+			return err
+		}
+
+		if got := string(ssh.MarshalAuthorizedKey(sshPubKey)); got != attrs["public_key_openssh"] {
+			return fmt.Errorf("private_key_openssh_encrypted does not match public_key_openssh: %q != %q", got, attrs["public_key_openssh"])
+		}
+		return nil
+	}
+}
+
+func TestPrivateKey_OpenSSHComment(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+				`,
+				Check: r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ssh-ed25519 \S+\n$`)),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm       = "ED25519"
+						openssh_comment = "user@host"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ssh-ed25519 \S+ user@host\n$`)),
+					testCheckOpenSSHComment("tls_private_key.test", "user@host"),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm       = "RSA"
+						openssh_comment = "deploy key for example.com"
+					}
+				`,
+				Check: testCheckOpenSSHComment("tls_private_key.test", "deploy key for example.com"),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm       = "ED25519"
+						openssh_comment = "user@host\nssh-ed25519 AAAA"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected value of openssh_comment to not contain any of`),
+			},
+		},
+	})
+}
+
+// testCheckOpenSSHComment checks that `public_key_openssh` carries the given comment,
+// and that it still parses as an OpenSSH 'Authorized Keys' line.
+func testCheckOpenSSHComment(name, expectedComment string) r.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		_, comment, _, rest, err := ssh.ParseAuthorizedKey([]byte(rs.Primary.Attributes["public_key_openssh"]))
+		if err != nil {
+			return fmt.Errorf("unable to parse public_key_openssh: %w", err)
+		}
+		if comment != expectedComment {
+			return fmt.Errorf("incorrect comment in public_key_openssh: expected %q, got %q", expectedComment, comment)
+		}
+		if len(rest) != 0 {
+			return fmt.Errorf("unexpected content after public_key_openssh: %q", rest)
+		}
+		return nil
+	}
+}
+
+func TestPrivateKey_PrivateKeyFormat(t *testing.T) {
+	config := `
+		resource "tls_private_key" "test" {
+			algorithm          = %q
+			ecdsa_curve        = %q
+			private_key_format = %q
+		}
+		data "tls_public_key" "test" {
+			private_key_pem = tls_private_key.test.private_key_pem
+		}
+	`
+
+	var steps []r.TestStep
+	for _, tc := range []struct {
+		algorithm, curve, format string
+		preamble                 PEMPreamble
+	}{
+		{"RSA", "P224", "pkcs1", PreamblePrivateKeyRSA},
+		{"RSA", "P224", "pkcs8", PreamblePrivateKeyPKCS8},
+		{"ECDSA", "P256", "sec1", PreamblePrivateKeyEC},
+		{"ECDSA", "P256", "pkcs8", PreamblePrivateKeyPKCS8},
+		{"ED25519", "P224", "pkcs8", PreamblePrivateKeyPKCS8},
+	} {
+		tc := tc
+
+		// Each private key must be read back, by the data source, into the same public key
+		steps = append(steps, r.TestStep{
+			Config: fmt.Sprintf(config, tc.algorithm, tc.curve, tc.format),
+			Check: r.ComposeAggregateTestCheckFunc(
+				r.TestCheckResourceAttr("tls_private_key.test", "private_key_format", tc.format),
+				testCheckPEMFormat("tls_private_key.test", "private_key_pem", tc.preamble),
+				r.TestCheckResourceAttrWith("tls_private_key.test", "private_key_pem", func(value string) error {
+					block, _ := pem.Decode([]byte(value))
+					preamble, err := PEMBlockToPEMPreamble(block)
+					if err != nil {
+						return err
+					}
+					if expected := privateKeyFormatPreambles[PrivateKeyFormat(tc.format)]; preamble != expected {
+						return fmt.Errorf("expected PEM preamble %q for format %s, got %q", expected, tc.format, preamble)
+					}
+					return nil
+				}),
+				r.TestCheckResourceAttr("data.tls_public_key.test", "algorithm", tc.algorithm),
+				r.TestCheckResourceAttrPair("data.tls_public_key.test", "public_key_pem", "tls_private_key.test", "public_key_pem"),
+			),
+		})
+	}
+
+	steps = append(steps,
+		r.TestStep{
+			Config:      fmt.Sprintf(config, "RSA", "P224", "sec1"),
+			ExpectError: regexp.MustCompile("invalid private_key_format: RSA keys cannot be encoded in sec1 format"),
+		},
+		r.TestStep{
+			Config:      fmt.Sprintf(config, "ECDSA", "P256", "pkcs1"),
+			ExpectError: regexp.MustCompile("invalid private_key_format: ECDSA keys cannot be encoded in pkcs1 format"),
+		},
+		r.TestStep{
+			Config:      fmt.Sprintf(config, "ED25519", "P224", "sec1"),
+			ExpectError: regexp.MustCompile("invalid private_key_format: ED25519 keys cannot be encoded in sec1 format"),
+		},
+	)
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps:             steps,
+	})
+}
+
+func TestPrivateKey_ProviderDefaults(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					provider "tls" {
+						default_key_algorithm = "RSA"
+						default_rsa_bits      = 4096
+					}
+					resource "tls_private_key" "test" {}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "4096"),
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyRSA),
+				),
+			},
+			{
+				Config: `
+					provider "tls" {
+						default_key_algorithm = "RSA"
+						default_rsa_bits      = 4096
+					}
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "ECDSA"),
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyEC),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "RSA"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "2048"),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test_no_algorithm" {}
+				`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`missing key algorithm: either set 'algorithm', 'key_profile' or the provider\s+'default_key_algorithm'`),
+			},
+		},
+	})
+}
+
+func TestPrivateKey_FIPSMode(t *testing.T) {
+	config := func(providerArgs, resourceArgs string) string {
+		return fmt.Sprintf(`
+			provider "tls" {
+				fips_mode = true
+				%s
+			}
+			resource "tls_private_key" "test" {
+				%s
+			}
+		`, providerArgs, resourceArgs)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config("", `
+					algorithm = "RSA"
+					rsa_bits  = 3072
+				`),
+				Check: r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "3072"),
+			},
+			{
+				Config: config("", `
+					algorithm   = "ECDSA"
+					ecdsa_curve = "P384"
+				`),
+				Check: r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P384"),
+			},
+			{
+				Config: config("", `key_profile = "fips"`),
+				Check:  r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P384"),
+			},
+			{
+				Config: config(`default_key_algorithm = "RSA"`, ""),
+				Check:  r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "2048"),
+			},
+			{
+				Config:      config("", `algorithm = "ED25519"`),
+				ExpectError: regexp.MustCompile(`ED25519 keys are not approved in fips_mode: use either RSA keys of at least\s+2048 bits, or ECDSA keys with curve P256, P384, P521`),
+			},
+			{
+				Config:      config("", `key_profile = "modern"`),
+				ExpectError: regexp.MustCompile(`ED25519 keys are not approved in fips_mode`),
+			},
+			{
+				Config:      config(`default_key_algorithm = "ED25519"`, ""),
+				ExpectError: regexp.MustCompile(`ED25519 keys are not approved in fips_mode`),
+			},
+			{
+				Config:      config("", `algorithm = "ECDSA"`),
+				ExpectError: regexp.MustCompile(`ECDSA keys with curve P224 are not approved in fips_mode: set ecdsa_curve to\s+one of P256, P384, P521`),
+			},
+			{
+				Config: config("", `
+					algorithm = "RSA"
+					rsa_bits  = 1024
+				`),
+				ExpectError: regexp.MustCompile(`RSA keys of 1024 bits are not approved in fips_mode: set rsa_bits to at least\s+2048`),
+			},
+			{
+				Config: `
+					provider "tls" {
+						fips_mode = true
+					}
+					resource "tls_self_signed_cert" "test" {
+						generate_key {
+							algorithm = "ED25519"
+						}
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`,
+				ExpectError: regexp.MustCompile(`ED25519 keys are not approved in fips_mode`),
+			},
+		},
+	})
+}
+
+func TestCheckFIPSKeyParameters(t *testing.T) {
+	testCases := map[string]struct {
+		algorithm  Algorithm
+		rsaBits    int
+		ecdsaCurve ECDSACurve
+		expectErr  bool
+	}{
+		"RSA 2048":   {algorithm: RSA, rsaBits: 2048},
+		"RSA 4096":   {algorithm: RSA, rsaBits: 4096},
+		"RSA 1024":   {algorithm: RSA, rsaBits: 1024, expectErr: true},
+		"ECDSA P256": {algorithm: ECDSA, ecdsaCurve: P256},
+		"ECDSA P384": {algorithm: ECDSA, ecdsaCurve: P384},
+		"ECDSA P521": {algorithm: ECDSA, ecdsaCurve: P521},
+		"ECDSA P224": {algorithm: ECDSA, ecdsaCurve: P224, expectErr: true},
+		"ED25519":    {algorithm: ED25519, expectErr: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkFIPSKeyParameters(tc.algorithm, tc.rsaBits, tc.ecdsaCurve)
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrivateKey_KeyProfile(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile = "modern"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "ED25519"),
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyPKCS8),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile = "fips"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P384"),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_openssh", regexp.MustCompile(`^ecdsa-sha2-nistp384 `)),
+				),
+			},
+			{
+				Config: `
+					provider "tls" {
+						default_rsa_bits = 4096
+					}
+					resource "tls_private_key" "test" {
+						key_profile = "legacy-compat"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "rsa_bits", "2048"),
+					testCheckPEMFormat("tls_private_key.test", "private_key_pem", PreamblePrivateKeyRSA),
+				),
+			},
+			{
+				// The parameters of the profile can be overridden individually
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile = "fips"
+						ecdsa_curve = "P256"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P256"),
+				),
+			},
+			{
+				// An explicit algorithm overrides the whole profile
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile = "fips"
+						algorithm   = "ECDSA"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_private_key.test", "ecdsa_curve", "P224"),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile = "strong"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected key_profile to be one of \[modern fips legacy-compat\]`),
+			},
+		},
+	})
+}
+
+func TestPrivateKey_Ephemeral(t *testing.T) {
+	privateKeyFile := filepath.Join(t.TempDir(), "private_key.pem")
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm        = "ECDSA"
+						ecdsa_curve      = "P256"
+						ephemeral        = true
+						private_key_file = %q
+					}
+				`, privateKeyFile),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_pem", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_pem_base64", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_openssh", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_openssh_encrypted", ""),
+					r.TestCheckResourceAttr("tls_private_key.test", "private_key_raw_base64", ""),
+					testCheckPEMFormat("tls_private_key.test", "public_key_pem", PreamblePublicKey),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256", regexp.MustCompile(`^SHA256:`)),
+					r.TestMatchResourceAttr("tls_private_key.test", "public_key_fingerprint_sha256_hex", regexp.MustCompile(`^([abcdef\d]{2}:){31}[abcdef\d]{2}$`)),
+					r.TestCheckResourceAttrWith("tls_private_key.test", "public_key_pem", func(value string) error {
+						keyPem, err := os.ReadFile(privateKeyFile)
+						if err != nil {
+							return err
+						}
+
+						prvKey, _, err := parsePrivateKeyPEM(keyPem, false)
+						if err != nil {
+							return err
+						}
+						pubKey, err := privateKeyToPublicKey(prvKey)
+						if err != nil {
+							return err
+						}
+						_, pubKeyPEM, err := publicKeyToPEM(pubKey)
+						if err != nil {
+							return err
+						}
+
+						if pubKeyPEM != value {
+							return fmt.Errorf("private key written to %s does not match public_key_pem", privateKeyFile)
+						}
+						return nil
+					}),
 				),
 			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm        = "ECDSA"
+						ecdsa_curve      = "P256"
+						ephemeral        = true
+						private_key_file = %q
+					}
+				`, privateKeyFile),
+				PlanOnly: true,
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ECDSA"
+						ephemeral = true
+					}
+				`,
+				ExpectError: regexp.MustCompile("private_key_file must be set when ephemeral is true"),
+			},
 		},
 	})
 }
+
+func TestPrivateKey_WriteTo(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyFile := filepath.Join(dir, "private_key.pem")
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+						write_to {
+							path = %q
+						}
+					}
+				`, privateKeyFile),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.test", "write_to.0.permissions", "0600"),
+					testCheckAttrWrittenToFile("tls_private_key.test", "private_key_pem", privateKeyFile, 0600),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+						write_to {
+							path        = %q
+							permissions = "0440"
+						}
+					}
+				`, privateKeyFile),
+				Check: testCheckAttrWrittenToFile("tls_private_key.test", "private_key_pem", privateKeyFile, 0440),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+						write_to {
+							path        = %q
+							permissions = "rw-------"
+						}
+					}
+				`, privateKeyFile),
+				ExpectError: regexp.MustCompile("must be a file mode in octal notation, like '0600'"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+						write_to {
+							path = %q
+						}
+					}
+				`, filepath.Join(dir, "missing", "private_key.pem")),
+				ExpectError: regexp.MustCompile("error writing private key to write_to"),
+			},
+		},
+	})
+}
+
+func TestPrivateKey_EntropySource(t *testing.T) {
+	if _, err := os.Stat("/dev/urandom"); err != nil {
+		t.Skip("/dev/urandom is not available")
+	}
+	regularFile := filepath.Join(t.TempDir(), "entropy")
+	if err := os.WriteFile(regularFile, make([]byte, 1024), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "ed25519" {
+						algorithm      = "ED25519"
+						entropy_source = "/dev/urandom"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_key.ed25519", "entropy_source", "/dev/urandom"),
+					testCheckPEMFormat("tls_private_key.ed25519", "private_key_pem", PreamblePrivateKeyPKCS8),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm      = "RSA"
+						entropy_source = "/dev/urandom"
+					}
+				`,
+				ExpectError: regexp.MustCompile("entropy_source is only supported for ED25519 keys, not RSA"),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						key_profile    = "fips"
+						entropy_source = "/dev/urandom"
+					}
+				`,
+				ExpectError: regexp.MustCompile("entropy_source is only supported for ED25519 keys, not ECDSA"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_private_key" "test" {
+						algorithm      = "ED25519"
+						entropy_source = %q
+					}
+				`, regularFile),
+				ExpectError: regexp.MustCompile("invalid entropy_source: .* is not a character device or a named pipe"),
+			},
+		},
+	})
+}
+
+func TestOpenEntropySource(t *testing.T) {
+	dir := t.TempDir()
+	regularFile := filepath.Join(dir, "entropy")
+	if err := os.WriteFile(regularFile, make([]byte, 1024), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		path      string
+		expectErr bool
+	}{
+		"regular file": {path: regularFile, expectErr: true},
+		"directory":    {path: dir, expectErr: true},
+		"missing":      {path: filepath.Join(dir, "missing"), expectErr: true},
+	}
+	if _, err := os.Stat("/dev/urandom"); err == nil {
+		testCases["character device"] = struct {
+			path      string
+			expectErr bool
+		}{path: "/dev/urandom"}
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			f, err := openEntropySource(tc.path)
+			if tc.expectErr && err == nil {
+				f.Close()
+				t.Errorf("expected error, got none")
+			}
+			if !tc.expectErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				f.Close()
+			}
+		})
+	}
+}
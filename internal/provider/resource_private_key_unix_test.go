@@ -0,0 +1,55 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package provider
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestPrivateKey_EntropySourceNamedPipe(t *testing.T) {
+	fixed := bytes.Repeat([]byte("terraform-provider-tls entropy"), 64)
+
+	pipe := filepath.Join(t.TempDir(), "entropy")
+	if err := syscall.Mkfifo(pipe, 0600); err != nil {
+		t.Skipf("named pipes are not available: %s", err)
+	}
+
+	// NOTE: Opening a named pipe for reading blocks until a writer opens it too
+	go func() {
+		w, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer w.Close()
+		_, _ = w.Write(fixed)
+	}()
+
+	f, err := openEntropySource(pipe)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	key, err := keyGenerators[ED25519](f, 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The key is derived from the very first bytes provided by the source, and nothing else is read from it
+	if expected := ed25519.NewKeyFromSeed(fixed[:ed25519.SeedSize]); !expected.Equal(key) {
+		t.Errorf("expected the key to be generated from the first %d bytes of the entropy source", ed25519.SeedSize)
+	}
+	rest, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(rest, fixed[ed25519.SeedSize:]) {
+		t.Errorf("expected %d bytes left in the entropy source, got %d", len(fixed)-ed25519.SeedSize, len(rest))
+	}
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/terraform-providers/terraform-provider-tls/internal/openssh"
+)
+
+// maxPrivateKeysCount is the maximum number of keys a single `tls_private_keys` resource can generate.
+const maxPrivateKeysCount = 100
+
+func resourcePrivateKeys() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createResourcePrivateKeys,
+		DeleteContext: deleteResourcePrivateKey,
+		ReadContext:   readResourcePrivateKey,
+		CustomizeDiff: customdiff.All(customizeKeyAlgorithmDiff(""), customizeFIPSKeyDiff("")),
+
+		Description: "Creates multiple PEM (and OpenSSH) formatted private keys, all with the same parameters.\n\n" +
+			"Generates the given number of secure private keys, as multiple `tls_private_key` resources would, " +
+			"and exposes them as lists, in the same order. " +
+			"This is useful for systems that rotate or shard among a set of keys. " +
+			"This resource is primarily intended for easily bootstrapping throwaway development environments.",
+
+		Schema: map[string]*schema.Schema{
+			"key_count": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, maxPrivateKeysCount)),
+				Description: "Number of private keys to generate, between `1` and `100`. " +
+					"Changing it generates all the keys again.",
+			},
+
+			"algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedAlgorithmsStr(), false)),
+				Description: "Name of the algorithm to use when generating the private keys. " +
+					"Currently-supported values are `RSA`, `ECDSA` and `ED25519`. " +
+					"If not set, the algorithm of `key_profile` is used, and otherwise the provider `default_key_algorithm`: " +
+					"one of the three must be set.",
+			},
+
+			"key_profile": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedKeyProfilesStr(), false)),
+				Description: "Intended use of the private keys, selecting a sensible algorithm and parameters " +
+					"instead of setting `algorithm`: " +
+					"`modern` generates `ED25519` keys, " +
+					"`fips` generates `ECDSA` keys with curve `P384`, approved by [FIPS 186-4](https://csrc.nist.gov/publications/detail/fips/186/4/final), " +
+					"and `legacy-compat` generates `RSA` keys of `2048` bits, for clients that support nothing else. " +
+					"If `algorithm` is set, it overrides the whole profile; otherwise, " +
+					"`rsa_bits` and `ecdsa_curve` override the matching parameter of the profile.",
+			},
+
+			"rsa_bits": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Description: "When `algorithm` is `RSA`, the size of the generated RSA keys, in bits. " +
+					"If not set, the size of the `key_profile` is used, if any, " +
+					"and otherwise the provider `default_rsa_bits` (default: `2048`).",
+			},
+
+			"ecdsa_curve": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedECDSACurvesAndAliasesStr(), false)),
+				StateFunc: func(v interface{}) string {
+					return NormalizeECDSACurve(v.(string)).String()
+				},
+				Description: "When `algorithm` is `ECDSA`, the name of the elliptic curve to use. " +
+					"Currently-supported values are `P224`, `P256`, `P384` or `P521`. " +
+					"If not set, the curve of the `key_profile` is used, if any (default: `P224`). " +
+					"The key sizes `224`, `256`, `384` and `521` are also accepted as aliases, and normalized to the curve name.",
+			},
+
+			"private_keys_pem": {
+				Type:      schema.TypeList,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Description: "Private keys data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format, " +
+					"encoded as `tls_private_key` does by default.",
+			},
+
+			"private_keys_openssh": {
+				Type:      schema.TypeList,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Description: "Private keys data in [OpenSSH PEM (RFC 4716)](https://datatracker.ietf.org/doc/html/rfc4716) format. " +
+					"Only available if the selected private key format is compatible, similarly to " +
+					"`public_keys_openssh` and the [ECDSA P224 limitations](../../docs#limitations) (empty strings otherwise).",
+			},
+
+			"public_keys_pem": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Public keys data in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
+			},
+
+			"public_keys_openssh": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "The public keys data in " +
+					"[\"Authorized Keys\"](https://www.ssh.com/academy/ssh/authorized_keys/openssh#format-of-the-authorized-keys-file) format. " +
+					"Only available if the selected private key format is compatible, as per the rules " +
+					"explained in `tls_private_key` (empty strings otherwise).",
+			},
+
+			"public_key_fingerprints_sha256": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "The fingerprints of the public keys data in OpenSSH SHA256 hash format, e.g. `SHA256:...`. " +
+					"Only available if the selected private key format is compatible, similarly to " +
+					"`public_keys_openssh` (empty strings otherwise).",
+			},
+
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Unique identifier for this resource: " +
+					"hexadecimal representation of the SHA1 checksum of the public keys.",
+			},
+		},
+	}
+}
+
+func createResourcePrivateKeys(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, keyGen, diags := resolveKeyGenerator(d, m.(*providerConfig))
+	if diags.HasError() {
+		return diags
+	}
+
+	keyCount := d.Get("key_count").(int)
+	prvKeysPEM := make([]string, keyCount)
+	prvKeysOpenSSH := make([]string, keyCount)
+	pubKeysPEM := make([]string, keyCount)
+	pubKeysOpenSSH := make([]string, keyCount)
+	pubKeyFingerprintsSHA256 := make([]string, keyCount)
+	for i := 0; i < keyCount; i++ {
+		key, err := keyGen(rand.Reader, d.Get("rsa_bits").(int), d.Get("ecdsa_curve").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		keyPemBlock, err := privateKeyToPEMBlock(key)
+		if err != nil {
+			return diag.Errorf("error encoding key to PEM: %s", err)
+		}
+		prvKeysPEM[i] = string(pem.EncodeToMemory(keyPemBlock))
+
+		pubKey, err := privateKeyToPublicKey(key)
+		if err != nil {
+			return diag.Errorf("failed to get public key from private key: %v", err)
+		}
+		_, pubKeysPEM[i], err = publicKeyToPEM(pubKey)
+		if err != nil {
+			return diag.Errorf("failed to marshal public key: %v", err)
+		}
+
+		// GOTCHA: `x/crypto/ssh` doesn't handle elliptic curve P-224
+		if publicKeySupportsOpenSSH(pubKey) {
+			openSSHKeyPemBlock, err := openssh.MarshalPrivateKey(key, "")
+			if err != nil {
+				return diag.Errorf("unable to marshal private key into OpenSSH format: %v", err)
+			}
+			prvKeysOpenSSH[i] = string(pem.EncodeToMemory(openSSHKeyPemBlock))
+		}
+		pubKeysOpenSSH[i], _, pubKeyFingerprintsSHA256[i] = publicKeyToOpenSSH(pubKey)
+	}
+
+	d.SetId(hashForState(strings.Join(pubKeysPEM, "")))
+
+	if err := d.Set("private_keys_pem", prvKeysPEM); err != nil {
+		return diag.Errorf("error setting value on key 'private_keys_pem': %s", err)
+	}
+	if err := d.Set("private_keys_openssh", prvKeysOpenSSH); err != nil {
+		return diag.Errorf("error setting value on key 'private_keys_openssh': %s", err)
+	}
+	if err := d.Set("public_keys_pem", pubKeysPEM); err != nil {
+		return diag.Errorf("error setting value on key 'public_keys_pem': %s", err)
+	}
+	if err := d.Set("public_keys_openssh", pubKeysOpenSSH); err != nil {
+		return diag.Errorf("error setting value on key 'public_keys_openssh': %s", err)
+	}
+	if err := d.Set("public_key_fingerprints_sha256", pubKeyFingerprintsSHA256); err != nil {
+		return diag.Errorf("error setting value on key 'public_key_fingerprints_sha256': %s", err)
+	}
+
+	return nil
+}
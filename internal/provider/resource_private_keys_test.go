@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPrivateKeys(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_keys" "test" {
+						key_count   = 3
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_keys.test", "private_keys_pem.#", "3"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "private_keys_openssh.#", "3"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "public_keys_pem.#", "3"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "public_keys_openssh.#", "3"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "public_key_fingerprints_sha256.#", "3"),
+					testCheckPEMFormat("tls_private_keys.test", "private_keys_pem.0", PreamblePrivateKeyEC),
+					testCheckPEMFormat("tls_private_keys.test", "private_keys_openssh.1", PreamblePrivateKeyOpenSSH),
+					testCheckPEMFormat("tls_private_keys.test", "public_keys_pem.2", PreamblePublicKey),
+					r.TestMatchResourceAttr("tls_private_keys.test", "public_keys_openssh.0", regexp.MustCompile(`^ecdsa-sha2-nistp256 `)),
+					r.TestMatchResourceAttr("tls_private_keys.test", "public_key_fingerprints_sha256.0", regexp.MustCompile(`^SHA256:`)),
+					testCheckPrivateKeysMatchPublicKeys("tls_private_keys.test", 3),
+				),
+			},
+		},
+	})
+}
+
+func TestPrivateKeys_P224(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_keys" "test" {
+						key_count = 2
+						algorithm = "ECDSA"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_keys.test", "ecdsa_curve", "P224"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "private_keys_pem.#", "2"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "private_keys_openssh.0", ""),
+					r.TestCheckResourceAttr("tls_private_keys.test", "public_keys_openssh.1", ""),
+					r.TestCheckResourceAttr("tls_private_keys.test", "public_key_fingerprints_sha256.1", ""),
+					testCheckPrivateKeysMatchPublicKeys("tls_private_keys.test", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestPrivateKeys_KeyProfile(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_keys" "test" {
+						key_count   = 2
+						key_profile = "fips"
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_private_keys.test", "algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_private_keys.test", "ecdsa_curve", "P384"),
+					r.TestMatchResourceAttr("tls_private_keys.test", "public_keys_openssh.1", regexp.MustCompile(`^ecdsa-sha2-nistp384 `)),
+					testCheckPrivateKeysMatchPublicKeys("tls_private_keys.test", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestPrivateKeys_KeyCount(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_keys" "test" {
+						key_count = 0
+						algorithm = "ED25519"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected key_count to be in the range \(1 - 100\), got 0`),
+			},
+		},
+	})
+}
+
+// testCheckPrivateKeysMatchPublicKeys verifies that each of the private keys generated by a `tls_private_keys`
+// resource matches the public key at the same position, and that all the keys are distinct.
+func testCheckPrivateKeysMatchPublicKeys(name string, expectedCount int) r.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+		attrs := rs.Primary.Attributes
+
+		seen := map[string]bool{}
+		for i := 0; i < expectedCount; i++ {
+			prvKey, _, err := parsePrivateKeyPEM([]byte(attrs[fmt.Sprintf("private_keys_pem.%d", i)]), false)
+			if err != nil {
+				return fmt.Errorf("error parsing private_keys_pem.%d: %s", i, err)
+			}
+			pubKey, err := privateKeyToPublicKey(prvKey)
+			if err != nil {
+				return err
+			}
+			_, pubKeyPEM, err := publicKeyToPEM(pubKey)
+			if err != nil {
+				return err
+			}
+
+			if pubKeyPEM != attrs[fmt.Sprintf("public_keys_pem.%d", i)] {
+				return fmt.Errorf("public_keys_pem.%d does not match private_keys_pem.%d", i, i)
+			}
+			if seen[pubKeyPEM] {
+				return fmt.Errorf("public_keys_pem.%d is not distinct from the other keys", i)
+			}
+			seen[pubKeyPEM] = true
+		}
+
+		return nil
+	}
+}
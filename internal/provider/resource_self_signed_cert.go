@@ -1,13 +1,19 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/rand"
 	"crypto/x509"
-	"net"
-	"net/url"
+	"encoding/pem"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceSelfSignedCert() *schema.Resource {
@@ -16,67 +22,286 @@ func resourceSelfSignedCert() *schema.Resource {
 	setCertificateCommonSchema(s)
 	setCertificateSubjectSchema(s)
 
+	// When `cert_request_pem` is provided, subject and SANs are sourced from the Certificate Request instead
+	s["subject"].Required = false
+	s["subject"].Optional = true
+	s["subject"].ExactlyOneOf = []string{"subject", "cert_request_pem"}
+	s["subject_rdn_order"].RequiredWith = []string{"subject"}
+	s["subject_serial_from_key"].ConflictsWith = []string{"subject.0.serial_number", "cert_request_pem"}
+
+	s["cert_request_pem"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ExactlyOneOf:  []string{"subject", "cert_request_pem"},
+		ConflictsWith: []string{"dns_names", "ip_addresses", "uris", "san"},
+		StateFunc: func(v interface{}) string {
+			return hashForState(v.(string))
+		},
+		Description: "Certificate request data in " +
+			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+			"When provided, the subject and the Subject Alternative Names (DNS names, email addresses, IP addresses and URIs) " +
+			"of the certificate are sourced from the certificate request: the request must have been created with the same private key. " +
+			"This is _mutually exclusive_ with `subject`.",
+	}
+
+	// When `generate_key` is provided, the private key is generated by this resource instead
+	s["private_key_pem"].ExactlyOneOf = []string{"private_key_pem", "private_key_pem_file", "generate_key"}
+	s["private_key_pem_file"].ExactlyOneOf = []string{"private_key_pem", "private_key_pem_file", "generate_key"}
+
+	s["generate_key"] = &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		ForceNew:     true,
+		MaxItems:     1,
+		ExactlyOneOf: []string{"private_key_pem", "private_key_pem_file", "generate_key"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"algorithm": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Computed:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedAlgorithmsStr(), false)),
+					Description: "Name of the algorithm to use when generating the private key. " +
+						"Currently-supported values are `RSA`, `ECDSA` and `ED25519`. " +
+						"If not set, the provider `default_key_algorithm` is used: one of the two must be set.",
+				},
+				"rsa_bits": {
+					Type:     schema.TypeInt,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+					Description: "When `algorithm` is `RSA`, the size of the generated RSA key, in bits. " +
+						"If not set, the provider `default_rsa_bits` is used (default: `2048`).",
+				},
+				"ecdsa_curve": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ForceNew:         true,
+					Default:          P224,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(SupportedECDSACurvesAndAliasesStr(), false)),
+					StateFunc: func(v interface{}) string {
+						return NormalizeECDSACurve(v.(string)).String()
+					},
+					Description: "When `algorithm` is `ECDSA`, the name of the elliptic curve to use. " +
+						"Currently-supported values are `P224`, `P256`, `P384` or `P521` (default: `P224`). " +
+						"The key sizes `224`, `256`, `384` and `521` are also accepted as aliases, and normalized to the curve name.",
+				},
+				"private_key_pem": {
+					Type:      schema.TypeString,
+					Computed:  true,
+					Sensitive: true,
+					Description: "The generated private key, in [PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format. " +
+						"Unlike the `private_key_pem` argument, this is stored in the Terraform state as-is.",
+				},
+			},
+		},
+		Description: "Generate the private key of the certificate, instead of providing it via `private_key_pem` " +
+			"or `private_key_pem_file`: this is a shortcut for the simple cases that would otherwise need " +
+			"a separate `tls_private_key` resource. The generated key is stored, unencrypted, in the Terraform state. " +
+			"This is _mutually exclusive_ with `private_key_pem` and `private_key_pem_file`.",
+	}
+
+	// A change of `private_key_pem` forces a new certificate only if the key itself changed:
+	// a different encoding of the same key (e.g. PKCS#1 instead of PKCS#8) is ignored
+	s["private_key_pem"].DiffSuppressFunc = suppressPrivateKeyPEMSameSPKIDiff
+	s["private_key_pem"].Description += " Changing this to a different key forces a new certificate to be created, " +
+		"while changing it to a different encoding of the same key (as per `public_key_spki_sha256`) is ignored."
+
+	s["public_key_spki_sha256"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: "Hexadecimal representation of the SHA256 checksum of the public key of the certificate, " +
+			"in PKIX `SubjectPublicKeyInfo` DER format. This identifies the private key of the certificate, " +
+			"regardless of the format it's provided in.",
+	}
+
+	s["is_self_signed"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Computed: true,
+		Description: "Is the certificate actually self-signed, i.e. is its issuer identical to its subject, " +
+			"and does its signature verify against its own public key? " +
+			"A certificate that is only self-issued (i.e. with an issuer identical to its subject, " +
+			"but signed with a different key) is not self-signed.",
+	}
+
 	return &schema.Resource{
 		CreateContext: createSelfSignedCert,
 		DeleteContext: deleteCertificate,
 		ReadContext:   readCertificate,
 		UpdateContext: updateCertificate,
-		CustomizeDiff: customizeCertificateDiff,
+		CustomizeDiff: customdiff.All(customizeCertificateDiff, customizeSubjectAlternativeNamesDiff, customizeNameConstraintsDiff, customizeMaxPathLengthDiff, customizeValidityPeriodDiff, customizeKeyAlgorithmDiff("generate_key"), customizeFIPSKeyDiff("generate_key")),
 		Schema:        s,
 		Description: "Creates a **self-signed** TLS certificate in " +
 			"[PEM (RFC 1421)](https://datatracker.ietf.org/doc/html/rfc1421) format.",
 	}
 }
 
-func createSelfSignedCert(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	key, algorithm, err := parsePrivateKeyPEM([]byte(d.Get("private_key_pem").(string)))
-	if err != nil {
-		return diag.FromErr(err)
+func createSelfSignedCert(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	issuanceStart := time.Now()
+
+	var key crypto.PrivateKey
+	var algorithm Algorithm
+	var err error
+	if len(d.Get("generate_key").([]interface{})) > 0 {
+		var diags diag.Diagnostics
+		key, algorithm, diags = generateSelfSignedCertKey(d, m.(*providerConfig))
+		if diags.HasError() {
+			return diags
+		}
+	} else {
+		key, algorithm, err = parsePrivateKeyPEMAttribute(d, "private_key_pem", m.(*providerConfig).strictPEM)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	if err := d.Set("key_algorithm", algorithm); err != nil {
 		return diag.Errorf("error setting value on key 'key_algorithm': %s", err)
 	}
 
-	subjectConfs := d.Get("subject").([]interface{})
-	if len(subjectConfs) != 1 {
-		return diag.Errorf("must have exactly one 'subject' block")
+	if diags := setKeyParametersAttributes(d, "", key); diags.HasError() {
+		return diags
 	}
-	subjectConf, ok := subjectConfs[0].(map[string]interface{})
-	if !ok {
-		return diag.Errorf("subject block cannot be empty")
+
+	publicKey, err := privateKeyToPublicKey(key)
+	if err != nil {
+		return diag.Errorf("failed to get public key from private key: %v", err)
 	}
-	subject := distinguishedNamesFromSubjectAttributes(subjectConf)
 
 	cert := x509.Certificate{
-		Subject:               *subject,
 		BasicConstraintsValid: true,
 	}
 
-	dnsNamesI := d.Get("dns_names").([]interface{})
-	for _, nameI := range dnsNamesI {
-		cert.DNSNames = append(cert.DNSNames, nameI.(string))
-	}
-	ipAddressesI := d.Get("ip_addresses").([]interface{})
-	for _, ipStrI := range ipAddressesI {
-		ip := net.ParseIP(ipStrI.(string))
-		if ip == nil {
-			return diag.Errorf("invalid IP address %#v", ipStrI.(string))
+	if _, ok := d.GetOk("cert_request_pem"); ok {
+		certReq, err := parseCertificateRequest(d, "cert_request_pem")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := certReq.CheckSignature(); err != nil {
+			return diag.Errorf("invalid signature of cert_request_pem: %s", err)
+		}
+
+		if !publicKeysEqual(certReq.PublicKey, publicKey) {
+			return diag.Errorf("the public key of cert_request_pem doesn't match the private key")
+		}
+
+		cert.Subject = certReq.Subject
+		cert.DNSNames = certReq.DNSNames
+		cert.EmailAddresses = certReq.EmailAddresses
+		cert.IPAddresses = certReq.IPAddresses
+		cert.URIs = certReq.URIs
+	} else {
+		if len(d.Get("subject").([]interface{})) != 1 {
+			return diag.Errorf("must have exactly one 'subject' block")
+		}
+		if diags := setCertificateNamesFromConfig(d, &cert, publicKey); diags.HasError() {
+			return diags
 		}
-		cert.IPAddresses = append(cert.IPAddresses, ip)
 	}
-	urisI := d.Get("uris").([]interface{})
-	for _, uriStrI := range urisI {
-		uri, err := url.Parse(uriStrI.(string))
-		if err != nil {
-			return diag.Errorf("invalid URI %#v", uriStrI.(string))
+
+	diags := createCertificate(d, m.(*providerConfig), &cert, &cert, publicKey, key)
+	if diags.HasError() {
+		return diags
+	}
+	if setDiags := setIssuanceDuration(d, issuanceStart); setDiags.HasError() {
+		return append(diags, setDiags...)
+	}
+
+	certs, err := parseCertificatesPEM([]byte(d.Get("cert_pem").(string)))
+	if err != nil {
+		return append(diags, diag.Errorf("error parsing certificate: %s", err)...)
+	}
+	if err := d.Set("is_self_signed", isSelfSigned(certs[0])); err != nil {
+		return append(diags, diag.Errorf("error setting value on key 'is_self_signed': %s", err)...)
+	}
+
+	if err := d.Set("public_key_spki_sha256", contentHash(certs[0].RawSubjectPublicKeyInfo)); err != nil {
+		return append(diags, diag.Errorf("error setting value on key 'public_key_spki_sha256': %s", err)...)
+	}
+
+	return diags
+}
+
+// suppressPrivateKeyPEMSameSPKIDiff suppresses the diff of `private_key_pem`, when the configured private key
+// has the same public key as the one of the certificate, as recorded in `public_key_spki_sha256`.
+//
+// NOTE: The state only holds a hash of `private_key_pem`, so the configured key is read from the configuration;
+// for certificates created before `public_key_spki_sha256` was introduced, the diff is never suppressed.
+func suppressPrivateKeyPEMSameSPKIDiff(_, old, _ string, d *schema.ResourceData) bool {
+	spkiSHA256 := d.Get("public_key_spki_sha256").(string)
+	if old == "" || spkiSHA256 == "" {
+		return false
+	}
+
+	prvKey, _, err := parsePrivateKeyPEM([]byte(d.Get("private_key_pem").(string)), false)
+	if err != nil {
+		return false
+	}
+	pubKey, err := privateKeyToPublicKey(prvKey)
+	if err != nil {
+		return false
+	}
+	pubKeySPKISHA256, err := publicKeySPKISHA256(pubKey)
+	if err != nil {
+		return false
+	}
+
+	return pubKeySPKISHA256 == spkiSHA256
+}
+
+// generateSelfSignedCertKey generates the private key described by the `generate_key` block,
+// falling back to the provider defaults for the algorithm and the RSA key size,
+// and sets the resolved parameters and the generated key back on the block.
+func generateSelfSignedCertKey(d *schema.ResourceData, config *providerConfig) (crypto.PrivateKey, Algorithm, diag.Diagnostics) {
+	// Resolve the key parameters, falling back to the provider defaults
+	// NOTE: the raw configuration is used, as an empty `generate_key {}` block would be read as a nil element
+	keyAlgoName, rsaBits, ecdsaCurve, _ := resolveKeyParameters(d.GetRawConfig().GetAttr("generate_key").Index(cty.NumberIntVal(0)), config)
+	if keyAlgoName == "" {
+		return nil, "", diag.FromErr(errMissingKeyAlgorithm("generate_key"))
+	}
+	if config.fipsMode {
+		if err := checkFIPSKeyParameters(keyAlgoName, rsaBits, ecdsaCurve); err != nil {
+			return nil, "", diag.Errorf("invalid generate_key: %s", err)
 		}
-		cert.URIs = append(cert.URIs, uri)
 	}
 
-	publicKey, err := privateKeyToPublicKey(key)
+	keyGen, ok := keyGenerators[keyAlgoName]
+	if !ok {
+		return nil, "", diag.Errorf("invalid generate_key.0.algorithm %#v", keyAlgoName)
+	}
+
+	key, err := keyGen(rand.Reader, rsaBits, ecdsaCurve.String())
 	if err != nil {
-		return diag.Errorf("failed to get public key from private key: %v", err)
+		return nil, "", diag.FromErr(err)
 	}
-	return createCertificate(d, &cert, &cert, publicKey, key)
+
+	keyPemBlock, err := privateKeyToPEMBlock(key)
+	if err != nil {
+		return nil, "", diag.Errorf("error encoding key to PEM: %s", err)
+	}
+
+	if err := d.Set("generate_key", []interface{}{map[string]interface{}{
+		"algorithm":       keyAlgoName.String(),
+		"rsa_bits":        rsaBits,
+		"ecdsa_curve":     ecdsaCurve.String(),
+		"private_key_pem": string(pem.EncodeToMemory(keyPemBlock)),
+	}}); err != nil {
+		return nil, "", diag.Errorf("error setting value on key 'generate_key': %s", err)
+	}
+
+	return key, keyAlgoName, nil
+}
+
+// isSelfSigned returns true if the issuer of the given certificate is identical to its subject,
+// and its signature verifies against its own public key.
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+
+	// NOTE: `CheckSignatureFrom` can't be used, as it requires the parent to be a CA certificate
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
 }
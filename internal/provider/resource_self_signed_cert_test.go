@@ -2,12 +2,19 @@ package provider
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -250,6 +257,69 @@ func TestAccSelfSignedCertNotRecreatedForEarlyRenewalUpdateInFuture(t *testing.T
 	overridableTimeFunc = oldNow
 }
 
+func TestAccSelfSignedCertEarlyRenewalJitter(t *testing.T) {
+	oldNow := overridableTimeFunc
+	var previousCert string
+	// NOTE: the serial number is fixed, so that the jitter derived from the resource ID is known:
+	// for ID "42" and 4 hours of jitter it is 2h2m43s, so the early renewal starts at 17:57:17.
+	config := fmt.Sprintf(`
+		resource "tls_self_signed_cert" "test" {
+			subject {
+				common_name = "example.com"
+			}
+			certificate_serial_hex     = "2A"
+			validity_period_hours      = 10
+			early_renewal_hours        = 2
+			early_renewal_jitter_hours = 4
+			allowed_uses               = []
+			private_key_pem = <<EOT
+%s
+EOT
+		}
+	`, testPrivateKeyPEM)
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		PreCheck:          setTimeForTest("2019-06-14T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: config,
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "id", "42"),
+					r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+						previousCert = value
+						return nil
+					}),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2019-06-14T17:50:00Z"),
+				Config:    config,
+				Check: r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+					if previousCert != value {
+						return fmt.Errorf("certificate updated even though not enough time has passed")
+					}
+
+					previousCert = value
+					return nil
+				}),
+			},
+			{
+				PreConfig: setTimeForTest("2019-06-14T18:00:00Z"),
+				Config:    config,
+				Check: r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+					if previousCert == value {
+						return fmt.Errorf("certificate not updated even though passed jittered early renewal")
+					}
+
+					previousCert = value
+					return nil
+				}),
+			},
+		},
+	})
+	overridableTimeFunc = oldNow
+}
+
 func TestAccSelfSignedCertSetSubjectKeyID(t *testing.T) {
 	r.UnitTest(t, r.TestCase{
 		ProviderFactories: testProviders,
@@ -317,10 +387,199 @@ func TestAccSelfSignedCert_InvalidConfigs(t *testing.T) {
 				`,
 				ExpectError: regexp.MustCompile(`expected early_renewal_hours to be at least \(0\), got -10`),
 			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+							country = "USA"
+						}
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected country to be a two letters country code, got "USA"`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						subject {
+							common_name = "other test cert"
+						}
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`(Too many subject blocks|Too many list items)`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						dns_names = ["example.com", "127.0.0.1"]
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected dns_names to contain DNS names, got IP address "127.0.0.1": use ip_addresses instead`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						dns_names = ["https://example.com"]
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected dns_names to contain DNS names, got URI "https://example.com": use uris instead`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						ip_addresses = ["example.com"]
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected ip_addresses to contain a valid IP, got: example.com`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						uris = ["example-trust-domain/workload"]
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`expected uris to contain absolute URIs, with a scheme, got "example-trust-domain/workload"`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "common test cert"
+						}
+						dns_names = ["example.com", "example.net", "example.com"]
+						validity_period_hours = 20
+						allowed_uses = [
+						]
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`dns_names.2: duplicate value "example.com", already present at dns_names.0`),
+			},
 		},
 	})
 }
 
+func TestAccResourceSelfSignedCert_DNSNameSyntax(t *testing.T) {
+	config := func(dnsNames, extra string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				private_key_pem = <<EOT
+%s
+EOT
+				subject {
+					common_name = "example.com"
+				}
+				dns_names             = %s
+				validity_period_hours = 1
+				allowed_uses          = ["server_auth"]
+				%s
+			}
+		`, testPrivateKeyPEM, dnsNames, extra)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(`["example.com", "*.example.com", "xn--bcher-kva.example", "a-1.EXAMPLE.net"]`, ""),
+				Check: testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{
+					"example.com", "*.example.com", "xn--bcher-kva.example", "a-1.EXAMPLE.net",
+				}),
+			},
+			{
+				Config:      config(`["example.com."]`, ""),
+				ExpectError: regexp.MustCompile(`dns_names.0: "example.com." is not a valid DNS name: trailing dots are not\s+allowed \(set skip_dns_name_syntax_check to accept it anyway\)`),
+			},
+			{
+				Config:      config(`["example.com", "_acme-challenge.example.com"]`, ""),
+				ExpectError: regexp.MustCompile(`dns_names.1: "_acme-challenge.example.com" is not a valid DNS name: label\s+"_acme-challenge" contains '_'`),
+			},
+			{
+				Config:      config(`["www.*.example.com"]`, ""),
+				ExpectError: regexp.MustCompile(`a wildcard can only be the whole leftmost label`),
+			},
+			{
+				Config:      config(`["-example.com"]`, ""),
+				ExpectError: regexp.MustCompile(`label "-example" starts or ends with a hyphen`),
+			},
+			{
+				Config:      config(`["bücher.example"]`, ""),
+				ExpectError: regexp.MustCompile(`internationalized names must be in their ASCII form`),
+			},
+			{
+				Config: config(`["_acme-challenge.example.com", "example.com."]`, "skip_dns_name_syntax_check = true"),
+				Check: testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{
+					"_acme-challenge.example.com", "example.com.",
+				}),
+			},
+		},
+	})
+}
+
+func TestCheckDNSNameSyntax(t *testing.T) {
+	for _, tc := range []struct {
+		dnsName string
+		valid   bool
+	}{
+		{"example.com", true},
+		{"*.example.com", true},
+		{"localhost", true},
+		{"xn--bcher-kva.example", true},
+		{strings.Repeat("a", 63) + ".example.com", true},
+		{strings.Repeat("a", 64) + ".example.com", false},
+		{"*", false},
+		{"*example.com", false},
+		{"example..com", false},
+		{".example.com", false},
+		{"example-.com", false},
+		{"exa mple.com", false},
+		{"exa_mple.com", false},
+	} {
+		if err := checkDNSNameSyntax(tc.dnsName); (err == nil) != tc.valid {
+			t.Errorf("%q: expected valid to be %t, got error %v", tc.dnsName, tc.valid, err)
+		}
+	}
+}
+
 func selfSignedCertConfig(validity, earlyRenewal uint32) string {
 	return fmt.Sprintf(`
         resource "tls_self_signed_cert" "test1" {
@@ -439,6 +698,10 @@ func TestAccResourceSelfSignedCert_FromED25519PrivateKeyResource(t *testing.T) {
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "ED25519"),
+					testCheckAttrBase64Of("tls_self_signed_cert.test", "cert_pem_base64", "cert_pem"),
+					testCheckAttrBase64URLOf("tls_self_signed_cert.test", "cert_pem_base64url", "cert_pem_base64"),
+					testCheckAttrContentHashOf("tls_self_signed_cert.test", "content_hash", "cert_pem"),
+					testCheckPEMCertificateThumbprints("tls_self_signed_cert.test", "cert_pem"),
 					testCheckPEMFormat("tls_self_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
@@ -471,6 +734,8 @@ func TestAccResourceSelfSignedCert_FromECDSAPrivateKeyResource(t *testing.T) {
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "ecdsa_curve", "P521"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "rsa_bits", "0"),
 					testCheckPEMFormat("tls_self_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
@@ -502,9 +767,1901 @@ func TestAccResourceSelfSignedCert_FromRSAPrivateKeyResource(t *testing.T) {
 				`,
 				Check: r.ComposeTestCheckFunc(
 					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "rsa_bits", "4096"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "ecdsa_curve", ""),
 					testCheckPEMFormat("tls_self_signed_cert.test", "cert_pem", PreambleCertificate),
 				),
 			},
 		},
 	})
 }
+
+func TestAccResourceSelfSignedCert_CertificateSerialHex(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						certificate_serial_hex = "7fffffffffffffffffffffffffffffffffffffff"
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "id", "730750818665451459101842416358141509827966271487"),
+					testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						if got, want := fmt.Sprintf("%x", cert.SerialNumber), "7fffffffffffffffffffffffffffffffffffffff"; got != want {
+							return fmt.Errorf("incorrect serial number: expected %s, got %s", want, got)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_InvalidCertificateSerialHex(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						certificate_serial_hex = "not-hex"
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`"not-hex" is not a valid hexadecimal number`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						certificate_serial_hex = "0"
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`serial number must be positive`),
+			},
+			{
+				Config: `
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						certificate_serial_hex = "8000000000000000000000000000000000000000"
+						private_key_pem = "does not matter"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`serial number must not exceed 20 octets`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_ProviderDefaultValidityPeriod(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					provider "tls" {
+						default_validity_period_hours = 2
+					}
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						allowed_uses = []
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_period_hours", "2"),
+					testCheckPEMCertificateDuration("tls_self_signed_cert.test", "cert_pem", 2*time.Hour),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					provider "tls" {
+						default_validity_period_hours = 2
+					}
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_period_hours", "1"),
+					testCheckPEMCertificateDuration("tls_self_signed_cert.test", "cert_pem", time.Hour),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test_no_validity" {
+						subject {
+							common_name = "example.com"
+						}
+						allowed_uses = []
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`missing validity period: either set 'validity_period_hours',\s+'validity_period_days' or the provider 'default_validity_period_hours'`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_ValidityPeriodDays(t *testing.T) {
+	config := func(validity string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				subject {
+					common_name = "example.com"
+				}
+				%s
+				early_renewal_hours = 2
+				allowed_uses        = []
+				private_key_pem     = <<EOT
+%s
+EOT
+			}
+		`, validity, testPrivateKeyPEM)
+	}
+
+	oldNow := overridableTimeFunc
+	var previousCert string
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		PreCheck:          setTimeForTest("2019-06-14T12:00:00Z"),
+		Steps: []r.TestStep{
+			{
+				Config: config("validity_period_days = 1"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_period_days", "1"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_period_hours", "24"),
+					testCheckPEMCertificateDuration("tls_self_signed_cert.test", "cert_pem", 24*time.Hour),
+					r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+						previousCert = value
+						return nil
+					}),
+				),
+			},
+			{
+				PreConfig: setTimeForTest("2019-06-15T09:00:00Z"),
+				Config:    config("validity_period_days = 1"),
+				Check: r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+					if previousCert != value {
+						return fmt.Errorf("certificate updated even though not enough time has passed")
+					}
+					return nil
+				}),
+			},
+			{
+				PreConfig: setTimeForTest("2019-06-15T11:00:00Z"),
+				Config:    config("validity_period_days = 1"),
+				Check: r.TestCheckResourceAttrWith("tls_self_signed_cert.test", "cert_pem", func(value string) error {
+					if previousCert == value {
+						return fmt.Errorf("certificate not updated even though passed early renewal")
+					}
+					return nil
+				}),
+			},
+			{
+				Config: config("validity_period_days = 825"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_period_hours", "19800"),
+					testCheckPEMCertificateDuration("tls_self_signed_cert.test", "cert_pem", 825*24*time.Hour),
+				),
+			},
+			{
+				Config: config(`
+					validity_period_days  = 1
+					validity_period_hours = 24
+				`),
+				ExpectError: regexp.MustCompile(`"validity_period_days": conflicts with validity_period_hours`),
+			},
+			{
+				Config:      config("validity_period_days = -1"),
+				ExpectError: regexp.MustCompile(`expected validity_period_days to be at least \(0\), got -1`),
+			},
+		},
+	})
+	overridableTimeFunc = oldNow
+}
+
+func TestAccResourceSelfSignedCert_GenerateKey(t *testing.T) {
+	config := func(generateKey string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				generate_key {
+					%s
+				}
+				subject {
+					common_name = "example.com"
+				}
+				validity_period_hours = 1
+				allowed_uses          = ["server_auth"]
+			}
+		`, generateKey)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(`algorithm = "RSA"`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "generate_key.0.algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "generate_key.0.rsa_bits", "2048"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "RSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "rsa_bits", "2048"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "is_self_signed", "true"),
+					testCheckPEMFormat("tls_self_signed_cert.test", "generate_key.0.private_key_pem", PreamblePrivateKeyRSA),
+					testCheckPEMCertificatePrivateKey("tls_self_signed_cert.test", "cert_pem", "generate_key.0.private_key_pem"),
+				),
+			},
+			{
+				Config: config(`
+					algorithm   = "ECDSA"
+					ecdsa_curve = "256"
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "generate_key.0.ecdsa_curve", "P256"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "ecdsa_curve", "P256"),
+					testCheckPEMFormat("tls_self_signed_cert.test", "generate_key.0.private_key_pem", PreamblePrivateKeyEC),
+					testCheckPEMCertificatePrivateKey("tls_self_signed_cert.test", "cert_pem", "generate_key.0.private_key_pem"),
+				),
+			},
+			{
+				Config: config(`algorithm = "ED25519"`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "ED25519"),
+					testCheckPEMFormat("tls_self_signed_cert.test", "generate_key.0.private_key_pem", PreamblePrivateKeyPKCS8),
+					testCheckPEMCertificatePrivateKey("tls_self_signed_cert.test", "cert_pem", "generate_key.0.private_key_pem"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						generate_key {
+							algorithm = "RSA"
+						}
+						private_key_pem = <<EOT
+%s
+EOT
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`only one of\s+` + "`" + `generate_key,private_key_pem,private_key_pem_file` + "`" + `\s+can\s+be\s+specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_CertificatePolicies(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						certificate_policy {
+							policy_identifier = "2.23.140.1.2.1"
+							cps_uris          = ["https://example.com/cps", "http://example.com/cps"]
+							user_notice_text  = "Relying parties: see the CPS – à bientôt"
+						}
+						certificate_policy {
+							policy_identifier = "1.3.6.1.4.1.44947.1.1.1"
+						}
+						validity_period_hours = 1
+						allowed_uses          = []
+						private_key_pem       = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificatePolicies("tls_self_signed_cert.test", "cert_pem", []certificatePolicy{
+					{
+						identifier:     asn1.ObjectIdentifier{2, 23, 140, 1, 2, 1},
+						cpsURIs:        []string{"https://example.com/cps", "http://example.com/cps"},
+						userNoticeText: "Relying parties: see the CPS – à bientôt",
+					},
+					{
+						identifier: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 44947, 1, 1, 1},
+					},
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						certificate_policy {
+							policy_identifier = "2.23.140.1.2.1"
+							user_notice_text  = "%s"
+						}
+						validity_period_hours = 1
+						allowed_uses          = []
+						private_key_pem       = "does not matter"
+					}
+				`, strings.Repeat("a", 201)),
+				ExpectError: regexp.MustCompile(`expected length of certificate_policy.0.user_notice_text to be in the range \(1 - 200\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SCTList(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						sct_list_base64 = [
+							"AAEC",
+							"AwQFBg==",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+					for _, ext := range cert.Extensions {
+						if !ext.Id.Equal(oidExtensionSCTList) {
+							continue
+						}
+
+						var list []byte
+						if _, err := asn1.Unmarshal(ext.Value, &list); err != nil {
+							return fmt.Errorf("failed to unmarshal SCT list extension: %s", err)
+						}
+
+						want := []byte{0, 11, 0, 3, 0, 1, 2, 0, 4, 3, 4, 5, 6}
+						if !bytes.Equal(list, want) {
+							return fmt.Errorf("incorrect SCT list\ngot:  %v\nwant: %v", list, want)
+						}
+						return nil
+					}
+
+					return fmt.Errorf("SCT list extension not found")
+				}),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_FromCertRequest(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "test" {
+						private_key_pem = tls_private_key.test.private_key_pem
+						subject {
+							common_name  = "example.com"
+							organization = "Example, Inc"
+						}
+						dns_names = [
+							"example.com",
+							"example.net",
+						]
+						ip_addresses = [
+							"127.0.0.1",
+						]
+					}
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem       = tls_private_key.test.private_key_pem
+						cert_request_pem      = tls_cert_request.test.cert_request_pem
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMFormat("tls_self_signed_cert.test", "cert_pem", PreambleCertificate),
+					testCheckPEMCertificateSubject("tls_self_signed_cert.test", "cert_pem", &pkix.Name{
+						CommonName:   "example.com",
+						Organization: []string{"Example, Inc"},
+					}),
+					testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{
+						"example.com",
+						"example.net",
+					}),
+					testCheckPEMCertificateIPAddresses("tls_self_signed_cert.test", "cert_pem", []net.IP{
+						net.ParseIP("127.0.0.1"),
+					}),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "test" {
+						private_key_pem = tls_private_key.test.private_key_pem
+						subject {
+							common_name = "example.com"
+						}
+						san {
+							type  = "dns"
+							value = "example.com"
+						}
+						san {
+							type  = "email"
+							value = "admin@example.com"
+						}
+					}
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem       = tls_private_key.test.private_key_pem
+						cert_request_pem      = tls_cert_request.test.cert_request_pem
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{
+						"example.com",
+					}),
+					testCheckPEMCertificateEmailAddresses("tls_self_signed_cert.test", "cert_pem", []string{
+						"admin@example.com",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_FromCertRequestInvalidConfigs(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm = "ED25519"
+					}
+					resource "tls_private_key" "other" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "test" {
+						private_key_pem = tls_private_key.other.private_key_pem
+						subject {
+							common_name = "example.com"
+						}
+					}
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem       = tls_private_key.test.private_key_pem
+						cert_request_pem      = tls_cert_request.test.cert_request_pem
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`,
+				ExpectError: regexp.MustCompile(`the public key of cert_request_pem doesn't match private_key_pem`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem       = "does not matter"
+						cert_request_pem      = <<EOT
+%s
+EOT
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`, testCertRequest),
+				ExpectError: regexp.MustCompile("only one of `cert_request_pem,subject` can be specified"),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SignatureAlgorithm(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "SHA384-RSAPSS"
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+					if cert.SignatureAlgorithm != x509.SHA384WithRSAPSS {
+						return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.SHA384WithRSAPSS, cert.SignatureAlgorithm)
+					}
+					return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "ECDSA-SHA256"
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile("signature_algorithm ECDSA-SHA256 cannot be used with RSA keys: accepted values are SHA256-RSA, SHA256-RSAPSS, SHA384-RSA, SHA384-RSAPSS, SHA512-RSA, SHA512-RSAPSS"),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "MD5-RSA"
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected signature_algorithm to be one of \[.*\], got MD5-RSA`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SignatureAlgorithmIndependentOfCurve(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P256"
+					}
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "ECDSA-SHA384"
+						private_key_pem = tls_private_key.test.private_key_pem
+					}
+				`,
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+					if cert.SignatureAlgorithm != x509.ECDSAWithSHA384 {
+						return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.ECDSAWithSHA384, cert.SignatureAlgorithm)
+					}
+					if _, ecdsaCurve := publicKeyParameters(cert.PublicKey); ecdsaCurve != P256 {
+						return fmt.Errorf("incorrect ECDSA curve: expected %v, got %v", P256, ecdsaCurve)
+					}
+					return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+				}),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P384"
+					}
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "ECDSA-SHA256"
+						private_key_pem = tls_private_key.test.private_key_pem
+					}
+				`,
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+					if cert.SignatureAlgorithm != x509.ECDSAWithSHA256 {
+						return fmt.Errorf("incorrect signature algorithm: expected %v, got %v", x509.ECDSAWithSHA256, cert.SignatureAlgorithm)
+					}
+					return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+				}),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "test" {
+						algorithm   = "ECDSA"
+						ecdsa_curve = "P384"
+					}
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						signature_algorithm = "Ed25519"
+						private_key_pem = tls_private_key.test.private_key_pem
+					}
+				`,
+				ExpectError: regexp.MustCompile("signature_algorithm Ed25519 cannot be used with ECDSA keys: accepted values are ECDSA-SHA256, ECDSA-SHA384, ECDSA-SHA512"),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_KeyUsages(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						key_usages = [
+							"digital_signature",
+							"key_agreement",
+						]
+						extended_key_usages = [
+							"code_signing",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateKeyUsage("tls_self_signed_cert.test", "cert_pem", x509.KeyUsageDigitalSignature|x509.KeyUsageKeyAgreement),
+					testCheckPEMCertificateExtKeyUsages("tls_self_signed_cert.test", "cert_pem", []x509.ExtKeyUsage{
+						x509.ExtKeyUsageCodeSigning,
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = [
+							"key_encipherment",
+							"server_auth",
+						]
+						key_usages = [
+							"digital_signature",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateKeyUsage("tls_self_signed_cert.test", "cert_pem", x509.KeyUsageDigitalSignature),
+					testCheckPEMCertificateExtKeyUsages("tls_self_signed_cert.test", "cert_pem", []x509.ExtKeyUsage{
+						x509.ExtKeyUsageServerAuth,
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						key_usages = [
+							"server_auth",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected key_usages to be one of \[.*\], got server_auth`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SubjectInfoAccess(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						is_ca_certificate = true
+						subject_info_access {
+							access_method = "1.3.6.1.5.5.7.48.5"
+							url           = "http://repository.example.com/ca"
+						}
+						subject_info_access {
+							access_method = "1.3.6.1.5.5.7.48.3"
+							url           = "http://timestamp.example.com"
+						}
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateSubjectInfoAccess("tls_self_signed_cert.test", "cert_pem", map[string]string{
+					"1.3.6.1.5.5.7.48.5": "http://repository.example.com/ca",
+					"1.3.6.1.5.5.7.48.3": "http://timestamp.example.com",
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						subject_info_access {
+							access_method = "ca_repository"
+							url           = "http://repository.example.com/ca"
+						}
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`invalid access_method: "ca_repository" is not a valid object identifier`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_FreshestCRL(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						freshest_crl_distribution_points = [
+							"http://crl.example.com/delta.crl",
+							"ldap://ldap.example.com/cn=Example%%20CA?deltaRevocationList",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateFreshestCRL("tls_self_signed_cert.test", "cert_pem", []string{
+					"http://crl.example.com/delta.crl",
+					"ldap://ldap.example.com/cn=Example%20CA?deltaRevocationList",
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						freshest_crl_distribution_points = ["ftp://crl.example.com/delta.crl"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected "freshest_crl_distribution_points" to have a url with schema of:\s+"http,https,ldap,ldaps", got ftp://crl.example.com/delta.crl`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_AIAAndCRLDistributionPoints(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						ocsp_servers = [
+							"http://ocsp2.example.com",
+							"http://ocsp1.example.com",
+							"http://ocsp2.example.com",
+						]
+						issuing_certificate_urls = [
+							"http://z.example.com/ca.crt",
+							"ldap://ldap.example.com/cn=Example%%20CA?cACertificate",
+							"http://a.example.com/ca.crt",
+						]
+						crl_distribution_points = [
+							"ldap://ldap.example.com/cn=Example%%20CA?certificateRevocationList",
+							"http://crl.example.com/b.crl",
+							"http://crl.example.com/a.crl",
+						]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateAIAAndCRLDistributionPoints("tls_self_signed_cert.test", "cert_pem",
+					[]string{
+						"http://ocsp2.example.com",
+						"http://ocsp1.example.com",
+						"http://ocsp2.example.com",
+					},
+					[]string{
+						"http://z.example.com/ca.crt",
+						"ldap://ldap.example.com/cn=Example%20CA?cACertificate",
+						"http://a.example.com/ca.crt",
+					},
+					[]string{
+						"ldap://ldap.example.com/cn=Example%20CA?certificateRevocationList",
+						"http://crl.example.com/b.crl",
+						"http://crl.example.com/a.crl",
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses = []
+						ocsp_servers = ["ldap://ocsp.example.com"]
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`expected "ocsp_servers" to have a url with schema of:\s+"http,https", got ldap://ocsp.example.com`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SubjectRDNOrder(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						subject {
+							common_name  = "example.com"
+							organization = "Example, Inc"
+							country      = "GB"
+						}
+						subject_rdn_order = ["common_name"]
+						validity_period_hours = 1
+						allowed_uses = []
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testPrivateKeyPEM),
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(crt *x509.Certificate) error {
+					if !bytes.Equal(crt.RawIssuer, crt.RawSubject) {
+						return fmt.Errorf("issuer of self-signed certificate doesn't match its subject")
+					}
+					return compareSubjectRDNOrder([]asn1.ObjectIdentifier{
+						subjectAttributeTypes["common_name"],
+						subjectAttributeTypes["country"],
+						subjectAttributeTypes["organization"],
+					}, crt.RawSubject)
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						cert_request_pem = <<EOT
+%s
+EOT
+						subject_rdn_order = ["common_name"]
+						validity_period_hours = 1
+						allowed_uses = []
+						private_key_pem = <<EOT
+%s
+EOT
+					}
+				`, testCertRequest, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`"subject_rdn_order": all of\s+` + "`subject,subject_rdn_order`" + `\s+must be specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SANOrder(t *testing.T) {
+	config := func(sans string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				subject {
+					common_name = "example.com"
+				}
+				%s
+				validity_period_hours = 1
+				allowed_uses = []
+				private_key_pem = <<EOT
+%s
+EOT
+			}
+		`, sans, testPrivateKeyPEM)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(`
+					san {
+						type  = "ip"
+						value = "127.0.0.1"
+					}
+					san {
+						type  = "dns"
+						value = "example.com"
+					}
+					san {
+						type  = "email"
+						value = "admin@example.com"
+					}
+					san {
+						type  = "ip"
+						value = "::1"
+					}
+					san {
+						type  = "uri"
+						value = "spiffe://example.com/service"
+					}
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateSANOrder("tls_self_signed_cert.test", "cert_pem", []string{
+						"ip:127.0.0.1",
+						"dns:example.com",
+						"email:admin@example.com",
+						"ip:::1",
+						"uri:spiffe://example.com/service",
+					}),
+					testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{"example.com"}),
+				),
+			},
+			{
+				// The configured order of the names of each type is preserved too
+				Config: config(`
+					dns_names    = ["www.example.com", "example.com"]
+					ip_addresses = ["::1", "127.0.0.1"]
+				`),
+				Check: testCheckPEMCertificateSANOrder("tls_self_signed_cert.test", "cert_pem", []string{
+					"dns:www.example.com",
+					"dns:example.com",
+					"ip:::1",
+					"ip:127.0.0.1",
+				}),
+			},
+			{
+				Config: config(`
+					dns_names = ["example.com"]
+					san {
+						type  = "dns"
+						value = "www.example.com"
+					}
+				`),
+				ExpectError: regexp.MustCompile(`"san": conflicts with dns_names`),
+			},
+			{
+				Config: config(`
+					san {
+						type  = "email"
+						value = "admin"
+					}
+				`),
+				ExpectError: regexp.MustCompile(`san.0: "admin" is not an email address of the form 'user@domain'`),
+			},
+			{
+				Config: config(`
+					san {
+						type  = "ip"
+						value = "127.0.0.1"
+					}
+					san {
+						type  = "ip"
+						value = "127.000.000.001"
+					}
+				`),
+				ExpectError: regexp.MustCompile(`san.1: "127.000.000.001" is not a valid IP address`),
+			},
+			{
+				Config: config(`
+					san {
+						type  = "dns"
+						value = "example.com"
+					}
+					san {
+						type  = "dns"
+						value = "example.com"
+					}
+				`),
+				ExpectError: regexp.MustCompile(`san.1: duplicate dns "example.com", already present at san.0`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_CAWithoutSubjectKeyID(t *testing.T) {
+	oidExtensionSubjectKeyID := asn1.ObjectIdentifier{2, 5, 29, 14}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: `
+					resource "tls_private_key" "ca" {
+						algorithm = "ED25519"
+					}
+					resource "tls_self_signed_cert" "ca" {
+						private_key_pem = tls_private_key.ca.private_key_pem
+						subject {
+							common_name = "Legacy Root CA"
+						}
+						is_ca_certificate     = true
+						set_subject_key_id    = false
+						validity_period_hours = 1
+						allowed_uses          = ["cert_signing"]
+					}
+					resource "tls_private_key" "leaf" {
+						algorithm = "ED25519"
+					}
+					resource "tls_cert_request" "leaf" {
+						private_key_pem = tls_private_key.leaf.private_key_pem
+						subject {
+							common_name = "example.com"
+						}
+					}
+					resource "tls_locally_signed_cert" "leaf" {
+						cert_request_pem      = tls_cert_request.leaf.cert_request_pem
+						ca_private_key_pem    = tls_private_key.ca.private_key_pem
+						ca_cert_pem           = tls_self_signed_cert.ca.cert_pem
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+					}
+				`,
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateWith("tls_self_signed_cert.ca", "cert_pem", func(cert *x509.Certificate) error {
+						if !cert.BasicConstraintsValid || !cert.IsCA {
+							return fmt.Errorf("certificate is not a CA")
+						}
+						for _, ext := range cert.Extensions {
+							if ext.Id.Equal(oidExtensionSubjectKeyID) {
+								return fmt.Errorf("unexpected subject key identifier extension: %x", ext.Value)
+							}
+						}
+						return nil
+					}),
+					testCheckPEMCertificateWith("tls_locally_signed_cert.leaf", "cert_pem", func(cert *x509.Certificate) error {
+						if len(cert.AuthorityKeyId) > 0 {
+							return fmt.Errorf("unexpected authority key identifier: %x", cert.AuthorityKeyId)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "ca" {
+						algorithm = "ED25519"
+					}
+					resource "tls_self_signed_cert" "ca" {
+						private_key_pem = tls_private_key.ca.private_key_pem
+						subject {
+							common_name = "Root CA"
+						}
+						is_ca_certificate     = true
+						validity_period_hours = 1
+						allowed_uses          = ["cert_signing"]
+					}
+				`,
+				Check: testCheckPEMCertificateWith("tls_self_signed_cert.ca", "cert_pem", func(cert *x509.Certificate) error {
+					if len(cert.SubjectKeyId) == 0 {
+						return fmt.Errorf("expected subject key identifier for CA certificate")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_BasicConstraintsCritical(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "example.com"
+			}
+			is_ca_certificate     = %t
+			validity_period_hours = 1
+			allowed_uses          = ["cert_signing", "server_auth"]
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, true, ""),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", true, true),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, true, "basic_constraints_critical = false"),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", true, false),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, false, ""),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", false, false),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, false, "basic_constraints_critical = true"),
+				Check:  testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", false, true),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_MaxPathLength(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "Example Root CA"
+			}
+			is_ca_certificate     = %t
+			validity_period_hours = 1
+			allowed_uses          = ["cert_signing"]
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, true, ""),
+				Check:  testCheckPEMCertificateMaxPathLength("tls_self_signed_cert.test", "cert_pem", -1),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, true, "max_path_length = 0"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "max_path_length", "0"),
+					testCheckPEMCertificateMaxPathLength("tls_self_signed_cert.test", "cert_pem", 0),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, true, "max_path_length = 2"),
+				Check:  testCheckPEMCertificateMaxPathLength("tls_self_signed_cert.test", "cert_pem", 2),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, false, "max_path_length = 0"),
+				ExpectError: regexp.MustCompile("max_path_length can only be set when is_ca_certificate is true"),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, true, "max_path_length = -1"),
+				ExpectError: regexp.MustCompile(`expected max_path_length to be at least \(0\), got -1`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_PrivateKeyPEMFile(t *testing.T) {
+	privateKeyFile := filepath.Join(t.TempDir(), "private_key.pem")
+	if err := os.WriteFile(privateKeyFile, []byte(testPrivateKeyPEM), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidPrivateKeyFile := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidPrivateKeyFile, []byte("not a private key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem_file = %q
+			subject {
+				common_name = "example.com"
+			}
+			validity_period_hours = 1
+			allowed_uses          = ["server_auth"]
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, privateKeyFile),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMFormat("tls_self_signed_cert.test", "cert_pem", PreambleCertificate),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "private_key_pem_file", privateKeyFile),
+					r.TestCheckNoResourceAttr("tls_self_signed_cert.test", "private_key_pem"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "RSA"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, filepath.Join(t.TempDir(), "missing.pem")),
+				ExpectError: regexp.MustCompile(`failed to read private_key_pem_file`),
+			},
+			{
+				Config:      fmt.Sprintf(config, invalidPrivateKeyFile),
+				ExpectError: regexp.MustCompile(`invalid private key in private_key_pem_file`),
+			},
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem      = <<EOT
+%s
+EOT
+						private_key_pem_file = %q
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+					}
+				`, testPrivateKeyPEM, privateKeyFile),
+				ExpectError: regexp.MustCompile(`only one of\s+` + "`" + `generate_key,private_key_pem,private_key_pem_file` + "`" + `\s+can\s+be\s+specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_ExtensionOrder(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "example.com"
+			}
+			dns_names             = ["example.com"]
+			is_ca_certificate     = true
+			validity_period_hours = 1
+			allowed_uses          = ["cert_signing", "server_auth"]
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, ""),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateExtensionOrder("tls_self_signed_cert.test", "cert_pem", []asn1.ObjectIdentifier{
+						certificateExtensions["key_usage"],
+						certificateExtensions["extended_key_usage"],
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, `extension_order = ["basic_constraints", "subject_alt_name", "subject_key_id"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateExtensionOrder("tls_self_signed_cert.test", "cert_pem", []asn1.ObjectIdentifier{
+						certificateExtensions["basic_constraints"],
+						certificateExtensions["subject_alt_name"],
+						certificateExtensions["subject_key_id"],
+						certificateExtensions["key_usage"],
+						certificateExtensions["extended_key_usage"],
+					}),
+					testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", true, true),
+					testCheckPEMCertificateDNSNames("tls_self_signed_cert.test", "cert_pem", []string{"example.com"}),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, `extension_order = ["unknown"]`),
+				ExpectError: regexp.MustCompile(`expected extension_order.0 to be one of`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_UniqueIDs(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "example.com"
+			}
+			is_ca_certificate     = true
+			validity_period_hours = 1
+			allowed_uses          = ["cert_signing"]
+			signature_algorithm   = %q
+			%s
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, "SHA256-RSA", ""),
+				Check:  testCheckPEMCertificateUniqueIDs("tls_self_signed_cert.test", "cert_pem", nil, nil),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, "SHA256-RSA", `
+					issuer_unique_id  = "0a1b2c"
+					subject_unique_id = "ff"
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateUniqueIDs("tls_self_signed_cert.test", "cert_pem", []byte{0x0a, 0x1b, 0x2c}, []byte{0xff}),
+					testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(crt *x509.Certificate) error {
+						return crt.CheckSignatureFrom(crt)
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, "SHA384-RSAPSS", `subject_unique_id = "0a1b2c3d"`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateUniqueIDs("tls_self_signed_cert.test", "cert_pem", nil, []byte{0x0a, 0x1b, 0x2c, 0x3d}),
+					testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(crt *x509.Certificate) error {
+						return crt.CheckSignatureFrom(crt)
+					}),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, "SHA256-RSA", `subject_unique_id = "xyz"`),
+				ExpectError: regexp.MustCompile(`invalid subject_unique_id: "xyz" is not a valid hexadecimal string`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_IPAddresses(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "example.com"
+			}
+			ip_addresses          = %s
+			validity_period_hours = 1
+			allowed_uses          = ["server_auth"]
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, `["127.0.0.1", "2001:db8::1"]`),
+				Check: testCheckPEMCertificateIPAddresses("tls_self_signed_cert.test", "cert_pem", []net.IP{
+					net.ParseIP("127.0.0.1"),
+					net.ParseIP("2001:db8::1"),
+				}),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, `["192.168.0.0/16"]`),
+				ExpectError: regexp.MustCompile(`expected ip_addresses to contain single IP addresses, got IP range\s+"192.168.0.0/16" in CIDR notation: IP ranges can only be used in\s+name_constraints`),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, `["2001:db8::/32"]`),
+				ExpectError: regexp.MustCompile(`got IP range\s+"2001:db8::/32"\s+in\s+CIDR\s+notation`),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, `["not-an-ip"]`),
+				ExpectError: regexp.MustCompile(`expected ip_addresses to contain a valid IP, got: not-an-ip`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_NotBefore(t *testing.T) {
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			private_key_pem = <<EOT
+%s
+EOT
+			subject {
+				common_name = "example.com"
+			}
+			not_before            = "2022-01-01T00:00:00Z"
+			validity_period_hours = 876000
+			allowed_uses          = ["server_auth"]
+			%s
+		}
+	`
+
+	var certPEM, serialNumber string
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, ""),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_start_time", "2022-01-01T00:00:00Z"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_end_time", "2121-12-08T00:00:00Z"),
+					r.TestMatchResourceAttr("tls_self_signed_cert.test", "issuance_duration_ms", regexp.MustCompile(`^\d+$`)),
+					testCheckAttrSaveValue("tls_self_signed_cert.test", "cert_pem", &certPEM),
+					testCheckAttrSaveValue("tls_self_signed_cert.test", "id", &serialNumber),
+				),
+			},
+			{
+				// The certificate is created again from identical inputs
+				Taint:  []string{"tls_self_signed_cert.test"},
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, ""),
+				Check:  testCheckAttrValueUnchanged("tls_self_signed_cert.test", "cert_pem", &certPEM),
+			},
+			{
+				Config: fmt.Sprintf(config, testPrivateKeyPEM, `dns_names = ["example.com"]`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckAttrValueChanged("tls_self_signed_cert.test", "cert_pem", &certPEM),
+					testCheckAttrValueChanged("tls_self_signed_cert.test", "id", &serialNumber),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, testPrivateKeyPEM, `signature_algorithm = "SHA256-RSAPSS"`),
+				ExpectError: regexp.MustCompile(`signature_algorithm SHA256-RSAPSS cannot be used with not_before`),
+			},
+			{
+				Config: `
+					resource "tls_private_key" "ecdsa" {
+						algorithm = "ECDSA"
+					}
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem = tls_private_key.ecdsa.private_key_pem
+						subject {
+							common_name = "example.com"
+						}
+						not_before            = "2022-01-01T00:00:00Z"
+						validity_period_hours = 876000
+						allowed_uses          = ["server_auth"]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`not_before cannot be used with ECDSA keys: ECDSA signatures are not\s+deterministic`),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_IsSelfSigned(t *testing.T) {
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem = <<EOT
+%s
+EOT
+						cert_request_pem = <<EOT
+%s
+EOT
+						validity_period_hours = 1
+						allowed_uses          = []
+					}
+				`, testPrivateKeyPEM, testCertRequest),
+				Check: r.TestCheckResourceAttr("tls_self_signed_cert.test", "is_self_signed", "true"),
+			},
+		},
+	})
+}
+
+func TestIsSelfSigned(t *testing.T) {
+	key, _, err := parsePrivateKeyPEM([]byte(testPrivateKeyPEM), false)
+	if err != nil {
+		t.Fatalf("error parsing private key: %s", err)
+	}
+	pubKey, err := privateKeyToPublicKey(key)
+	if err != nil {
+		t.Fatalf("error getting public key: %s", err)
+	}
+	caKey, _, err := parsePrivateKeyPEM([]byte(testCAPrivateKey), false)
+	if err != nil {
+		t.Fatalf("error parsing CA private key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		signer   interface{}
+		expected bool
+	}{
+		{"self-signed", key, true},
+		// Issuer and subject are identical, but the certificate is signed with a different key
+		{"self-issued", caKey, false},
+	} {
+		certBytes, err := x509.CreateCertificate(rand.Reader, template, template, pubKey, tc.signer)
+		if err != nil {
+			t.Fatalf("%s: error creating certificate: %s", tc.name, err)
+		}
+		cert, err := x509.ParseCertificate(certBytes)
+		if err != nil {
+			t.Fatalf("%s: error parsing certificate: %s", tc.name, err)
+		}
+
+		if actual := isSelfSigned(cert); actual != tc.expected {
+			t.Errorf("%s: expected isSelfSigned to be %t, got %t", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccResourceSelfSignedCert_WriteTo(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "tls_self_signed_cert" "test" {
+						private_key_pem = <<EOT
+%s
+EOT
+						subject {
+							common_name = "example.com"
+						}
+						validity_period_hours = 1
+						allowed_uses          = ["server_auth"]
+						write_to {
+							path = %q
+						}
+					}
+				`, testPrivateKeyPEM, certFile),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "write_to.0.permissions", "0644"),
+					testCheckAttrWrittenToFile("tls_self_signed_cert.test", "cert_pem", certFile, 0644),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_MinimalProfile(t *testing.T) {
+	config := func(extra string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				private_key_pem = <<EOT
+%s
+EOT
+				subject {
+					common_name = "example.com"
+				}
+				validity_period_hours = 1
+				minimal_profile       = true
+				%s
+			}
+		`, testPrivateKeyPEM, extra)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(`
+					allowed_uses = ["digital_signature", "server_auth"]
+					dns_names    = ["example.com"]
+				`),
+				Check: testCheckPEMCertificateExtensions("tls_self_signed_cert.test", "cert_pem", []string{
+					"key_usage",
+					"extended_key_usage",
+					"subject_alt_name",
+				}),
+			},
+			{
+				Config: config(`allowed_uses = ["digital_signature"]`),
+				Check:  testCheckPEMCertificateExtensions("tls_self_signed_cert.test", "cert_pem", []string{"key_usage"}),
+			},
+			{
+				Config: config(`
+					allowed_uses               = ["digital_signature"]
+					basic_constraints_critical = true
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateExtensions("tls_self_signed_cert.test", "cert_pem", []string{"key_usage", "basic_constraints"}),
+					testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", false, true),
+				),
+			},
+			{
+				Config: config(`
+					allowed_uses      = ["cert_signing"]
+					is_ca_certificate = true
+				`),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateExtensions("tls_self_signed_cert.test", "cert_pem", []string{"key_usage", "basic_constraints"}),
+					testCheckPEMCertificateBasicConstraints("tls_self_signed_cert.test", "cert_pem", true, true),
+				),
+			},
+			{
+				Config: config(`
+					allowed_uses       = ["cert_signing"]
+					is_ca_certificate  = true
+					set_subject_key_id = true
+				`),
+				Check: testCheckPEMCertificateExtensions("tls_self_signed_cert.test", "cert_pem", []string{
+					"key_usage",
+					"basic_constraints",
+					"subject_key_id",
+				}),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_RoundValidityToDay(t *testing.T) {
+	config := func(notBefore, validityPeriod string) string {
+		return fmt.Sprintf(`
+			resource "tls_self_signed_cert" "test" {
+				private_key_pem = <<EOT
+%s
+EOT
+				subject {
+					common_name = "example.com"
+				}
+				not_before            = %q
+				%s
+				round_validity_to_day = true
+				allowed_uses          = ["server_auth"]
+			}
+		`, testPrivateKeyPEM, notBefore, validityPeriod)
+	}
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config("2022-01-01T12:30:00Z", "validity_period_days = 10"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_start_time", "2022-01-01T12:30:00Z"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_end_time", "2022-01-10T23:59:59Z"),
+				),
+			},
+			{
+				// The end of the validity period is rounded in UTC, whatever the timezone of not_before
+				Config: config("2022-01-01T23:30:00-05:00", "validity_period_days = 10"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_start_time", "2022-01-01T23:30:00-05:00"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "validity_end_time", "2022-01-11T23:59:59Z"),
+				),
+			},
+			{
+				Config:      config("2022-01-01T12:30:00Z", "validity_period_hours = 6"),
+				ExpectError: regexp.MustCompile(`round_validity_to_day: the validity period doesn't include the end of any day in UTC`),
+			},
+		},
+	})
+}
+
+func TestRoundDownToEndOfDay(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %s", err)
+	}
+
+	for _, tc := range []struct {
+		input    time.Time
+		expected string
+	}{
+		{time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC), "2022-03-09T23:59:59Z"},
+		{time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC), "2022-03-09T23:59:59Z"},
+		{time.Date(2022, 3, 10, 23, 59, 59, 0, time.UTC), "2022-03-10T23:59:59Z"},
+		{time.Date(2022, 3, 10, 23, 59, 59, 500, time.UTC), "2022-03-10T23:59:59Z"},
+		// 2022-03-10T22:00:00Z
+		{time.Date(2022, 3, 10, 17, 0, 0, 0, newYork), "2022-03-09T23:59:59Z"},
+		// 2022-03-11T03:00:00Z
+		{time.Date(2022, 3, 10, 22, 0, 0, 0, newYork), "2022-03-10T23:59:59Z"},
+		// 2022-03-01T10:00:00Z: across the end of a month
+		{time.Date(2022, 3, 1, 15, 30, 0, 0, time.FixedZone("+05:30", 5*3600+1800)), "2022-02-28T23:59:59Z"},
+	} {
+		if actual := roundDownToEndOfDay(tc.input).Format(time.RFC3339); actual != tc.expected {
+			t.Errorf("roundDownToEndOfDay(%s): expected %s, got %s", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccResourceSelfSignedCert_PrivateKeyChange(t *testing.T) {
+	prvKey, _, err := parsePrivateKeyPEM([]byte(testPrivateKeyPEM), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvKeyPKCS8DER, err := x509.MarshalPKCS8PrivateKey(prvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvKeyPKCS8PEM := string(pem.EncodeToMemory(&pem.Block{Type: PreamblePrivateKeyPKCS8.String(), Bytes: prvKeyPKCS8DER}))
+
+	config := func(privateKeyPEM string) string {
+		return fmt.Sprintf(`
+			resource "tls_private_key" "ecdsa" {
+				algorithm   = "ECDSA"
+				ecdsa_curve = "P256"
+			}
+
+			resource "tls_self_signed_cert" "test" {
+				subject {
+					common_name = "example.com"
+				}
+				validity_period_hours = 1
+				allowed_uses          = ["server_auth"]
+				private_key_pem       = %s
+			}
+		`, privateKeyPEM)
+	}
+
+	var certPEM, spkiSHA256 string
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: config(fmt.Sprintf("<<EOT\n%s\nEOT", testPrivateKeyPEM)),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "RSA"),
+					r.TestMatchResourceAttr("tls_self_signed_cert.test", "public_key_spki_sha256", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					testCheckAttrSaveValue("tls_self_signed_cert.test", "cert_pem", &certPEM),
+					testCheckAttrSaveValue("tls_self_signed_cert.test", "public_key_spki_sha256", &spkiSHA256),
+				),
+			},
+			{
+				// The same key, in a different format, doesn't change the certificate
+				Config:             config(fmt.Sprintf("<<EOT\n%s\nEOT", prvKeyPKCS8PEM)),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				Config: config(fmt.Sprintf("<<EOT\n%s\nEOT", prvKeyPKCS8PEM)),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckAttrValueUnchanged("tls_self_signed_cert.test", "cert_pem", &certPEM),
+					testCheckAttrValueUnchanged("tls_self_signed_cert.test", "public_key_spki_sha256", &spkiSHA256),
+				),
+			},
+			{
+				// A different key creates a new certificate
+				Config: config("tls_private_key.ecdsa.private_key_pem"),
+				Check: r.ComposeAggregateTestCheckFunc(
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "key_algorithm", "ECDSA"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "ecdsa_curve", "P256"),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "rsa_bits", "0"),
+					testCheckAttrValueChanged("tls_self_signed_cert.test", "cert_pem", &certPEM),
+					testCheckAttrValueChanged("tls_self_signed_cert.test", "public_key_spki_sha256", &spkiSHA256),
+					testCheckPEMCertificateWith("tls_self_signed_cert.test", "cert_pem", func(cert *x509.Certificate) error {
+						if cert.PublicKeyAlgorithm != x509.ECDSA {
+							return fmt.Errorf("expected an ECDSA public key, got %s", cert.PublicKeyAlgorithm)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSelfSignedCert_SubjectSerialFromKey(t *testing.T) {
+	block, _ := pem.Decode([]byte(testPublicKeyPEM))
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := publicKeySPKISHA256(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+		resource "tls_self_signed_cert" "test" {
+			subject {
+				common_name = "device.example.com"
+				%s
+			}
+			subject_serial_from_key = true
+			validity_period_hours   = 1
+			allowed_uses            = ["client_auth"]
+			private_key_pem         = <<EOT
+%s
+EOT
+		}
+	`
+
+	r.UnitTest(t, r.TestCase{
+		ProviderFactories: testProviders,
+		Steps: []r.TestStep{
+			{
+				Config: fmt.Sprintf(config, "", testPrivateKeyPEM),
+				Check: r.ComposeAggregateTestCheckFunc(
+					testCheckPEMCertificateSubject("tls_self_signed_cert.test", "cert_pem", &pkix.Name{
+						CommonName:   "device.example.com",
+						SerialNumber: fingerprint,
+					}),
+					r.TestCheckResourceAttr("tls_self_signed_cert.test", "public_key_spki_sha256", fingerprint),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, `serial_number = "42"`, testPrivateKeyPEM),
+				ExpectError: regexp.MustCompile(`"subject_serial_from_key": conflicts with subject.0.serial_number`),
+			},
+		},
+	})
+}
@@ -1,23 +1,39 @@
 package provider
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"os"
 	"reflect"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	r "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+// testCheckPEMFormat verifies that the value of the given attribute is a PEM block of the expected type,
+// in the strict textual encoding of RFC 7468.
 func testCheckPEMFormat(name, key string, expected PEMPreamble) r.TestCheckFunc {
-	return r.TestMatchResourceAttr(name, key, regexp.MustCompile(fmt.Sprintf(`^-----BEGIN %[1]s-----\n(.|\s)+\n-----END %[1]s-----\n$`, expected)))
+	return r.ComposeAggregateTestCheckFunc(
+		r.TestMatchResourceAttr(name, key, regexp.MustCompile(fmt.Sprintf(`^-----BEGIN %[1]s-----\n(.|\s)+\n-----END %[1]s-----\n$`, expected))),
+		r.TestCheckResourceAttrWith(name, key, func(value string) error {
+			return checkStrictRFC7468([]byte(value))
+		}),
+	)
 }
 
 func testCheckPEMCertificateRequestWith(name, key string, f func(csr *x509.CertificateRequest) error) r.TestCheckFunc {
@@ -56,6 +72,105 @@ func testCheckPEMCertificateRequestURIs(name, key string, expected []*url.URL) r
 	})
 }
 
+// testCheckPEMCertificateRequestExtensionRequestSANs checks that the subject alternative names of the CSR
+// are in a Subject Alternative Name extension, in the PKCS #9 `extensionRequest` attribute.
+// The attributes are parsed from the raw CSR, independently of how x509.ParseCertificateRequest reads them.
+// The expected SANs are in the format of sanExtensionToStrings.
+func testCheckPEMCertificateRequestExtensionRequestSANs(name, key string, expected []string) r.TestCheckFunc {
+	return testCheckPEMCertificateRequestWith(name, key, func(csr *x509.CertificateRequest) error {
+		var tbs struct {
+			Version    int
+			Subject    asn1.RawValue
+			PublicKey  asn1.RawValue
+			Attributes []struct {
+				Type   asn1.ObjectIdentifier
+				Values []asn1.RawValue `asn1:"set"`
+			} `asn1:"tag:0"`
+		}
+		if _, err := asn1.Unmarshal(csr.RawTBSCertificateRequest, &tbs); err != nil {
+			return fmt.Errorf("failed to unmarshal certificate request info: %s", err)
+		}
+
+		var sanExtensions []pkix.Extension
+		for _, attr := range tbs.Attributes {
+			if !attr.Type.Equal(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}) {
+				continue
+			}
+			if len(attr.Values) != 1 {
+				return fmt.Errorf("expected exactly one value in the extensionRequest attribute, got %d", len(attr.Values))
+			}
+
+			var exts []pkix.Extension
+			if _, err := asn1.Unmarshal(attr.Values[0].FullBytes, &exts); err != nil {
+				return fmt.Errorf("failed to unmarshal extensionRequest attribute: %s", err)
+			}
+			for _, ext := range exts {
+				if ext.Id.Equal(oidExtensionSubjectAltName) {
+					sanExtensions = append(sanExtensions, ext)
+				}
+			}
+		}
+		if len(sanExtensions) != 1 {
+			return fmt.Errorf("expected exactly one subject alternative name extension in the extensionRequest attribute, got %d", len(sanExtensions))
+		}
+
+		actual, err := sanExtensionToStrings(sanExtensions[0])
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect subject alternative names in extensionRequest attribute: expected %v, got %v", expected, actual)
+		}
+		return nil
+	})
+}
+
+// sanExtensionToStrings returns the names in the given Subject Alternative Name extension, in the order they are encoded,
+// prefixed by their type, as in `dns:example.com`, `email:user@example.com`, `ip:127.0.0.1` and `uri:spiffe://example.com`.
+func sanExtensionToStrings(ext pkix.Extension) ([]string, error) {
+	var generalNames []asn1.RawValue
+	if _, err := asn1.Unmarshal(ext.Value, &generalNames); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subject alternative name extension: %s", err)
+	}
+	var names []string
+	for _, gn := range generalNames {
+		switch gn.Tag {
+		case 1:
+			names = append(names, "email:"+string(gn.Bytes))
+		case 2:
+			names = append(names, "dns:"+string(gn.Bytes))
+		case 6:
+			names = append(names, "uri:"+string(gn.Bytes))
+		case 7:
+			names = append(names, "ip:"+net.IP(gn.Bytes).String())
+		default:
+			return nil, fmt.Errorf("unexpected general name with tag %d", gn.Tag)
+		}
+	}
+	return names, nil
+}
+
+// testCheckPEMCertificateSANOrder checks that the Subject Alternative Name extension of the certificate
+// contains the expected names, in the expected order, in the format of sanExtensionToStrings.
+func testCheckPEMCertificateSANOrder(name, key string, expected []string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		for _, ext := range crt.Extensions {
+			if !ext.Id.Equal(oidExtensionSubjectAltName) {
+				continue
+			}
+			actual, err := sanExtensionToStrings(ext)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				return fmt.Errorf("incorrect order of subject alternative names: expected %v, got %v", expected, actual)
+			}
+			return nil
+		}
+		return fmt.Errorf("subject alternative name extension not found")
+	})
+}
+
 func testCheckPEMCertificateWith(name, key string, f func(csr *x509.Certificate) error) r.TestCheckFunc {
 	return r.TestCheckResourceAttrWith(name, key, func(value string) error {
 		block, _ := pem.Decode([]byte(value))
@@ -82,6 +197,13 @@ func testCheckPEMCertificateDNSNames(name, key string, expected []string) r.Test
 	})
 }
 
+//nolint:unparam // `key` parameter always receives `cert_pem` because generated PEMs attributes are called that way.
+func testCheckPEMCertificateEmailAddresses(name, key string, expected []string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		return compareCertEmailAddresses(expected, crt.EmailAddresses)
+	})
+}
+
 //nolint:unparam // `key` parameter always receives `cert_pem` because generated PEMs attributes are called that way.
 func testCheckPEMCertificateIPAddresses(name, key string, expected []net.IP) r.TestCheckFunc {
 	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
@@ -136,6 +258,46 @@ func testCheckPEMCertificateDuration(name, key string, expected time.Duration) r
 	})
 }
 
+// testCheckPEMCertificateRenewalOf verifies that the certificate is a renewal of the saved one:
+// only the serial number and the validity period (and so the signature) are expected to differ.
+func testCheckPEMCertificateRenewalOf(name, key string, saved *string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(cert *x509.Certificate) error {
+		block, _ := pem.Decode([]byte(*saved))
+		previous, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("error parsing saved Certificate: %s", err)
+		}
+
+		if cert.SerialNumber.Cmp(previous.SerialNumber) == 0 {
+			return fmt.Errorf("expected a new serial number, but it is still %s", cert.SerialNumber)
+		}
+		if cert.NotBefore.Equal(previous.NotBefore) && cert.NotAfter.Equal(previous.NotAfter) {
+			return fmt.Errorf("expected a new validity period, but it is still %s - %s", cert.NotBefore, cert.NotAfter)
+		}
+
+		if cert.Version != previous.Version {
+			return fmt.Errorf("incorrect version: expected %d, got %d", previous.Version, cert.Version)
+		}
+		if cert.SignatureAlgorithm != previous.SignatureAlgorithm {
+			return fmt.Errorf("incorrect signature algorithm: expected %s, got %s", previous.SignatureAlgorithm, cert.SignatureAlgorithm)
+		}
+		if !bytes.Equal(cert.RawIssuer, previous.RawIssuer) {
+			return fmt.Errorf("incorrect issuer: expected %s, got %s", previous.Issuer, cert.Issuer)
+		}
+		if !bytes.Equal(cert.RawSubject, previous.RawSubject) {
+			return fmt.Errorf("incorrect subject: expected %s, got %s", previous.Subject, cert.Subject)
+		}
+		if !bytes.Equal(cert.RawSubjectPublicKeyInfo, previous.RawSubjectPublicKeyInfo) {
+			return fmt.Errorf("incorrect subject public key: expected the one of the renewed certificate")
+		}
+		if !reflect.DeepEqual(cert.Extensions, previous.Extensions) {
+			return fmt.Errorf("incorrect extensions: expected %v, got %v", previous.Extensions, cert.Extensions)
+		}
+
+		return nil
+	})
+}
+
 func testCheckPEMCertificateAgainstPEMRootCA(name, key string, rootCA []byte) r.TestCheckFunc {
 	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
 		// Certificate verification must fail if no CA Cert Pool is provided
@@ -197,6 +359,25 @@ func compareCertSubjects(expected, actualSubject *pkix.Name) error {
 	return nil
 }
 
+// compareSubjectRDNOrder verifies that the relative distinguished names of the given raw subject
+// have the expected attribute types, in the expected order.
+func compareSubjectRDNOrder(expected []asn1.ObjectIdentifier, rawSubject []byte) error {
+	var rdns pkix.RDNSequence
+	if _, err := asn1.Unmarshal(rawSubject, &rdns); err != nil {
+		return fmt.Errorf("failed to unmarshal subject: %s", err)
+	}
+
+	actual := make([]asn1.ObjectIdentifier, len(rdns))
+	for i, rdn := range rdns {
+		actual[i] = rdn[0].Type
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("incorrect subject RDN order: expected %v, got %v", expected, actual)
+	}
+	return nil
+}
+
 func compareCertDNSNames(expected, actual []string) error {
 	if len(expected) != len(actual) {
 		return fmt.Errorf("incorrect DNS names: expected %v, got %v", expected, actual)
@@ -211,6 +392,20 @@ func compareCertDNSNames(expected, actual []string) error {
 	return nil
 }
 
+func compareCertEmailAddresses(expected, actual []string) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("incorrect email addresses: expected %v, got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return fmt.Errorf("incorrect email addresses: expected %v, got %v", expected, actual)
+		}
+	}
+
+	return nil
+}
+
 func compareCertIPAddresses(expected, actual []net.IP) error {
 	if len(expected) != len(actual) {
 		return fmt.Errorf("incorrect IP addresses: expected %v, got %v", expected, actual)
@@ -252,3 +447,465 @@ func compareExtKeyUsages(expected, actual []x509.ExtKeyUsage) error {
 
 	return nil
 }
+
+// testCheckAttrSaveValue stores the value of the given attribute into the given string,
+// so that it can be compared with the value in a later test step.
+func testCheckAttrSaveValue(name, key string, saved *string) r.TestCheckFunc {
+	return r.TestCheckResourceAttrWith(name, key, func(value string) error {
+		*saved = value
+		return nil
+	})
+}
+
+// testCheckAttrValueChanged verifies that the value of the given attribute differs from the saved one,
+// and then saves the new value.
+func testCheckAttrValueChanged(name, key string, saved *string) r.TestCheckFunc {
+	return r.TestCheckResourceAttrWith(name, key, func(value string) error {
+		if value == *saved {
+			return fmt.Errorf("expected value of %s.%s to change, but it did not", name, key)
+		}
+		*saved = value
+		return nil
+	})
+}
+
+// testCheckAttrValueUnchanged verifies that the value of the given attribute matches the saved one.
+func testCheckAttrValueUnchanged(name, key string, saved *string) r.TestCheckFunc {
+	return r.TestCheckResourceAttrWith(name, key, func(value string) error {
+		if value != *saved {
+			return fmt.Errorf("expected value of %s.%s to stay the same, but it changed", name, key)
+		}
+		return nil
+	})
+}
+
+// testCheckAttrBase64Of verifies that the value of the given attribute is the base64 encoding
+// of the value of another attribute of the same resource.
+func testCheckAttrBase64Of(name, key, sourceKey string) r.TestCheckFunc {
+	var source string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, sourceKey, &source),
+		r.TestCheckResourceAttrWith(name, key, func(value string) error {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("error decoding base64 value of %s.%s: %s", name, key, err)
+			}
+			if string(decoded) != source {
+				return fmt.Errorf("expected %s.%s to be the base64 encoding of %s.%s", name, key, name, sourceKey)
+			}
+			return nil
+		}),
+	)
+}
+
+// testCheckAttrBase64URLOf verifies that the value of the given attribute is the unpadded base64url encoding
+// of the same data as the base64 encoded value of another attribute of the same resource.
+func testCheckAttrBase64URLOf(name, key, base64Key string) r.TestCheckFunc {
+	var source string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, base64Key, &source),
+		r.TestCheckResourceAttrWith(name, key, func(value string) error {
+			decoded, err := base64.RawURLEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("error decoding base64url value of %s.%s: %s", name, key, err)
+			}
+			expected, err := base64.StdEncoding.DecodeString(source)
+			if err != nil {
+				return fmt.Errorf("error decoding base64 value of %s.%s: %s", name, base64Key, err)
+			}
+			if !bytes.Equal(decoded, expected) {
+				return fmt.Errorf("expected %s.%s to be the base64url encoding of the same data as %s.%s", name, key, name, base64Key)
+			}
+			return nil
+		}),
+	)
+}
+
+// testCheckAttrDERBase64Of verifies that the value of the given attribute is the base64 encoding
+// of the DER data in the PEM of another attribute of the same resource.
+func testCheckAttrDERBase64Of(name, key, pemKey string) r.TestCheckFunc {
+	var source string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, pemKey, &source),
+		r.TestCheckResourceAttrWith(name, key, func(value string) error {
+			block, _ := pem.Decode([]byte(source))
+			if block == nil {
+				return fmt.Errorf("no PEM block found in %s.%s", name, pemKey)
+			}
+			if expected := base64.StdEncoding.EncodeToString(block.Bytes); value != expected {
+				return fmt.Errorf("incorrect %s.%s: expected %s, got %s", name, key, expected, value)
+			}
+			return nil
+		}),
+	)
+}
+
+// testCheckAttrWrittenToFile verifies that the value of the given attribute has been written
+// to the file at the given path, and that the file has the expected permissions.
+func testCheckAttrWrittenToFile(name, key, path string, expectedPerm os.FileMode) r.TestCheckFunc {
+	return r.TestCheckResourceAttrWith(name, key, func(value string) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(content) != value {
+			return fmt.Errorf("expected %s to contain the value of %s.%s", path, name, key)
+		}
+
+		// NOTE: on Windows, file permissions are limited to the read-only attribute
+		if runtime.GOOS == "windows" {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if actual := info.Mode().Perm(); actual != expectedPerm {
+			return fmt.Errorf("incorrect permissions of %s: expected %04o, got %04o", path, expectedPerm, actual)
+		}
+		return nil
+	})
+}
+
+// testCheckAttrContentHashOf verifies that the value of the given attribute is the SHA256 checksum
+// of the DER data in the PEM of another attribute of the same resource.
+func testCheckAttrContentHashOf(name, key, pemKey string) r.TestCheckFunc {
+	var source string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, pemKey, &source),
+		r.TestCheckResourceAttrWith(name, key, func(value string) error {
+			block, _ := pem.Decode([]byte(source))
+			if block == nil {
+				return fmt.Errorf("no PEM block found in %s.%s", name, pemKey)
+			}
+			hash := sha256.Sum256(block.Bytes)
+			if expected := hex.EncodeToString(hash[:]); value != expected {
+				return fmt.Errorf("incorrect %s.%s: expected %s, got %s", name, key, expected, value)
+			}
+			return nil
+		}),
+	)
+}
+
+// testCheckPEMCertificateThumbprints verifies that `cert_thumbprint_sha1` and `cert_thumbprint_sha256`
+// are the SHA1 and SHA256 checksums of the certificate in DER format, in uppercase hexadecimal.
+func testCheckPEMCertificateThumbprints(name, key string) r.TestCheckFunc {
+	var sha1Thumbprint, sha256Thumbprint string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, "cert_thumbprint_sha1", &sha1Thumbprint),
+		testCheckAttrSaveValue(name, "cert_thumbprint_sha256", &sha256Thumbprint),
+		testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+			if expected := fmt.Sprintf("%X", sha1.Sum(crt.Raw)); sha1Thumbprint != expected {
+				return fmt.Errorf("incorrect cert_thumbprint_sha1: expected %s, got %s", expected, sha1Thumbprint)
+			}
+			if expected := fmt.Sprintf("%X", sha256.Sum256(crt.Raw)); sha256Thumbprint != expected {
+				return fmt.Errorf("incorrect cert_thumbprint_sha256: expected %s, got %s", expected, sha256Thumbprint)
+			}
+			return nil
+		}),
+	)
+}
+
+func testCheckPEMCertificateSubjectInfoAccess(name, key string, expected map[string]string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		actual := map[string]string{}
+		for _, ext := range crt.Extensions {
+			if !ext.Id.Equal(oidExtensionSubjectInfoAccess) {
+				continue
+			}
+
+			var descriptions []accessDescription
+			if _, err := asn1.Unmarshal(ext.Value, &descriptions); err != nil {
+				return fmt.Errorf("failed to unmarshal subject information access: %s", err)
+			}
+			for _, ad := range descriptions {
+				if ad.Location.Class != asn1.ClassContextSpecific || ad.Location.Tag != 6 {
+					return fmt.Errorf("unexpected access location: %v", ad.Location)
+				}
+				actual[ad.Method.String()] = string(ad.Location.Bytes)
+			}
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect subject information access: expected %v, got %v", expected, actual)
+		}
+		return nil
+	})
+}
+
+func testCheckPEMCertificateFreshestCRL(name, key string, expected []string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		var actual []string
+		for _, ext := range crt.Extensions {
+			if !ext.Id.Equal(oidExtensionFreshestCRL) {
+				continue
+			}
+			if ext.Critical {
+				return fmt.Errorf("freshest CRL extension must not be critical")
+			}
+
+			var points []distributionPoint
+			if _, err := asn1.Unmarshal(ext.Value, &points); err != nil {
+				return fmt.Errorf("failed to unmarshal freshest CRL: %s", err)
+			}
+			for _, dp := range points {
+				for _, fullName := range dp.DistributionPoint.FullName {
+					if fullName.Class != asn1.ClassContextSpecific || fullName.Tag != 6 {
+						return fmt.Errorf("unexpected distribution point name: %v", fullName)
+					}
+					actual = append(actual, string(fullName.Bytes))
+				}
+			}
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect freshest CRL distribution points: expected %v, got %v", expected, actual)
+		}
+		return nil
+	})
+}
+
+func testCheckPEMCertificateAIAAndCRLDistributionPoints(name, key string, expectedOCSPServers, expectedIssuingCertificateURLs, expectedCRLDistributionPoints []string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		if !reflect.DeepEqual(expectedOCSPServers, crt.OCSPServer) {
+			return fmt.Errorf("incorrect OCSP servers: expected %v, got %v", expectedOCSPServers, crt.OCSPServer)
+		}
+		if !reflect.DeepEqual(expectedIssuingCertificateURLs, crt.IssuingCertificateURL) {
+			return fmt.Errorf("incorrect issuing certificate URLs: expected %v, got %v", expectedIssuingCertificateURLs, crt.IssuingCertificateURL)
+		}
+		if !reflect.DeepEqual(expectedCRLDistributionPoints, crt.CRLDistributionPoints) {
+			return fmt.Errorf("incorrect CRL distribution points: expected %v, got %v", expectedCRLDistributionPoints, crt.CRLDistributionPoints)
+		}
+		return nil
+	})
+}
+
+// testCheckPEMCertificatePolicies decodes the Certificate Policies extension of the certificate,
+// and checks its policies and their qualifiers against the expected ones.
+func testCheckPEMCertificatePolicies(name, key string, expected []certificatePolicy) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		var infos []policyInformation
+		for _, ext := range crt.Extensions {
+			if !ext.Id.Equal(oidExtensionCertificatePolicies) {
+				continue
+			}
+			if ext.Critical {
+				return fmt.Errorf("certificate policies extension should not be critical")
+			}
+			if rest, err := asn1.Unmarshal(ext.Value, &infos); err != nil || len(rest) > 0 {
+				return fmt.Errorf("error parsing certificate policies extension: %v", err)
+			}
+		}
+
+		actual := make([]certificatePolicy, len(infos))
+		for i, info := range infos {
+			actual[i].identifier = info.PolicyIdentifier
+			for _, qualifier := range info.PolicyQualifiers {
+				switch {
+				case qualifier.PolicyQualifierID.Equal(oidPolicyQualifierCPS):
+					if qualifier.Qualifier.Tag != asn1.TagIA5String {
+						return fmt.Errorf("CPS URI should be an IA5String, got tag %d", qualifier.Qualifier.Tag)
+					}
+					actual[i].cpsURIs = append(actual[i].cpsURIs, string(qualifier.Qualifier.Bytes))
+				case qualifier.PolicyQualifierID.Equal(oidPolicyQualifierUserNotice):
+					var notice struct {
+						ExplicitText asn1.RawValue
+					}
+					if _, err := asn1.Unmarshal(qualifier.Qualifier.FullBytes, &notice); err != nil {
+						return fmt.Errorf("error parsing user notice: %v", err)
+					}
+					if notice.ExplicitText.Tag != asn1.TagUTF8String {
+						return fmt.Errorf("user notice text should be a UTF8String, got tag %d", notice.ExplicitText.Tag)
+					}
+					actual[i].userNoticeText = string(notice.ExplicitText.Bytes)
+				default:
+					return fmt.Errorf("unexpected policy qualifier: %s", qualifier.PolicyQualifierID)
+				}
+			}
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect certificate policies: expected %+v, got %+v", expected, actual)
+		}
+		return nil
+	})
+}
+
+func testCheckPEMCertificateBasicConstraints(name, key string, expectedIsCA, expectedCritical bool) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		if !crt.BasicConstraintsValid || crt.IsCA != expectedIsCA {
+			return fmt.Errorf("incorrect basic constraints: expected CA %t, got %t (valid: %t)", expectedIsCA, crt.IsCA, crt.BasicConstraintsValid)
+		}
+
+		for _, ext := range crt.Extensions {
+			if ext.Id.Equal(oidExtensionBasicConstraints) {
+				if ext.Critical != expectedCritical {
+					return fmt.Errorf("incorrect basic constraints criticality: expected %t, got %t", expectedCritical, ext.Critical)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("basic constraints extension not found")
+	})
+}
+
+// testCheckPEMCertificateMaxPathLength checks the path length constraint of the certificate:
+// an expected value of -1 means that the path length is unlimited.
+func testCheckPEMCertificateMaxPathLength(name, key string, expected int) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		if !crt.BasicConstraintsValid {
+			return fmt.Errorf("basic constraints extension not found")
+		}
+
+		// NOTE: x509.ParseCertificate sets MaxPathLen to -1 when the path length constraint is missing,
+		// and MaxPathLenZero when it's present and set to 0
+		if crt.MaxPathLen != expected || crt.MaxPathLenZero != (expected == 0) {
+			return fmt.Errorf("incorrect max path length: expected %d, got %d (zero: %t)", expected, crt.MaxPathLen, crt.MaxPathLenZero)
+		}
+		return nil
+	})
+}
+
+// testCheckPEMCertificatePrivateKey checks that the public key of the certificate
+// matches the private key in PEM format found at the given prvKeyKey.
+func testCheckPEMCertificatePrivateKey(name, key, prvKeyKey string) r.TestCheckFunc {
+	var prvKeyPEM string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, prvKeyKey, &prvKeyPEM),
+		testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+			prvKey, _, err := parsePrivateKeyPEM([]byte(prvKeyPEM), false)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %s", prvKeyKey, err)
+			}
+			pubKey, err := privateKeyToPublicKey(prvKey)
+			if err != nil {
+				return err
+			}
+
+			if !publicKeysEqual(pubKey, crt.PublicKey) {
+				return fmt.Errorf("public key of the certificate does not match %s", prvKeyKey)
+			}
+			return nil
+		}),
+	)
+}
+
+func testCheckRawKeyAttributes(name string, expectedPrvKeyLen, expectedPubKeyLen int) r.TestCheckFunc {
+	var prvKeyPEM string
+	return r.ComposeTestCheckFunc(
+		testCheckAttrSaveValue(name, "private_key_pem", &prvKeyPEM),
+		r.TestCheckResourceAttrWith(name, "private_key_raw_base64", func(value string) error {
+			prvKey, _, err := parsePrivateKeyPEM([]byte(prvKeyPEM), false)
+			if err != nil {
+				return err
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("error decoding base64 value of %s.private_key_raw_base64: %s", name, err)
+			}
+			if len(raw) != expectedPrvKeyLen {
+				return fmt.Errorf("incorrect raw private key length: expected %d, got %d", expectedPrvKeyLen, len(raw))
+			}
+			if !bytes.Equal(raw, privateKeyToRaw(prvKey)) {
+				return fmt.Errorf("raw private key does not match private_key_pem")
+			}
+			return nil
+		}),
+		r.TestCheckResourceAttrWith(name, "public_key_raw_base64", func(value string) error {
+			prvKey, _, err := parsePrivateKeyPEM([]byte(prvKeyPEM), false)
+			if err != nil {
+				return err
+			}
+			pubKey, err := privateKeyToPublicKey(prvKey)
+			if err != nil {
+				return err
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("error decoding base64 value of %s.public_key_raw_base64: %s", name, err)
+			}
+			if len(raw) != expectedPubKeyLen {
+				return fmt.Errorf("incorrect raw public key length: expected %d, got %d", expectedPubKeyLen, len(raw))
+			}
+			if !bytes.Equal(raw, publicKeyToRaw(pubKey)) {
+				return fmt.Errorf("raw public key does not match private_key_pem")
+			}
+			return nil
+		}),
+		testCheckAttrBase64URLOf(name, "private_key_raw_base64url", "private_key_raw_base64"),
+		testCheckAttrBase64URLOf(name, "public_key_raw_base64url", "public_key_raw_base64"),
+	)
+}
+
+func testCheckPEMCertificateExtensionOrder(name, key string, expected []asn1.ObjectIdentifier) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		if len(crt.Extensions) < len(expected) {
+			return fmt.Errorf("incorrect number of extensions: expected at least %d, got %d", len(expected), len(crt.Extensions))
+		}
+
+		for i, oid := range expected {
+			if !crt.Extensions[i].Id.Equal(oid) {
+				return fmt.Errorf("incorrect extension at position %d: expected %s, got %s", i, oid, crt.Extensions[i].Id)
+			}
+		}
+		return nil
+	})
+}
+
+// testCheckPEMCertificateExtensions checks that the certificate has exactly the given extensions,
+// named as in `extension_order`, regardless of their order.
+func testCheckPEMCertificateExtensions(name, key string, expected []string) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		actual := make([]string, len(crt.Extensions))
+		for i, ext := range crt.Extensions {
+			actual[i] = ext.Id.String()
+			for extName, oid := range certificateExtensions {
+				if ext.Id.Equal(oid) {
+					actual[i] = extName
+				}
+			}
+		}
+		sort.Strings(actual)
+
+		expected = append([]string{}, expected...)
+		sort.Strings(expected)
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("incorrect extensions: expected %v, got %v", expected, actual)
+		}
+		return nil
+	})
+}
+
+func testCheckPEMCertificateUniqueIDs(name, key string, expectedIssuerUniqueID, expectedSubjectUniqueID []byte) r.TestCheckFunc {
+	return testCheckPEMCertificateWith(name, key, func(crt *x509.Certificate) error {
+		var tbs asn1.RawValue
+		if _, err := asn1.Unmarshal(crt.RawTBSCertificate, &tbs); err != nil {
+			return fmt.Errorf("failed to unmarshal TBSCertificate: %s", err)
+		}
+
+		var actualIssuerUniqueID, actualSubjectUniqueID []byte
+		for rest := tbs.Bytes; len(rest) > 0; {
+			var field asn1.RawValue
+			var err error
+			if rest, err = asn1.Unmarshal(rest, &field); err != nil {
+				return fmt.Errorf("failed to unmarshal TBSCertificate field: %s", err)
+			}
+			if field.Class == asn1.ClassContextSpecific && field.Tag == 1 {
+				actualIssuerUniqueID = field.Bytes[1:]
+			}
+			if field.Class == asn1.ClassContextSpecific && field.Tag == 2 {
+				actualSubjectUniqueID = field.Bytes[1:]
+			}
+		}
+
+		if !bytes.Equal(expectedIssuerUniqueID, actualIssuerUniqueID) {
+			return fmt.Errorf("incorrect issuer unique identifier: expected %x, got %x", expectedIssuerUniqueID, actualIssuerUniqueID)
+		}
+		if !bytes.Equal(expectedSubjectUniqueID, actualSubjectUniqueID) {
+			return fmt.Errorf("incorrect subject unique identifier: expected %x, got %x", expectedSubjectUniqueID, actualSubjectUniqueID)
+		}
+		return nil
+	})
+}
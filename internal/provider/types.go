@@ -37,6 +37,72 @@ func SupportedAlgorithmsStr() []string {
 	return supportedStr
 }
 
+// KeyProfile represents a target use of a private key, that selects its algorithm and parameters.
+type KeyProfile string
+
+const (
+	KeyProfileModern       KeyProfile = "modern"
+	KeyProfileFIPS         KeyProfile = "fips"
+	KeyProfileLegacyCompat KeyProfile = "legacy-compat"
+)
+
+func (p KeyProfile) String() string {
+	return string(p)
+}
+
+// SupportedKeyProfiles returns a slice of KeyProfile currently supported by this provider.
+func SupportedKeyProfiles() []KeyProfile {
+	return []KeyProfile{
+		KeyProfileModern,
+		KeyProfileFIPS,
+		KeyProfileLegacyCompat,
+	}
+}
+
+// SupportedKeyProfilesStr returns the same content of SupportedKeyProfiles but as a slice of string.
+func SupportedKeyProfilesStr() []string {
+	supported := SupportedKeyProfiles()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
+// SANType represents a type of Subject Alternative Name.
+type SANType string
+
+const (
+	SANTypeDNS   SANType = "dns"
+	SANTypeIP    SANType = "ip"
+	SANTypeURI   SANType = "uri"
+	SANTypeEmail SANType = "email"
+)
+
+func (t SANType) String() string {
+	return string(t)
+}
+
+// SupportedSANTypes returns a slice of SANType currently supported by this provider.
+func SupportedSANTypes() []SANType {
+	return []SANType{
+		SANTypeDNS,
+		SANTypeIP,
+		SANTypeURI,
+		SANTypeEmail,
+	}
+}
+
+// SupportedSANTypesStr returns the same content of SupportedSANTypes but as a slice of string.
+func SupportedSANTypesStr() []string {
+	supported := SupportedSANTypes()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
 // ECDSACurve represents a type of ECDSA elliptic curve.
 type ECDSACurve string
 
@@ -71,6 +137,38 @@ func SupportedECDSACurvesStr() []string {
 	return supportedStr
 }
 
+// ecdsaCurveAliases maps the key size in bits of each ECDSACurve, accepted as an alias, to the ECDSACurve itself.
+var ecdsaCurveAliases = map[string]ECDSACurve{
+	"224": P224,
+	"256": P256,
+	"384": P384,
+	"521": P521,
+}
+
+// SupportedECDSACurvesAndAliasesStr returns the same content of SupportedECDSACurvesStr,
+// followed by the key size aliases of each ECDSACurve.
+func SupportedECDSACurvesAndAliasesStr() []string {
+	supported := SupportedECDSACurves()
+	supportedStr := SupportedECDSACurvesStr()
+	for _, curve := range supported {
+		for alias, aliased := range ecdsaCurveAliases {
+			if aliased == curve {
+				supportedStr = append(supportedStr, alias)
+			}
+		}
+	}
+	return supportedStr
+}
+
+// NormalizeECDSACurve returns the ECDSACurve for the given name, resolving key size aliases
+// (e.g. `256` becomes `P256`). Names that are not aliases are returned unchanged.
+func NormalizeECDSACurve(name string) ECDSACurve {
+	if curve, ok := ecdsaCurveAliases[name]; ok {
+		return curve
+	}
+	return ECDSACurve(name)
+}
+
 // PEMPreamble represents the heading used in a PEM-formatted for the "encapsulation boundaries",
 // that is used to delimit the "encapsulated text portion" of cryptographic documents.
 //
@@ -80,13 +178,16 @@ type PEMPreamble string
 const (
 	PreamblePublicKey PEMPreamble = "PUBLIC KEY"
 
-	PreamblePrivateKeyPKCS8   PEMPreamble = "PRIVATE KEY"
-	PreamblePrivateKeyRSA     PEMPreamble = "RSA PRIVATE KEY"
-	PreamblePrivateKeyEC      PEMPreamble = "EC PRIVATE KEY"
-	PreamblePrivateKeyOpenSSH PEMPreamble = "OPENSSH PRIVATE KEY"
+	PreamblePrivateKeyPKCS8          PEMPreamble = "PRIVATE KEY"
+	PreamblePrivateKeyPKCS8Encrypted PEMPreamble = "ENCRYPTED PRIVATE KEY"
+	PreamblePrivateKeyRSA            PEMPreamble = "RSA PRIVATE KEY"
+	PreamblePrivateKeyEC             PEMPreamble = "EC PRIVATE KEY"
+	PreamblePrivateKeyOpenSSH        PEMPreamble = "OPENSSH PRIVATE KEY"
 
 	PreambleCertificate        PEMPreamble = "CERTIFICATE"
 	PreambleCertificateRequest PEMPreamble = "CERTIFICATE REQUEST"
+
+	PreambleCRL PEMPreamble = "X509 CRL"
 )
 
 func (p PEMPreamble) String() string {
@@ -108,11 +209,120 @@ func PEMBlockToPEMPreamble(block *pem.Block) (PEMPreamble, error) {
 		return PreambleCertificate, nil
 	case PreambleCertificateRequest.String():
 		return PreambleCertificateRequest, nil
+	case PreambleCRL.String():
+		return PreambleCRL, nil
 	default:
 		return "", fmt.Errorf("unsupported PEM preamble/type: %s", block.Type)
 	}
 }
 
+// KeyFormat represents a format in which keys can be encoded.
+type KeyFormat string
+
+const (
+	KeyFormatPEM     KeyFormat = "PEM"
+	KeyFormatOpenSSH KeyFormat = "OpenSSH"
+)
+
+func (f KeyFormat) String() string {
+	return string(f)
+}
+
+// SupportedKeyFormats returns an array of KeyFormat currently supported by this provider.
+func SupportedKeyFormats() []KeyFormat {
+	return []KeyFormat{
+		KeyFormatPEM,
+		KeyFormatOpenSSH,
+	}
+}
+
+// SupportedKeyFormatsStr returns the same content of SupportedKeyFormats but as a slice of string.
+func SupportedKeyFormatsStr() []string {
+	supported := SupportedKeyFormats()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
+// PrivateKeyFormat represents a structure in which private keys can be encoded in PEM format.
+type PrivateKeyFormat string
+
+const (
+	PrivateKeyFormatPKCS1 PrivateKeyFormat = "pkcs1"
+	PrivateKeyFormatPKCS8 PrivateKeyFormat = "pkcs8"
+	PrivateKeyFormatSEC1  PrivateKeyFormat = "sec1"
+)
+
+func (f PrivateKeyFormat) String() string {
+	return string(f)
+}
+
+// SupportedPrivateKeyFormats returns an array of PrivateKeyFormat currently supported by this provider.
+func SupportedPrivateKeyFormats() []PrivateKeyFormat {
+	return []PrivateKeyFormat{
+		PrivateKeyFormatPKCS1,
+		PrivateKeyFormatPKCS8,
+		PrivateKeyFormatSEC1,
+	}
+}
+
+// SupportedPrivateKeyFormatsStr returns the same content of SupportedPrivateKeyFormats but as a slice of string.
+func SupportedPrivateKeyFormatsStr() []string {
+	supported := SupportedPrivateKeyFormats()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
+// algorithmPrivateKeyFormats maps each Algorithm to the PrivateKeyFormat its keys can be encoded with:
+// the first is the default.
+var algorithmPrivateKeyFormats = map[Algorithm][]PrivateKeyFormat{
+	RSA:     {PrivateKeyFormatPKCS1, PrivateKeyFormatPKCS8},
+	ECDSA:   {PrivateKeyFormatSEC1, PrivateKeyFormatPKCS8},
+	ED25519: {PrivateKeyFormatPKCS8},
+}
+
+// privateKeyFormatPreambles maps each PrivateKeyFormat to the PEMPreamble that labels it in PEM format.
+var privateKeyFormatPreambles = map[PrivateKeyFormat]PEMPreamble{
+	PrivateKeyFormatPKCS1: PreamblePrivateKeyRSA,
+	PrivateKeyFormatPKCS8: PreamblePrivateKeyPKCS8,
+	PrivateKeyFormatSEC1:  PreamblePrivateKeyEC,
+}
+
+// PEMBundleOrder represents the order in which the components of a PEM bundle are concatenated.
+type PEMBundleOrder string
+
+const (
+	PEMBundleOrderCertFirst PEMBundleOrder = "cert_first"
+	PEMBundleOrderKeyFirst  PEMBundleOrder = "key_first"
+)
+
+func (o PEMBundleOrder) String() string {
+	return string(o)
+}
+
+// SupportedPEMBundleOrders returns an array of PEMBundleOrder currently supported by this provider.
+func SupportedPEMBundleOrders() []PEMBundleOrder {
+	return []PEMBundleOrder{
+		PEMBundleOrderCertFirst,
+		PEMBundleOrderKeyFirst,
+	}
+}
+
+// SupportedPEMBundleOrdersStr returns the same content of SupportedPEMBundleOrders but as a slice of string.
+func SupportedPEMBundleOrdersStr() []string {
+	supported := SupportedPEMBundleOrders()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
 // ProxyScheme represents url schemes supported when providing proxy configuration to this provider.
 type ProxyScheme string
 
@@ -149,6 +359,7 @@ func SupportedProxySchemesStr() []string {
 type URLScheme string
 
 const (
+	HTTPScheme  URLScheme = "http"
 	HTTPSScheme URLScheme = "https"
 	TLSScheme   URLScheme = "tls"
 )
@@ -157,7 +368,8 @@ func (p URLScheme) String() string {
 	return string(p)
 }
 
-// SupportedURLSchemes returns an array of URLScheme currently supported by this provider.
+// SupportedURLSchemes returns an array of URLScheme currently supported by this provider
+// to fetch certificates from. Fetching CRLs also supports HTTPScheme.
 func SupportedURLSchemes() []URLScheme {
 	return []URLScheme{
 		HTTPSScheme,
@@ -174,3 +386,45 @@ func SupportedURLSchemesStr() []string {
 	}
 	return supportedStr
 }
+
+// PEMContentType represents the type of content expected in a PEM-formatted document.
+type PEMContentType string
+
+const (
+	PEMContentTypeCertificate PEMContentType = "certificate"
+	PEMContentTypePrivateKey  PEMContentType = "private_key"
+	PEMContentTypePublicKey   PEMContentType = "public_key"
+	PEMContentTypeCSR         PEMContentType = "csr"
+)
+
+func (t PEMContentType) String() string {
+	return string(t)
+}
+
+// SupportedPEMContentTypes returns an array of PEMContentType currently supported by this provider.
+func SupportedPEMContentTypes() []PEMContentType {
+	return []PEMContentType{
+		PEMContentTypeCertificate,
+		PEMContentTypePrivateKey,
+		PEMContentTypePublicKey,
+		PEMContentTypeCSR,
+	}
+}
+
+// SupportedPEMContentTypesStr returns the same content of SupportedPEMContentTypes but as a slice of string.
+func SupportedPEMContentTypesStr() []string {
+	supported := SupportedPEMContentTypes()
+	supportedStr := make([]string, len(supported))
+	for i := range supported {
+		supportedStr[i] = string(supported[i])
+	}
+	return supportedStr
+}
+
+// pemContentTypePreambles maps each PEMContentType to the PEMPreamble it accepts.
+var pemContentTypePreambles = map[PEMContentType][]PEMPreamble{
+	PEMContentTypeCertificate: {PreambleCertificate},
+	PEMContentTypePrivateKey:  {PreamblePrivateKeyRSA, PreamblePrivateKeyEC, PreamblePrivateKeyPKCS8},
+	PEMContentTypePublicKey:   {PreamblePublicKey},
+	PEMContentTypeCSR:         {PreambleCertificateRequest},
+}
@@ -2,13 +2,19 @@ package provider
 
 import (
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func decodePEM(d *schema.ResourceData, pemKey, pemType string) (*pem.Block, error) {
@@ -33,8 +39,104 @@ func hashForState(value string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// contentHash computes the hexadecimal representation of the SHA256 checksum of the given DER data.
+// This is exposed by resources as `content_hash`: being computed over the DER, rather than the PEM,
+// it only changes when the content itself changes.
+func contentHash(der []byte) string {
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:])
+}
+
 // overridableTimeFunc normally returns time.Now(),
 // but it is overridden during testing to simulate an arbitrary value of "now".
 var overridableTimeFunc = func() time.Time {
 	return time.Now()
 }
+
+// writeToSchema returns the schema.Schema for the `write_to` block,
+// to write the given PEM attribute to a file, with the given default permissions.
+func writeToSchema(pemKey, defaultPermissions string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"path": {
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+					Description:      "Path of the file to write. The directory containing it must already exist.",
+				},
+				"permissions": {
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: true,
+					Default:  defaultPermissions,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(
+						regexp.MustCompile(`^0?[0-7]{3}$`),
+						"must be a file mode in octal notation, like '0600'",
+					)),
+					Description: fmt.Sprintf("Permissions of the file, in octal notation (default: `%s`).", defaultPermissions),
+				},
+			},
+		},
+		Description: fmt.Sprintf("Write `%s` to a file, on the machine running `terraform apply`. ", pemKey) +
+			"The file is written atomically, via a temporary file in the same directory that is then renamed, " +
+			"so that it is never observed partially written nor with broader permissions than the given ones. " +
+			"As the content is only available when generated, the file is written only then: " +
+			"it is neither recreated if removed, nor deleted when the resource is destroyed.",
+	}
+}
+
+// writeToFile writes the given data to the file configured in the `write_to` block, if any.
+func writeToFile(d *schema.ResourceData, data []byte) error {
+	writeTo := d.Get("write_to").([]interface{})
+	if len(writeTo) == 0 || writeTo[0] == nil {
+		return nil
+	}
+	writeToMap := writeTo[0].(map[string]interface{})
+
+	// NOTE: the permissions are validated at the schema level
+	perm, err := strconv.ParseUint(writeToMap["permissions"].(string), 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid permissions %q: %w", writeToMap["permissions"], err)
+	}
+
+	return writeFileAtomically(writeToMap["path"].(string), data, os.FileMode(perm))
+}
+
+// writeFileAtomically writes the given data to a temporary file in the same directory as path,
+// with the given permissions, and then renames it to path: this way, a file at path is either
+// left untouched or fully replaced, and never has broader permissions than the given ones.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	// NOTE: the temporary file is created with `0600` permissions, regardless of the umask:
+	// these are only changed to the requested ones before any data is written
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}